@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchYears limita la búsqueda de la siguiente ejecución: una expresión válida
+// pero imposible (ej. 30 de febrero) no debe dejar el bucle girando indefinidamente.
+const cronSearchYears = 5
+
+// cronSchedule es una expresión cron estándar de cinco campos (minuto, hora, día del
+// mes, mes y día de la semana), el formato que acepta spec.schedule de un CronJob.
+type cronSchedule struct {
+	minutes  [60]bool
+	hours    [24]bool
+	days     [32]bool // índice 1-31
+	months   [13]bool // índice 1-12
+	weekdays [7]bool  // 0 = domingo
+	// domAny y dowAny indican que el campo es '*' o '?' sin paso mayor que 1: como en el
+	// parser del controlador de CronJobs, "*/2" cuenta como restringido y, si ambos lo
+	// están, basta con que coincida uno de los dos.
+	domAny bool
+	dowAny bool
+	// every es el intervalo fijo de "@every <duración>"; si no es cero, el resto de
+	// campos se ignora.
+	every time.Duration
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronWeekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// parseCronSchedule interpreta una expresión cron: cinco campos con '*', listas,
+// rangos, pasos y nombres de meses y días, o las macros @hourly, @daily, @weekly,
+// @monthly, @yearly y @every <duración>.
+func parseCronSchedule(expr string) (cronSchedule, error) {
+	var s cronSchedule
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil || d < time.Second {
+			return s, fmt.Errorf("duración de @every no válida en '%s'", expr)
+		}
+		s.every = d
+		return s, nil
+	}
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return s, fmt.Errorf("se esperaban 5 campos y '%s' tiene %d", expr, len(fields))
+	}
+	specs := []struct {
+		name     string
+		min, max int
+		names    map[string]int
+		set      []bool
+	}{
+		{"minuto", 0, 59, nil, s.minutes[:]},
+		{"hora", 0, 23, nil, s.hours[:]},
+		{"día del mes", 1, 31, nil, s.days[:]},
+		{"mes", 1, 12, cronMonthNames, s.months[:]},
+		{"día de la semana", 0, 7, cronWeekdayNames, nil},
+	}
+	var weekdays [8]bool
+	specs[4].set = weekdays[:]
+	for i, spec := range specs {
+		if err := parseCronField(fields[i], spec.min, spec.max, spec.names, spec.set); err != nil {
+			return s, fmt.Errorf("campo %s: %w", spec.name, err)
+		}
+	}
+	copy(s.weekdays[:], weekdays[:7])
+	if weekdays[7] {
+		s.weekdays[0] = true
+	}
+	s.domAny = cronFieldAny(fields[2])
+	s.dowAny = cronFieldAny(fields[4])
+	return s, nil
+}
+
+// cronFieldAny indica si el campo admite cualquier valor: '*' o '?', solos o con paso 1.
+func cronFieldAny(field string) bool {
+	switch field {
+	case "*", "?", "*/1", "?/1":
+		return true
+	}
+	return false
+}
+
+// parseCronField marca en set los valores de un campo separado por comas.
+func parseCronField(field string, min, max int, names map[string]int, set []bool) error {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("paso no válido en '%s'", part)
+			}
+			rangePart, step = part[:i], n
+		}
+		lo, hi := min, max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = cronValue(bounds[0], names); err != nil {
+				return err
+			}
+			if hi, err = cronValue(bounds[1], names); err != nil {
+				return err
+			}
+		default:
+			v, err := cronValue(rangePart, names)
+			if err != nil {
+				return err
+			}
+			lo, hi = v, v
+			if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return fmt.Errorf("'%s' fuera del rango %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return nil
+}
+
+func cronValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("valor no válido '%s'", s)
+	}
+	return v, nil
+}
+
+// dayMatches aplica la regla de cron para el día: si el día del mes y el de la semana
+// están restringidos, basta con que coincida cualquiera de los dos.
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	dom, dow := s.days[t.Day()], s.weekdays[t.Weekday()]
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// next devuelve la primera activación estrictamente posterior a t, en la zona horaria
+// de t, o el instante cero si no hay ninguna en los próximos cronSearchYears años.
+func (s cronSchedule) next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Truncate(time.Second).Add(s.every)
+	}
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + cronSearchYears
+	for t.Year() <= limit {
+		if !s.months[t.Month()] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !s.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// activationsBetween cuenta las activaciones en el intervalo (from, to], hasta max.
+// Devuelve también la última de ellas.
+func (s cronSchedule) activationsBetween(from, to time.Time, max int) (int, time.Time) {
+	count, last := 0, time.Time{}
+	for t := s.next(from); !t.IsZero() && !t.After(to) && count < max; t = s.next(t) {
+		count++
+		last = t
+	}
+	return count, last
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronScheduleNext(t *testing.T) {
+	from := time.Date(2026, 3, 14, 10, 17, 30, 0, time.UTC) // sábado
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 3, 15, 3, 0, 0, 0, time.UTC)},
+		{"30 9-17/4 * * mon-fri", time.Date(2026, 3, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)}, // día 13 o viernes
+		{"0 12 * JAN *", time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * */2", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)}, // día 1 o domingo, martes, jueves o sábado
+		{"0 0 1 * */1", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", time.Date(2026, 3, 14, 11, 47, 30, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := parseCronSchedule(tt.expr)
+		if err != nil {
+			t.Errorf("parseCronSchedule(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := s.next(from); !got.Equal(tt.want) {
+			t.Errorf("%q: next = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseCronScheduleInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "*/0 * * * *", "0 0 * foo *", "5-1 * * * *", "@every nunca"} {
+		if _, err := parseCronSchedule(expr); err == nil {
+			t.Errorf("parseCronSchedule(%q): expected error", expr)
+		}
+	}
+}
+
+func TestCronScheduleImpossibleDate(t *testing.T) {
+	s, err := parseCronSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("expected no activation for 30 February, got %s", got)
+	}
+}
+
+func TestCronScheduleActivationsBetween(t *testing.T) {
+	s, _ := parseCronSchedule("0 * * * *")
+	from := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	count, last := s.activationsBetween(from, from.Add(3*time.Hour+30*time.Minute), 100)
+	if count != 3 || !last.Equal(from.Add(3*time.Hour)) {
+		t.Errorf("got %d activations, last %s", count, last)
+	}
+	if count, _ := s.activationsBetween(from, from.Add(1000*time.Hour), 10); count != 10 {
+		t.Errorf("expected count capped at 10, got %d", count)
+	}
+}
@@ -1,23 +1,26 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/spf13/cobra"
 )
 
+var diagnoseNamespace string
+var diagnoseOutputFormat string
+
 // diagnoseCmd represents the diagnose command
 var diagnoseCmd = &cobra.Command{
 	Use:   "diagnose",
 	Short: "Ejecuta chequeos de diagnóstico",
-	Long: `Realiza pruebas básicas en Pods, Services e Ingresses para
+	Long: `Realiza pruebas sobre Pods, Services, Ingresses y otros recursos para
 detectar configuraciones erróneas o estados anómalos.
-Actualmente solo imprime un mensaje de prueba.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("diagnose called")
-	},
+
+Cada subcomando recopila la información relevante del clúster y devuelve una
+lista de hallazgos ordenados por severidad (critical, warning, info), cada uno
+con el siguiente paso sugerido.`,
 }
 
 func init() {
 	rootCmd.AddCommand(diagnoseCmd)
+	diagnoseCmd.PersistentFlags().StringVarP(&diagnoseNamespace, "namespace", "n", "", "Namespace del recurso a diagnosticar")
+	diagnoseCmd.PersistentFlags().StringVarP(&diagnoseOutputFormat, "output", "o", "", "Formato de salida. Soportado: json, yaml")
 }
@@ -0,0 +1,578 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var diagnoseClusterAllNamespaces bool
+var diagnoseClusterFailOn string
+
+// clusterDiagnosisSummary cuenta los hallazgos por severidad.
+type clusterDiagnosisSummary struct {
+	Critical int `json:"critical"`
+	Warning  int `json:"warning"`
+	Info     int `json:"info"`
+}
+
+// clusterDiagnosisScope cuenta los objetos revisados por cada diagnóstico.
+type clusterDiagnosisScope struct {
+	Pods        int `json:"pods"`
+	PendingPods int `json:"pendingPods"`
+	Services    int `json:"services"`
+	Ingresses   int `json:"ingresses"`
+	Deployments int `json:"deployments"`
+	Nodes       int `json:"nodes"`
+}
+
+// clusterDiagnosis es el informe de diagnose all.
+type clusterDiagnosis struct {
+	Namespace string                  `json:"namespace,omitempty"`
+	Summary   clusterDiagnosisSummary `json:"summary"`
+	Checked   clusterDiagnosisScope   `json:"checked"`
+	Findings  []finding               `json:"findings"`
+}
+
+// clusterInputs reúne lo que necesita analyzeCluster. Namespace vacío significa todo el
+// clúster. Los campos nil indican que ese recurso no se pudo listar y sus comprobaciones
+// se omiten.
+type clusterInputs struct {
+	Namespace        string
+	Snapshot         schedulingSnapshot
+	Services         []corev1.Service
+	EndpointSlices   []discoveryv1.EndpointSlice
+	Ingresses        []networkingv1.Ingress
+	IngressClasses   []networkingv1.IngressClass
+	AllIngresses     []networkingv1.Ingress
+	Secrets          map[string]corev1.Secret // por namespace/nombre
+	SecretErrors     map[string]error
+	Deployments      []appsv1.Deployment
+	ReplicaSets      []appsv1.ReplicaSet
+	PDBs             []policyv1.PodDisruptionBudget
+	Leases           map[string]*coordinationv1.Lease
+	SystemDaemonSets map[string]bool
+	NodeUsage        map[string]corev1.ResourceList
+	NodeEvents       []clusterEvent
+}
+
+var diagnoseClusterCmd = &cobra.Command{
+	Use:     "all",
+	Aliases: []string{"cluster"},
+	Short:   "Ejecuta todos los diagnósticos y genera un informe priorizado.",
+	Long: `El comando diagnose all ejecuta los diagnósticos de pods, pods Pending, services,
+ingresses, deployments y nodos sobre un namespace (-n) o todo el clúster (-A), elimina
+los hallazgos duplicados (por ejemplo, el mismo fallo en todas las réplicas de un
+ReplicaSet) y muestra un informe ordenado por severidad con un resumen.
+
+Con -n solo se revisan los nodos que alojan pods del namespace.
+
+Formatos de salida (-o): table (por defecto), json, yaml y markdown. Con --fail-on el
+comando termina con código de salida 2 si hay algún hallazgo critical (critical) o
+critical/warning (warning), lo que permite usarlo como control en CI.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputLower := strings.ToLower(diagnoseOutputFormat)
+		switch outputLower {
+		case "", "table", "json", "yaml", "markdown", "md":
+		default:
+			return fmt.Errorf("formato de salida no soportado '%s' (usa table, json, yaml o markdown)", diagnoseOutputFormat)
+		}
+		failOn := strings.ToLower(diagnoseClusterFailOn)
+		if failOn != "" && failOn != severityCritical && failOn != severityWarning {
+			return fmt.Errorf("valor de --fail-on no válido '%s' (usa critical o warning)", diagnoseClusterFailOn)
+		}
+		clients, err := GetKubeClients()
+		if err != nil {
+			return fmt.Errorf("creando clientes de Kubernetes: %w", err)
+		}
+		namespace := GetEffectiveNamespace(diagnoseNamespace, diagnoseClusterAllNamespaces, "default", false)
+		info := os.Stdout
+		if outputLower != "" && outputLower != "table" {
+			info = os.Stderr
+		}
+
+		if namespace == "" {
+			fmt.Fprintln(info, "Diagnosticando todo el clúster...")
+		} else {
+			fmt.Fprintf(info, "Diagnosticando namespace '%s'...\n", namespace)
+		}
+		in, err := collectClusterInputs(context.TODO(), clients, namespace)
+		if err != nil {
+			return err
+		}
+		diag := analyzeCluster(in, time.Now())
+
+		switch outputLower {
+		case "json", "yaml":
+			doc := struct {
+				outputHeader
+				clusterDiagnosis
+			}{newOutputHeader("ClusterDiagnosis"), diag}
+			if err := writeStructuredOutput(os.Stdout, outputLower, doc); err != nil {
+				return err
+			}
+		case "markdown", "md":
+			writeClusterDiagnosisMarkdown(os.Stdout, diag, time.Now())
+		default:
+			printClusterDiagnosis(diag)
+		}
+
+		if failOnTriggered(diag.Summary, failOn) {
+			fmt.Fprintf(os.Stderr, "Error: hay hallazgos de severidad %s o superior (--fail-on=%s).\n", failOn, failOn)
+			os.Exit(2)
+		}
+		return nil
+	},
+}
+
+func init() {
+	diagnoseCmd.AddCommand(diagnoseClusterCmd)
+	diagnoseClusterCmd.Flags().BoolVarP(&diagnoseClusterAllNamespaces, "all-namespaces", "A", false, "Diagnosticar todos los namespaces")
+	diagnoseClusterCmd.Flags().StringVar(&diagnoseClusterFailOn, "fail-on", "", "Terminar con código 2 si hay hallazgos de esta severidad o superior: critical o warning")
+}
+
+// collectClusterInputs lista de una vez todo lo que necesitan los diagnósticos. Solo
+// falla si no se pueden listar los nodos o los pods.
+func collectClusterInputs(ctx context.Context, clients *KubeClients, namespace string) (clusterInputs, error) {
+	clientset := clients.Core
+	snap, err := collectSchedulingSnapshot(ctx, clientset, namespace)
+	if err != nil {
+		return clusterInputs{}, err
+	}
+	in := clusterInputs{Namespace: namespace, Snapshot: snap}
+	warn := func(resource string, err error) {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar %s: %s\n", resource, describeCollectError(err))
+	}
+
+	if list, err := clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("services", err)
+	} else {
+		in.Services = append([]corev1.Service{}, list.Items...)
+	}
+	if list, err := clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("endpointslices", err)
+	} else {
+		in.EndpointSlices = append([]discoveryv1.EndpointSlice{}, list.Items...)
+	}
+	if list, err := clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("ingresses", err)
+	} else {
+		in.Ingresses = append([]networkingv1.Ingress{}, list.Items...)
+	}
+	if len(in.Ingresses) > 0 {
+		if list, err := clientset.NetworkingV1().IngressClasses().List(ctx, metav1.ListOptions{}); err != nil {
+			warn("ingressclasses", err)
+		} else {
+			in.IngressClasses = append([]networkingv1.IngressClass{}, list.Items...)
+		}
+		in.AllIngresses = in.Ingresses
+		if namespace != "" {
+			if list, err := clientset.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{}); err != nil {
+				warn("ingresses de todos los namespaces", err)
+				in.AllIngresses = nil
+			} else {
+				in.AllIngresses = list.Items
+			}
+		}
+		in.Secrets = map[string]corev1.Secret{}
+		in.SecretErrors = map[string]error{}
+		for _, ing := range in.Ingresses {
+			for _, tls := range ing.Spec.TLS {
+				key := ing.Namespace + "/" + tls.SecretName
+				if _, seen := in.Secrets[key]; tls.SecretName == "" || seen || in.SecretErrors[key] != nil {
+					continue
+				}
+				if secret, err := clientset.CoreV1().Secrets(ing.Namespace).Get(ctx, tls.SecretName, metav1.GetOptions{}); err != nil {
+					in.SecretErrors[key] = err
+				} else {
+					in.Secrets[key] = *secret
+				}
+			}
+		}
+	}
+	if list, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("deployments", err)
+	} else {
+		in.Deployments = append([]appsv1.Deployment{}, list.Items...)
+	}
+	if len(in.Deployments) > 0 {
+		if list, err := clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+			warn("replicasets", err)
+		} else {
+			in.ReplicaSets = list.Items
+		}
+		if list, err := clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+			warn("poddisruptionbudgets", err)
+		} else {
+			in.PDBs = list.Items
+		}
+	}
+
+	if list, err := clientset.CoordinationV1().Leases(nodeLeaseNamespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("leases de los nodos", err)
+	} else {
+		in.Leases = map[string]*coordinationv1.Lease{}
+		for i := range list.Items {
+			in.Leases[list.Items[i].Name] = &list.Items[i]
+		}
+	}
+	if list, err := clientset.AppsV1().DaemonSets("kube-system").List(ctx, metav1.ListOptions{}); err != nil {
+		warn("daemonsets de kube-system", err)
+	} else {
+		in.SystemDaemonSets = map[string]bool{}
+		for _, ds := range list.Items {
+			in.SystemDaemonSets[ds.Name] = true
+		}
+	}
+	if clients.Metrics != nil {
+		if list, err := clients.Metrics.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{}); err == nil {
+			in.NodeUsage = map[string]corev1.ResourceList{}
+			for _, m := range list.Items {
+				in.NodeUsage[m.Name] = m.Usage
+			}
+		} else if Verbose {
+			fmt.Fprintf(os.Stderr, "DEBUG: No se pudieron obtener métricas de nodos: %v\n", err)
+		}
+	}
+	// Los eventos de los nodos se registran en el namespace default.
+	in.NodeEvents = snap.Events
+	if namespace != "" && namespace != metav1.NamespaceDefault {
+		if in.NodeEvents, err = listClusterEvents(clientset, metav1.NamespaceDefault); err != nil {
+			warn("eventos de los nodos", err)
+		}
+	}
+	return in, nil
+}
+
+// analyzeCluster ejecuta los diagnósticos sobre los objetos del ámbito y resume los
+// hallazgos sin duplicados.
+func analyzeCluster(in clusterInputs, now time.Time) clusterDiagnosis {
+	d := clusterDiagnosis{Namespace: in.Namespace}
+	snap := in.Snapshot
+	inScope := func(namespace string) bool { return in.Namespace == "" || namespace == in.Namespace }
+	var findings []finding
+	// groups asocia el objeto de cada hallazgo de pod con su controlador, para agrupar
+	// el mismo hallazgo en todas las réplicas.
+	groups := map[string]string{}
+
+	nodesByName := map[string]*corev1.Node{}
+	for i := range snap.Nodes {
+		nodesByName[snap.Nodes[i].Name] = &snap.Nodes[i]
+	}
+	podsByNamespace := map[string][]corev1.Pod{}
+	scopeNodes := map[string]bool{}
+	for _, p := range snap.Pods {
+		if !inScope(p.Namespace) {
+			continue
+		}
+		podsByNamespace[p.Namespace] = append(podsByNamespace[p.Namespace], p)
+		if p.Spec.NodeName != "" && !isTerminatedPod(p) {
+			scopeNodes[p.Spec.NodeName] = true
+		}
+	}
+
+	// Con -A los eventos son de todos los namespaces y los analizadores los relacionan por
+	// tipo y nombre: cada uno recibe solo los de su namespace.
+	eventsByNamespace := map[string][]clusterEvent{}
+	for _, e := range snap.Events {
+		eventsByNamespace[e.Namespace] = append(eventsByNamespace[e.Namespace], e)
+	}
+
+	// Pods: los Pending sin nodo los explica el diagnóstico de scheduling; del resto solo
+	// se analizan los que no están listos o se han reiniciado.
+	for _, pods := range podsByNamespace {
+		for _, p := range pods {
+			if p.DeletionTimestamp != nil || p.Status.Phase == corev1.PodSucceeded {
+				continue
+			}
+			object := fmt.Sprintf("Pod %s/%s", p.Namespace, p.Name)
+			if owner := metav1.GetControllerOf(&p); owner != nil {
+				groups[object] = fmt.Sprintf("%s %s/%s", owner.Kind, p.Namespace, owner.Name)
+			}
+			if isUnscheduledPod(p) {
+				d.Checked.PendingPods++
+				findings = append(findings, analyzePendingPod(p, snap, now).Findings...)
+				continue
+			}
+			restarted := false
+			for _, cs := range p.Status.ContainerStatuses {
+				restarted = restarted || cs.RestartCount > 0
+			}
+			d.Checked.Pods++
+			if isPodReady(p) && !restarted {
+				continue
+			}
+			findings = append(findings, analyzePod(p, podEvents(p, eventsByNamespace[p.Namespace]), nodesByName[p.Spec.NodeName], nil)...)
+		}
+	}
+
+	// Services.
+	var slicesByService map[string][]discoveryv1.EndpointSlice
+	if in.EndpointSlices != nil {
+		slicesByService = map[string][]discoveryv1.EndpointSlice{}
+		for _, s := range in.EndpointSlices {
+			key := s.Namespace + "/" + s.Labels[discoveryv1.LabelServiceName]
+			slicesByService[key] = append(slicesByService[key], s)
+		}
+	}
+	servicesByNamespace := map[string]map[string]corev1.Service{}
+	for _, svc := range in.Services {
+		if servicesByNamespace[svc.Namespace] == nil {
+			servicesByNamespace[svc.Namespace] = map[string]corev1.Service{}
+		}
+		servicesByNamespace[svc.Namespace][svc.Name] = svc
+		var slices []discoveryv1.EndpointSlice
+		if slicesByService != nil {
+			slices = append([]discoveryv1.EndpointSlice{}, slicesByService[svc.Namespace+"/"+svc.Name]...)
+		}
+		d.Checked.Services++
+		findings = append(findings, analyzeService(svc, podsByNamespace[svc.Namespace], slices, eventsByNamespace[svc.Namespace], now).Findings...)
+	}
+
+	// Ingresses.
+	for _, ing := range in.Ingresses {
+		ingIn := ingressInputs{
+			Ingress:      ing,
+			Classes:      in.IngressClasses,
+			AllIngresses: in.AllIngresses,
+			Events:       eventsByNamespace[ing.Namespace],
+			Secrets:      map[string]corev1.Secret{},
+			SecretErrors: map[string]error{},
+		}
+		if _, controller := resolveIngressClass(ing, in.IngressClasses); controller != "" {
+			if selector, ok := ingressControllerSelectors[controller]; ok {
+				ingIn.ControllerPods = podsMatchingSelector(snap.Pods, selector)
+			}
+		}
+		if in.Services != nil {
+			ingIn.Services = servicesByNamespace[ing.Namespace]
+			if ingIn.Services == nil {
+				ingIn.Services = map[string]corev1.Service{}
+			}
+		}
+		if slicesByService != nil {
+			ingIn.EndpointSlices = map[string][]discoveryv1.EndpointSlice{}
+			for key, slices := range slicesByService {
+				if ns, svc, _ := strings.Cut(key, "/"); ns == ing.Namespace {
+					ingIn.EndpointSlices[svc] = slices
+				}
+			}
+		}
+		for _, tls := range ing.Spec.TLS {
+			key := ing.Namespace + "/" + tls.SecretName
+			if secret, ok := in.Secrets[key]; ok {
+				ingIn.Secrets[tls.SecretName] = secret
+			} else if err := in.SecretErrors[key]; err != nil {
+				ingIn.SecretErrors[tls.SecretName] = err
+			}
+		}
+		d.Checked.Ingresses++
+		findings = append(findings, analyzeIngress(ingIn, now).Findings...)
+	}
+
+	// Deployments.
+	for _, deploy := range in.Deployments {
+		selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		var pods []corev1.Pod
+		for _, p := range podsByNamespace[deploy.Namespace] {
+			if selector.Matches(labels.Set(p.Labels)) {
+				pods = append(pods, p)
+			}
+		}
+		d.Checked.Deployments++
+		findings = append(findings, analyzeDeployment(deploy, in.ReplicaSets, pods, in.PDBs, eventsByNamespace[deploy.Namespace]).Findings...)
+	}
+
+	// Nodos: todos con -A; con -n, los que alojan pods del namespace.
+	podsByNode := map[string][]corev1.Pod{}
+	for _, p := range snap.Pods {
+		podsByNode[p.Spec.NodeName] = append(podsByNode[p.Spec.NodeName], p)
+	}
+	for _, node := range snap.Nodes {
+		if in.Namespace != "" && !scopeNodes[node.Name] {
+			continue
+		}
+		nodeIn := nodeDiagnosisInputs{
+			Node:             node,
+			Pods:             podsByNode[node.Name],
+			SystemDaemonSets: in.SystemDaemonSets,
+			Usage:            in.NodeUsage[node.Name],
+			Events:           dedupeEvents(filterEvents(in.NodeEvents, eventFilter{ForKind: "Node", ForName: node.Name}, now)),
+		}
+		if in.Leases != nil {
+			nodeIn.Lease = in.Leases[node.Name]
+		}
+		d.Checked.Nodes++
+		findings = append(findings, analyzeNode(nodeIn, now).Findings...)
+	}
+
+	sortFindings(findings)
+	d.Findings = dedupeFindings(findings, groups)
+	for _, f := range d.Findings {
+		switch f.Severity {
+		case severityCritical:
+			d.Summary.Critical++
+		case severityWarning:
+			d.Summary.Warning++
+		default:
+			d.Summary.Info++
+		}
+	}
+	return d
+}
+
+// podsMatchingSelector devuelve los pods que cumplen un selector de etiquetas en texto.
+func podsMatchingSelector(pods []corev1.Pod, selector string) []corev1.Pod {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil
+	}
+	result := []corev1.Pod{}
+	for _, p := range pods {
+		if sel.Matches(labels.Set(p.Labels)) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// dedupeFindings elimina los hallazgos repetidos: el mismo check con el mismo mensaje
+// sobre el mismo objeto se reporta una sola vez, y el mismo check sobre objetos distintos
+// del mismo grupo (las réplicas de un ReplicaSet) se fusiona indicando cuántos objetos lo
+// comparten. Los hallazgos distintos de un mismo check sobre un objeto (un Service
+// ausente por backend, un puerto por targetPort) se conservan. Espera los hallazgos ya
+// ordenados, de forma que se conserva el más grave.
+func dedupeFindings(findings []finding, groups map[string]string) []finding {
+	type entry struct {
+		index   int
+		group   string
+		objects map[string]bool
+	}
+	result := []finding{}
+	exact := map[string]bool{}
+	perObject := map[string]int{}
+	seen := map[string]*entry{}
+	var order []*entry
+	for _, f := range findings {
+		exactKey := f.Check + "|" + f.Object + "|" + f.Message
+		if exact[exactKey] {
+			continue
+		}
+		exact[exactKey] = true
+		group := f.Object
+		if g, ok := groups[f.Object]; ok {
+			group = g
+		}
+		// El n-ésimo hallazgo de un check en un objeto solo se fusiona con el n-ésimo
+		// del mismo check en otro objeto del grupo.
+		ordinal := perObject[f.Check+"|"+f.Object]
+		perObject[f.Check+"|"+f.Object]++
+		key := fmt.Sprintf("%s|%s|%d", f.Check, group, ordinal)
+		if e, ok := seen[key]; ok {
+			e.objects[f.Object] = true
+			continue
+		}
+		e := &entry{index: len(result), group: group, objects: map[string]bool{f.Object: true}}
+		seen[key] = e
+		order = append(order, e)
+		result = append(result, f)
+	}
+	for _, e := range order {
+		if n := len(e.objects); n > 1 {
+			result[e.index].Message += fmt.Sprintf(" (mismo hallazgo en %d objetos de %s)", n, e.group)
+		}
+	}
+	return result
+}
+
+// failOnTriggered indica si los hallazgos alcanzan la severidad de --fail-on.
+func failOnTriggered(s clusterDiagnosisSummary, failOn string) bool {
+	switch failOn {
+	case severityCritical:
+		return s.Critical > 0
+	case severityWarning:
+		return s.Critical > 0 || s.Warning > 0
+	}
+	return false
+}
+
+// clusterScopeLabel describe el ámbito del informe.
+func clusterScopeLabel(namespace string) string {
+	if namespace == "" {
+		return "todos los namespaces"
+	}
+	return "namespace " + namespace
+}
+
+// printClusterDiagnosis imprime el informe de diagnose all como tablas.
+func printClusterDiagnosis(d clusterDiagnosis) {
+	fmt.Fprintf(os.Stdout, "\n--- Resumen (%s) ---\n", clusterScopeLabel(d.Namespace))
+	PrintBasicTable([]string{"CRITICAL", "WARNING", "INFO", "PODS", "PENDING", "SERVICES", "INGRESSES", "DEPLOYMENTS", "NODOS"}, [][]string{{
+		colorize(fmt.Sprintf("%d", d.Summary.Critical), severityColor(severityCritical)),
+		colorize(fmt.Sprintf("%d", d.Summary.Warning), severityColor(severityWarning)),
+		fmt.Sprintf("%d", d.Summary.Info),
+		fmt.Sprintf("%d", d.Checked.Pods), fmt.Sprintf("%d", d.Checked.PendingPods), fmt.Sprintf("%d", d.Checked.Services),
+		fmt.Sprintf("%d", d.Checked.Ingresses), fmt.Sprintf("%d", d.Checked.Deployments), fmt.Sprintf("%d", d.Checked.Nodes),
+	}})
+
+	fmt.Fprintln(os.Stdout, "--- Hallazgos ---")
+	if len(d.Findings) == 0 {
+		fmt.Fprintln(os.Stdout, colorize("No se detectaron problemas.", colorGreen))
+		return
+	}
+	rows := make([][]string, 0, len(d.Findings))
+	for i, f := range d.Findings {
+		rows = append(rows, []string{fmt.Sprintf("%d", i+1), strings.ToUpper(f.Severity), f.Check, f.Object, truncateString(f.Message, 100)})
+	}
+	PrintBasicTable([]string{"#", "SEVERIDAD", "CHECK", "OBJETO", "MENSAJE"}, rows)
+	fmt.Fprintln(os.Stdout, "Usa -o markdown o -o json para ver los mensajes completos y el siguiente paso de cada hallazgo.")
+}
+
+// markdownCell escapa el texto para una celda de tabla Markdown.
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// writeClusterDiagnosisMarkdown escribe el informe de diagnose all en Markdown, listo
+// para adjuntarlo a una incidencia o a un pull request.
+func writeClusterDiagnosisMarkdown(w io.Writer, d clusterDiagnosis, now time.Time) {
+	fmt.Fprintln(w, "# Informe de diagnóstico de eks-review")
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Ámbito: %s. Generado: %s.\n\n", clusterScopeLabel(d.Namespace), now.UTC().Format(time.RFC3339))
+	fmt.Fprintln(w, "## Resumen")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Severidad | Hallazgos |")
+	fmt.Fprintln(w, "|-----------|-----------|")
+	fmt.Fprintf(w, "| critical | %d |\n| warning | %d |\n| info | %d |\n\n", d.Summary.Critical, d.Summary.Warning, d.Summary.Info)
+	fmt.Fprintf(w, "Revisados: %d pods, %d pods Pending, %d services, %d ingresses, %d deployments y %d nodos.\n\n",
+		d.Checked.Pods, d.Checked.PendingPods, d.Checked.Services, d.Checked.Ingresses, d.Checked.Deployments, d.Checked.Nodes)
+	fmt.Fprintln(w, "## Hallazgos")
+	fmt.Fprintln(w)
+	if len(d.Findings) == 0 {
+		fmt.Fprintln(w, "No se detectaron problemas.")
+		return
+	}
+	fmt.Fprintln(w, "| # | Severidad | Check | Objeto | Mensaje | Siguiente paso |")
+	fmt.Fprintln(w, "|---|-----------|-------|--------|---------|----------------|")
+	for i, f := range d.Findings {
+		fmt.Fprintf(w, "| %d | %s | `%s` | %s | %s | %s |\n", i+1, f.Severity, f.Check,
+			markdownCell(f.Object), markdownCell(f.Message), markdownCell(f.Suggestion))
+	}
+}
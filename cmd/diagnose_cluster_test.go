@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testClusterInputs(pods ...corev1.Pod) clusterInputs {
+	nodes := []corev1.Node{testSchedulingNode("node-a", "us-east-1a", "2")}
+	return clusterInputs{
+		Namespace:      "prod",
+		Snapshot:       newSchedulingSnapshot(nodes, pods, []corev1.PersistentVolumeClaim{}, nil, nil, nil),
+		Services:       []corev1.Service{},
+		EndpointSlices: []discoveryv1.EndpointSlice{},
+	}
+}
+
+func TestAnalyzeClusterDedupesReplicas(t *testing.T) {
+	controller := true
+	owner := []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-5d8f", Controller: &controller}}
+	var pods []corev1.Pod
+	for _, name := range []string{"api-5d8f-a", "api-5d8f-b", "api-5d8f-c"} {
+		p := testCrashingPod("OOMKilled", 137)
+		p.Name = name
+		p.Spec.NodeName = "node-a"
+		p.OwnerReferences = owner
+		pods = append(pods, p)
+	}
+	pending := testPendingPod("big", "8")
+	pods = append(pods, pending)
+	other := testRunningPod("other", "node-a", "100m")
+	other.Namespace = "staging"
+	pods = append(pods, other)
+
+	d := analyzeCluster(testClusterInputs(pods...), time.Now())
+	if d.Checked.Pods != 3 || d.Checked.PendingPods != 1 || d.Checked.Nodes != 1 {
+		t.Errorf("unexpected scope: %+v", d.Checked)
+	}
+	oom := 0
+	for _, f := range d.Findings {
+		if f.Check == "container.oomkilled" {
+			oom++
+			if !strings.Contains(f.Message, "3 objetos de ReplicaSet prod/api-5d8f") {
+				t.Errorf("expected grouped message, got %q", f.Message)
+			}
+		}
+	}
+	if oom != 1 {
+		t.Errorf("expected 1 grouped oomkilled finding, got %d in %v", oom, findingChecks(d.Findings))
+	}
+	if !strings.Contains(strings.Join(findingChecks(d.Findings), ","), "pending.insufficient-cpu") {
+		t.Errorf("expected pending finding, got %v", findingChecks(d.Findings))
+	}
+	if d.Summary.Critical == 0 || d.Summary.Critical+d.Summary.Warning+d.Summary.Info != len(d.Findings) {
+		t.Errorf("summary does not match findings: %+v", d.Summary)
+	}
+}
+
+func TestAnalyzeClusterEventsStayInNamespace(t *testing.T) {
+	var pods []corev1.Pod
+	for _, ns := range []string{"prod", "staging"} {
+		p := testCrashingPod("Error", 1)
+		p.Namespace, p.Name, p.Spec.NodeName = ns, "postgres-0", "node-a"
+		pods = append(pods, p)
+	}
+	events := []clusterEvent{{Kind: "Pod", Namespace: "staging", Name: "postgres-0", Reason: "FailedMount", Message: "MountVolume.SetUp failed for volume \"data\"", Count: 3}}
+	in := testClusterInputs()
+	in.Namespace = ""
+	in.Snapshot = newSchedulingSnapshot([]corev1.Node{testSchedulingNode("node-a", "us-east-1a", "2")}, pods, []corev1.PersistentVolumeClaim{}, nil, nil, events)
+
+	var volume []string
+	for _, f := range analyzeCluster(in, time.Now()).Findings {
+		if f.Check == "pod.volume" {
+			volume = append(volume, f.Object)
+		}
+	}
+	if len(volume) != 1 || volume[0] != "Pod staging/postgres-0" {
+		t.Errorf("expected pod.volume only on the staging pod, got %v", volume)
+	}
+}
+
+func TestDedupeFindings(t *testing.T) {
+	findings := []finding{
+		{Severity: severityCritical, Check: "node.not-ready", Object: "Node a", Message: "down"},
+		{Severity: severityWarning, Check: "node.not-ready", Object: "Node a", Message: "down"},
+		{Severity: severityWarning, Check: "node.not-ready", Object: "Node b", Message: "down"},
+	}
+	got := dedupeFindings(findings, nil)
+	if len(got) != 2 || got[0].Severity != severityCritical || got[0].Message != "down" {
+		t.Errorf("unexpected dedupe result: %+v", got)
+	}
+}
+
+func TestDedupeFindingsKeepsDistinctOnSameObject(t *testing.T) {
+	findings := []finding{
+		{Severity: severityCritical, Check: "ingress.backend-missing", Object: "Ingress prod/web", Message: "El Service 'api' no existe."},
+		{Severity: severityCritical, Check: "ingress.backend-missing", Object: "Ingress prod/web", Message: "El Service 'auth' no existe."},
+	}
+	got := dedupeFindings(findings, nil)
+	if len(got) != 2 || got[0].Message != findings[0].Message || got[1].Message != findings[1].Message {
+		t.Errorf("expected both findings unchanged, got %+v", got)
+	}
+
+	// Dos contenedores con OOMKilled en cada réplica: un hallazgo por contenedor, fusionado entre réplicas.
+	groups := map[string]string{"Pod prod/api-a": "ReplicaSet prod/api", "Pod prod/api-b": "ReplicaSet prod/api"}
+	findings = []finding{
+		{Severity: severityCritical, Check: "container.oomkilled", Object: "Pod prod/api-a", Message: "app"},
+		{Severity: severityCritical, Check: "container.oomkilled", Object: "Pod prod/api-a", Message: "sidecar"},
+		{Severity: severityCritical, Check: "container.oomkilled", Object: "Pod prod/api-b", Message: "app"},
+		{Severity: severityCritical, Check: "container.oomkilled", Object: "Pod prod/api-b", Message: "sidecar"},
+	}
+	got = dedupeFindings(findings, groups)
+	if len(got) != 2 || got[0].Message != "app (mismo hallazgo en 2 objetos de ReplicaSet prod/api)" || got[1].Message != "sidecar (mismo hallazgo en 2 objetos de ReplicaSet prod/api)" {
+		t.Errorf("unexpected grouped result: %+v", got)
+	}
+}
+
+func TestFailOnTriggered(t *testing.T) {
+	warningsOnly := clusterDiagnosisSummary{Warning: 2, Info: 1}
+	if failOnTriggered(warningsOnly, severityCritical) {
+		t.Error("critical threshold should not trigger on warnings")
+	}
+	if !failOnTriggered(warningsOnly, severityWarning) {
+		t.Error("warning threshold should trigger on warnings")
+	}
+	if failOnTriggered(clusterDiagnosisSummary{Critical: 1}, "") {
+		t.Error("empty --fail-on should never trigger")
+	}
+}
+
+func TestWriteClusterDiagnosisMarkdown(t *testing.T) {
+	d := clusterDiagnosis{
+		Namespace: "prod",
+		Summary:   clusterDiagnosisSummary{Warning: 1},
+		Findings: []finding{{Severity: severityWarning, Check: "service.no-pods", Object: "Service prod/api",
+			Message: "selector app=api|v2 no coincide", Suggestion: "Revisa el selector."}},
+	}
+	var buf bytes.Buffer
+	writeClusterDiagnosisMarkdown(&buf, d, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	out := buf.String()
+	for _, want := range []string{"Ámbito: namespace prod. Generado: 2026-01-02T03:04:05Z.", "| warning | 1 |", "| 1 | warning | `service.no-pods` | Service prod/api | selector app=api\\|v2 no coincide | Revisa el selector. |"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const revisionAnnotation = "deployment.kubernetes.io/revision"
+
+// revisionSummary describe un ReplicaSet de una revisión del Deployment.
+type revisionSummary struct {
+	ReplicaSet string    `json:"replicaSet"`
+	Revision   int64     `json:"revision"`
+	Images     []string  `json:"images"`
+	Replicas   int32     `json:"replicas"`
+	Ready      int32     `json:"ready"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// failingPod es un pod de la revisión nueva que no está listo.
+type failingPod struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// pdbSummary es un PodDisruptionBudget que cubre los pods del Deployment.
+type pdbSummary struct {
+	Name               string `json:"name"`
+	MinAvailable       string `json:"minAvailable,omitempty"`
+	MaxUnavailable     string `json:"maxUnavailable,omitempty"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+}
+
+// deploymentDiagnosis es el informe de diagnose deployment.
+type deploymentDiagnosis struct {
+	Namespace               string           `json:"namespace"`
+	Deployment              string           `json:"deployment"`
+	Desired                 int32            `json:"desired"`
+	Updated                 int32            `json:"updated"`
+	Ready                   int32            `json:"ready"`
+	Available               int32            `json:"available"`
+	Strategy                string           `json:"strategy"`
+	MaxSurge                int              `json:"maxSurge"`
+	MaxUnavailable          int              `json:"maxUnavailable"`
+	Paused                  bool             `json:"paused"`
+	ProgressDeadlineSeconds int32            `json:"progressDeadlineSeconds"`
+	ProgressingReason       string           `json:"progressingReason,omitempty"`
+	ProgressingMessage      string           `json:"progressingMessage,omitempty"`
+	Current                 *revisionSummary `json:"currentRevision,omitempty"`
+	Previous                *revisionSummary `json:"previousRevision,omitempty"`
+	FailingPods             []failingPod     `json:"failingPods"`
+	PDBs                    []pdbSummary     `json:"podDisruptionBudgets"`
+	Findings                []finding        `json:"findings"`
+}
+
+var diagnoseDeploymentCmd = &cobra.Command{
+	Use:     "deployment <nombre-del-deployment>",
+	Aliases: []string{"deploy"},
+	Short:   "Detecta rollouts atascados o fallidos de un Deployment.",
+	Long: `El comando diagnose deployment compara el ReplicaSet actual con el anterior, lee
+la condición Progressing y progressDeadlineSeconds, muestra qué pods de la revisión
+nueva fallan y por qué, comprueba si la estrategia (maxUnavailable/maxSurge) o los
+PodDisruptionBudgets bloquean el despliegue y recomienda un rollback cuando la
+revisión nueva entra en CrashLoopBackOff.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clients, err := GetKubeClients()
+		if err != nil {
+			return fmt.Errorf("creando clientes de Kubernetes: %w", err)
+		}
+		namespace := GetEffectiveNamespace(diagnoseNamespace, false, "default", false)
+		outputLower := strings.ToLower(diagnoseOutputFormat)
+		info := os.Stdout
+		if isStructuredOutput(outputLower) {
+			info = os.Stderr
+		}
+
+		fmt.Fprintf(info, "Diagnosticando deployment '%s' en namespace '%s'...\n", args[0], namespace)
+		diag, err := collectDeploymentDiagnosis(context.TODO(), clients.Core, namespace, args[0])
+		if err != nil {
+			return err
+		}
+
+		if isStructuredOutput(outputLower) {
+			doc := struct {
+				outputHeader
+				deploymentDiagnosis
+			}{newOutputHeader("DeploymentDiagnosis"), diag}
+			return writeStructuredOutput(os.Stdout, outputLower, doc)
+		}
+		printDeploymentDiagnosis(diag)
+		return nil
+	},
+}
+
+func init() {
+	diagnoseCmd.AddCommand(diagnoseDeploymentCmd)
+}
+
+// collectDeploymentDiagnosis recupera el Deployment, sus ReplicaSets, sus pods, los PDB
+// y los eventos del namespace.
+func collectDeploymentDiagnosis(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (deploymentDiagnosis, error) {
+	deploy, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return deploymentDiagnosis{}, fmt.Errorf("obteniendo deployment '%s' en namespace '%s': %w", name, namespace, err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return deploymentDiagnosis{}, fmt.Errorf("selector del deployment no válido: %w", err)
+	}
+	rsList, err := clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return deploymentDiagnosis{}, fmt.Errorf("listando replicasets: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return deploymentDiagnosis{}, fmt.Errorf("listando pods: %w", err)
+	}
+	var pdbs []policyv1.PodDisruptionBudget
+	if list, err := clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar poddisruptionbudgets: %s\n", describeCollectError(err))
+	} else {
+		pdbs = list.Items
+	}
+	events, err := listClusterEvents(clientset, namespace)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar eventos: %s\n", describeCollectError(err))
+	}
+	return analyzeDeployment(*deploy, rsList.Items, pods.Items, pdbs, events), nil
+}
+
+// ownedReplicaSets devuelve los ReplicaSets del Deployment ordenados de la revisión más
+// reciente a la más antigua.
+func ownedReplicaSets(deploy appsv1.Deployment, replicaSets []appsv1.ReplicaSet) []appsv1.ReplicaSet {
+	var owned []appsv1.ReplicaSet
+	for _, rs := range replicaSets {
+		if ref := metav1.GetControllerOf(&rs); ref != nil && ref.UID == deploy.UID {
+			owned = append(owned, rs)
+		}
+	}
+	sort.SliceStable(owned, func(i, j int) bool { return replicaSetRevision(owned[i]) > replicaSetRevision(owned[j]) })
+	return owned
+}
+
+func replicaSetRevision(rs appsv1.ReplicaSet) int64 {
+	rev, _ := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+	return rev
+}
+
+func newRevisionSummary(rs appsv1.ReplicaSet) *revisionSummary {
+	s := &revisionSummary{
+		ReplicaSet: rs.Name,
+		Revision:   replicaSetRevision(rs),
+		Images:     []string{},
+		Replicas:   rs.Status.Replicas,
+		Ready:      rs.Status.ReadyReplicas,
+		CreatedAt:  rs.CreationTimestamp.UTC(),
+	}
+	for _, c := range rs.Spec.Template.Spec.Containers {
+		s.Images = append(s.Images, c.Image)
+	}
+	return s
+}
+
+// rollingUpdateLimits resuelve maxSurge (redondeando hacia arriba) y maxUnavailable
+// (hacia abajo) como hace el controlador de Deployments; por defecto ambos son 25%.
+func rollingUpdateLimits(deploy appsv1.Deployment) (surge, unavailable int) {
+	replicas := int(replicasOrDefault(deploy.Spec.Replicas))
+	defaultValue := intstr.FromString("25%")
+	maxSurge, maxUnavailable := &defaultValue, &defaultValue
+	if ru := deploy.Spec.Strategy.RollingUpdate; ru != nil {
+		if ru.MaxSurge != nil {
+			maxSurge = ru.MaxSurge
+		}
+		if ru.MaxUnavailable != nil {
+			maxUnavailable = ru.MaxUnavailable
+		}
+	}
+	surge, _ = intstr.GetScaledValueFromIntOrPercent(maxSurge, replicas, true)
+	unavailable, _ = intstr.GetScaledValueFromIntOrPercent(maxUnavailable, replicas, false)
+	// Si ambos se redondean a 0, el controlador usa maxUnavailable=1 para poder avanzar.
+	if surge == 0 && unavailable == 0 {
+		unavailable = 1
+	}
+	return surge, unavailable
+}
+
+// isCrashLooping indica si algún contenedor del pod está en CrashLoopBackOff.
+func isCrashLooping(pod corev1.Pod) bool {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeDeployment aplica las comprobaciones de diagnose deployment.
+func analyzeDeployment(deploy appsv1.Deployment, replicaSets []appsv1.ReplicaSet, pods []corev1.Pod, pdbs []policyv1.PodDisruptionBudget, events []clusterEvent) deploymentDiagnosis {
+	d := deploymentDiagnosis{
+		Namespace:   deploy.Namespace,
+		Deployment:  deploy.Name,
+		Desired:     replicasOrDefault(deploy.Spec.Replicas),
+		Updated:     deploy.Status.UpdatedReplicas,
+		Ready:       deploy.Status.ReadyReplicas,
+		Available:   deploy.Status.AvailableReplicas,
+		Strategy:    string(deploy.Spec.Strategy.Type),
+		Paused:      deploy.Spec.Paused,
+		FailingPods: []failingPod{},
+		PDBs:        []pdbSummary{},
+	}
+	if d.Strategy == "" {
+		d.Strategy = string(appsv1.RollingUpdateDeploymentStrategyType)
+	}
+	d.ProgressDeadlineSeconds = 600
+	if deploy.Spec.ProgressDeadlineSeconds != nil {
+		d.ProgressDeadlineSeconds = *deploy.Spec.ProgressDeadlineSeconds
+	}
+	if d.Strategy == string(appsv1.RollingUpdateDeploymentStrategyType) {
+		d.MaxSurge, d.MaxUnavailable = rollingUpdateLimits(deploy)
+	}
+
+	object := fmt.Sprintf("Deployment %s/%s", deploy.Namespace, deploy.Name)
+	var findings []finding
+	add := func(severity, check, message, suggestion string) {
+		findings = append(findings, finding{Severity: severity, Check: check, Object: object, Message: message, Suggestion: suggestion})
+	}
+
+	owned := ownedReplicaSets(deploy, replicaSets)
+	var current *appsv1.ReplicaSet
+	if len(owned) > 0 {
+		current = &owned[0]
+		d.Current = newRevisionSummary(owned[0])
+	}
+	if len(owned) > 1 {
+		d.Previous = newRevisionSummary(owned[1])
+	}
+
+	rolloutInProgress := d.Updated < d.Desired || deploy.Status.Replicas > d.Updated || d.Available < d.Desired
+	for _, c := range deploy.Status.Conditions {
+		switch {
+		case c.Type == appsv1.DeploymentProgressing:
+			d.ProgressingReason = c.Reason
+			d.ProgressingMessage = c.Message
+			if c.Status == corev1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded" {
+				add(severityCritical, "deployment.progress-deadline", fmt.Sprintf("El rollout superó progressDeadlineSeconds (%ds) sin avanzar: %s", d.ProgressDeadlineSeconds, c.Message),
+					"Revisa los pods de la nueva revisión (abajo); Kubernetes no revierte solo, el rollout seguirá detenido hasta que lo corrijas o hagas rollback.")
+			}
+		case c.Type == appsv1.DeploymentReplicaFailure && c.Status == corev1.ConditionTrue:
+			add(severityCritical, "deployment.replica-failure", fmt.Sprintf("El ReplicaSet no puede crear pods (%s): %s", c.Reason, c.Message),
+				"Suele deberse a una ResourceQuota o LimitRange del namespace, o a un webhook de admisión que rechaza el pod.")
+		}
+	}
+	if deploy.Spec.Paused {
+		add(severityWarning, "deployment.paused", "El Deployment está pausado: los cambios en el template no se despliegan.",
+			fmt.Sprintf("Reanúdalo con 'kubectl rollout resume deployment/%s -n %s'.", deploy.Name, deploy.Namespace))
+	}
+	if deploy.Status.ObservedGeneration < deploy.Generation {
+		add(severityInfo, "deployment.not-observed", "El controlador aún no ha procesado la última modificación del Deployment.", "")
+	}
+
+	// Pods de la revisión nueva que fallan.
+	crashLooping, unschedulable := 0, 0
+	var firstFailing string
+	if current != nil {
+		hash := current.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+		sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+		for _, p := range pods {
+			if p.Labels[appsv1.DefaultDeploymentUniqueLabelKey] != hash || isPodReady(p) || p.DeletionTimestamp != nil {
+				continue
+			}
+			reason := podNotReadyReason(p, events)
+			if causes := analyzePod(p, podEvents(p, events), nil, nil); len(causes) > 0 && causes[0].Severity != severityInfo {
+				reason = causes[0].Message
+			}
+			d.FailingPods = append(d.FailingPods, failingPod{Name: p.Name, Status: podDisplayStatus(p), Reason: reason})
+			if firstFailing == "" {
+				firstFailing = p.Name
+			}
+			if isCrashLooping(p) {
+				crashLooping++
+			}
+			if isUnscheduledPod(p) {
+				unschedulable++
+			}
+		}
+	}
+	if len(d.FailingPods) > 0 {
+		severity := severityWarning
+		if crashLooping > 0 || d.ProgressingReason == "ProgressDeadlineExceeded" {
+			severity = severityCritical
+		}
+		add(severity, "deployment.failing-pods", fmt.Sprintf("%d %s de la revisión %d no están listos (ej. %s: %s).",
+			len(d.FailingPods), pluralize(len(d.FailingPods), "pod", "pods"), d.Current.Revision, firstFailing, d.FailingPods[0].Reason),
+			fmt.Sprintf("Ejecuta 'eks-review diagnose pod %s -n %s'.", firstFailing, deploy.Namespace))
+	}
+	if crashLooping > 0 && d.Previous != nil {
+		add(severityCritical, "deployment.rollback", fmt.Sprintf("La revisión %d entra en CrashLoopBackOff (%d %s) y la revisión %d (%s) es la anterior.",
+			d.Current.Revision, crashLooping, pluralize(crashLooping, "pod", "pods"), d.Previous.Revision, strings.Join(d.Previous.Images, ", ")),
+			fmt.Sprintf("Haz rollback mientras investigas: kubectl rollout undo deployment/%s -n %s --to-revision=%d", deploy.Name, deploy.Namespace, d.Previous.Revision))
+	}
+
+	// Estrategia: con maxUnavailable=0 el rollout necesita capacidad extra para los pods
+	// nuevos; con maxSurge=0 solo avanza retirando pods antiguos.
+	if d.Strategy == string(appsv1.RollingUpdateDeploymentStrategyType) {
+		if d.MaxUnavailable == 0 && unschedulable > 0 {
+			add(severityWarning, "deployment.strategy-no-capacity", fmt.Sprintf("maxUnavailable=0: el rollout solo avanza creando pods nuevos (maxSurge=%d) y %d no se pueden programar.",
+				d.MaxSurge, unschedulable),
+				fmt.Sprintf("Añade capacidad (ver 'eks-review diagnose pending -n %s') o permite maxUnavailable>=1.", deploy.Namespace))
+		} else if d.MaxSurge == 0 && rolloutInProgress && len(d.FailingPods) > 0 {
+			add(severityWarning, "deployment.strategy-no-surge", fmt.Sprintf("maxSurge=0: el rollout solo avanza retirando pods antiguos (maxUnavailable=%d) y se detiene mientras los nuevos fallen.", d.MaxUnavailable),
+				"Corrige la revisión nueva; considera maxSurge>0 para no reducir capacidad durante el despliegue.")
+		}
+	} else if rolloutInProgress {
+		add(severityInfo, "deployment.recreate", "Estrategia Recreate: todos los pods antiguos se eliminan antes de crear los nuevos (hay indisponibilidad durante el rollout).", "")
+	}
+
+	// PDBs: no bloquean el rollout (el controlador no usa la API de eviction) pero sí los
+	// drains de nodos, que a su vez pueden dejar pods nuevos Pending.
+	podLabels := labels.Set(deploy.Spec.Template.Labels)
+	for _, pdb := range pdbs {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() || !selector.Matches(podLabels) {
+			continue
+		}
+		s := pdbSummary{Name: pdb.Name, DisruptionsAllowed: pdb.Status.DisruptionsAllowed}
+		if pdb.Spec.MinAvailable != nil {
+			s.MinAvailable = pdb.Spec.MinAvailable.String()
+		}
+		if pdb.Spec.MaxUnavailable != nil {
+			s.MaxUnavailable = pdb.Spec.MaxUnavailable.String()
+		}
+		d.PDBs = append(d.PDBs, s)
+		if pdb.Status.DisruptionsAllowed == 0 {
+			add(severityWarning, "deployment.pdb-blocking", fmt.Sprintf("El PodDisruptionBudget '%s' no permite ninguna interrupción (%d/%d pods sanos): bloquea los drains de nodos y las evicciones.",
+				pdb.Name, pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy),
+				"Restablece los pods no listos o ajusta minAvailable/maxUnavailable del PDB; el rollout en sí no lo respeta, pero las actualizaciones de nodegroups sí.")
+		}
+	}
+
+	sortFindings(findings)
+	d.Findings = findings
+	if d.Findings == nil {
+		d.Findings = []finding{}
+	}
+	return d
+}
+
+// podEvents filtra los eventos que se refieren al pod.
+func podEvents(pod corev1.Pod, events []clusterEvent) []clusterEvent {
+	var result []clusterEvent
+	for _, e := range events {
+		if e.Kind == "Pod" && e.Namespace == pod.Namespace && e.Name == pod.Name {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// printDeploymentDiagnosis imprime el informe de diagnose deployment.
+func printDeploymentDiagnosis(d deploymentDiagnosis) {
+	fmt.Fprintf(os.Stdout, "\n--- Deployment %s/%s ---\n", d.Namespace, d.Deployment)
+	strategy := d.Strategy
+	if d.Strategy == string(appsv1.RollingUpdateDeploymentStrategyType) {
+		strategy += fmt.Sprintf(" (maxSurge=%d, maxUnavailable=%d)", d.MaxSurge, d.MaxUnavailable)
+	}
+	progressing := valueOrNone(d.ProgressingReason)
+	PrintBasicTable([]string{"CAMPO", "VALOR"}, [][]string{
+		{"Réplicas", fmt.Sprintf("%d deseadas, %d actualizadas, %d listas, %d disponibles", d.Desired, d.Updated, d.Ready, d.Available)},
+		{"Estrategia", strategy},
+		{"Progressing", progressing},
+		{"progressDeadlineSeconds", fmt.Sprintf("%d", d.ProgressDeadlineSeconds)},
+		{"Pausado", fmt.Sprintf("%t", d.Paused)},
+	})
+
+	fmt.Fprintln(os.Stdout, "--- Revisiones ---")
+	var rows [][]string
+	for _, r := range []struct {
+		label string
+		rev   *revisionSummary
+	}{{"actual", d.Current}, {"anterior", d.Previous}} {
+		if r.rev == nil {
+			continue
+		}
+		rows = append(rows, []string{r.label, fmt.Sprintf("%d", r.rev.Revision), r.rev.ReplicaSet,
+			fmt.Sprintf("%d/%d", r.rev.Ready, r.rev.Replicas), strings.Join(r.rev.Images, ","), formatEventAge(r.rev.CreatedAt)})
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(os.Stdout, "No se encontraron ReplicaSets del Deployment.")
+	} else {
+		PrintBasicTable([]string{"", "REVISIÓN", "REPLICASET", "LISTOS", "IMÁGENES", "CREADO"}, rows)
+	}
+
+	if len(d.FailingPods) > 0 {
+		fmt.Fprintf(os.Stdout, "--- Pods de la revisión actual que fallan (%d) ---\n", len(d.FailingPods))
+		rows = rows[:0]
+		for _, p := range d.FailingPods {
+			rows = append(rows, []string{p.Name, p.Status, truncateString(p.Reason, 100)})
+		}
+		PrintBasicTable([]string{"POD", "ESTADO", "MOTIVO"}, rows)
+	}
+
+	if len(d.PDBs) > 0 {
+		fmt.Fprintln(os.Stdout, "--- PodDisruptionBudgets ---")
+		rows = rows[:0]
+		for _, p := range d.PDBs {
+			rows = append(rows, []string{p.Name, valueOrNone(p.MinAvailable), valueOrNone(p.MaxUnavailable), fmt.Sprintf("%d", p.DisruptionsAllowed)})
+		}
+		PrintBasicTable([]string{"NOMBRE", "MIN DISPONIBLES", "MAX NO DISPONIBLES", "INTERRUPCIONES PERMITIDAS"}, rows)
+	}
+
+	printFindings("Hallazgos", d.Findings)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func testRolloutDeployment(replicas int32) appsv1.Deployment {
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "api", UID: types.UID("deploy-uid")},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}}},
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RollingUpdateDeploymentStrategyType},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: replicas + 1, UpdatedReplicas: 1, ReadyReplicas: replicas, AvailableReplicas: replicas},
+	}
+}
+
+func testOwnedReplicaSet(deploy appsv1.Deployment, name, hash, revision, image string) appsv1.ReplicaSet {
+	controller := true
+	return appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "prod", Name: name,
+			Labels:          map[string]string{"app": "api", appsv1.DefaultDeploymentUniqueLabelKey: hash},
+			Annotations:     map[string]string{revisionAnnotation: revision},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: deploy.Name, UID: deploy.UID, Controller: &controller}},
+		},
+		Spec: appsv1.ReplicaSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}}}},
+	}
+}
+
+func TestAnalyzeDeploymentRecommendsRollback(t *testing.T) {
+	deploy := testRolloutDeployment(3)
+	deploy.Status.Conditions = []appsv1.DeploymentCondition{{
+		Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded",
+		Message: `ReplicaSet "api-new" has timed out progressing.`,
+	}}
+	replicaSets := []appsv1.ReplicaSet{
+		testOwnedReplicaSet(deploy, "api-old", "old", "4", "api:1.0"),
+		testOwnedReplicaSet(deploy, "api-new", "new", "5", "api:1.1"),
+		testOwnedReplicaSet(appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{UID: "other"}}, "other", "x", "9", "other:1"),
+	}
+	crash := testCrashingPod("Error", 1)
+	crash.Name = "api-new-1"
+	crash.Labels = map[string]string{"app": "api", appsv1.DefaultDeploymentUniqueLabelKey: "new"}
+	old := testServicePod("api-old-1", map[string]string{"app": "api", appsv1.DefaultDeploymentUniqueLabelKey: "old"}, true)
+
+	d := analyzeDeployment(deploy, replicaSets, []corev1.Pod{old, crash}, nil, nil)
+	if d.Current == nil || d.Current.ReplicaSet != "api-new" || d.Previous == nil || d.Previous.ReplicaSet != "api-old" {
+		t.Fatalf("unexpected revisions: current=%+v previous=%+v", d.Current, d.Previous)
+	}
+	if len(d.FailingPods) != 1 || d.FailingPods[0].Name != "api-new-1" {
+		t.Errorf("failing pods: got %+v", d.FailingPods)
+	}
+	if got := strings.Join(findingChecks(d.Findings), ","); got != "deployment.progress-deadline,deployment.failing-pods,deployment.rollback" {
+		t.Errorf("got checks %s", got)
+	}
+	for _, f := range d.Findings {
+		if f.Check == "deployment.rollback" && !strings.Contains(f.Suggestion, "--to-revision=4") {
+			t.Errorf("rollback suggestion should target revision 4, got %q", f.Suggestion)
+		}
+	}
+}
+
+func TestAnalyzeDeploymentStrategyAndPDB(t *testing.T) {
+	deploy := testRolloutDeployment(2)
+	zero := intstr.FromInt32(0)
+	deploy.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{MaxUnavailable: &zero}
+	replicaSets := []appsv1.ReplicaSet{testOwnedReplicaSet(deploy, "api-new", "new", "2", "api:2")}
+	pending := testPendingPod("api-new-1", "100m")
+	pending.Labels = map[string]string{"app": "api", appsv1.DefaultDeploymentUniqueLabelKey: "new"}
+	pending.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Message: "0/2 nodes are available"}}
+	pdbs := []policyv1.PodDisruptionBudget{{
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{MinAvailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 2}, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 0, CurrentHealthy: 2, DesiredHealthy: 2},
+	}}
+
+	d := analyzeDeployment(deploy, replicaSets, []corev1.Pod{pending}, pdbs, nil)
+	if d.MaxSurge != 1 || d.MaxUnavailable != 0 {
+		t.Errorf("got maxSurge=%d maxUnavailable=%d, want 1/0", d.MaxSurge, d.MaxUnavailable)
+	}
+	if got := strings.Join(findingChecks(d.Findings), ","); got != "deployment.failing-pods,deployment.strategy-no-capacity,deployment.pdb-blocking" {
+		t.Errorf("got checks %s", got)
+	}
+}
+
+func TestRollingUpdateLimits(t *testing.T) {
+	tests := []struct {
+		replicas               int32
+		surge, unavailable     *intstr.IntOrString
+		wantSurge, wantUnavail int
+	}{
+		{4, nil, nil, 1, 1},
+		{3, nil, nil, 1, 0},
+		{1, nil, nil, 1, 0},
+		{3, &intstr.IntOrString{Type: intstr.String, StrVal: "0%"}, &intstr.IntOrString{Type: intstr.String, StrVal: "10%"}, 0, 1},
+	}
+	for _, tt := range tests {
+		deploy := testRolloutDeployment(tt.replicas)
+		deploy.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{MaxSurge: tt.surge, MaxUnavailable: tt.unavailable}
+		surge, unavailable := rollingUpdateLimits(deploy)
+		if surge != tt.wantSurge || unavailable != tt.wantUnavail {
+			t.Errorf("replicas=%d: got %d/%d, want %d/%d", tt.replicas, surge, unavailable, tt.wantSurge, tt.wantUnavail)
+		}
+	}
+}
+
+func TestPodEventsMatchesNamespace(t *testing.T) {
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "postgres-0"}}
+	events := []clusterEvent{
+		{Kind: "Pod", Namespace: "prod", Name: "postgres-0", Reason: "Started"},
+		{Kind: "Pod", Namespace: "staging", Name: "postgres-0", Reason: "OOMKilling"},
+	}
+	if got := podEvents(pod, events); len(got) != 1 || got[0].Reason != "Started" {
+		t.Errorf("expected only the prod event, got %+v", got)
+	}
+}
@@ -0,0 +1,505 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var diagnoseDNSTail int64
+
+const (
+	coreDNSName      = "coredns"
+	kubeDNSService   = "kube-dns"
+	kubeDNSLabel     = "k8s-app=kube-dns"
+	corefileKey      = "Corefile"
+	defaultDNSNdots  = 5
+	maxDNSPodsListed = 5
+)
+
+// dnsLogPatterns clasifica las líneas de log de CoreDNS. Cada línea cuenta solo para el
+// primer patrón que coincide.
+var dnsLogPatterns = []struct {
+	Name    string
+	Pattern *regexp.Regexp
+}{
+	{"loop", regexp.MustCompile(`(?i)loop \(.*\) detected`)},
+	{"timeout", regexp.MustCompile(`(?i)i/o timeout`)},
+	{"servfail", regexp.MustCompile(`SERVFAIL`)},
+	{"refused", regexp.MustCompile(`(?i)connection refused`)},
+	{"error", regexp.MustCompile(`\[ERROR\]`)},
+}
+
+// dnsPodSummary es un pod de CoreDNS.
+type dnsPodSummary struct {
+	Name            string `json:"name"`
+	Node            string `json:"node,omitempty"`
+	Ready           bool   `json:"ready"`
+	Status          string `json:"status"`
+	Restarts        int32  `json:"restarts"`
+	LastTermination string `json:"lastTermination,omitempty"`
+}
+
+// dnsLogMatch resume las líneas de log de CoreDNS que coinciden con un patrón.
+type dnsLogMatch struct {
+	Pattern string `json:"pattern"`
+	Count   int    `json:"count"`
+	Example string `json:"example"`
+}
+
+// corefileSummary es lo que diagnose dns extrae del Corefile.
+type corefileSummary struct {
+	Cache    bool     `json:"cache"`
+	Loop     bool     `json:"loop"`
+	Autopath bool     `json:"autopath"`
+	Forwards []string `json:"forwards"`
+}
+
+// dnsDiagnosis es el informe de diagnose dns.
+type dnsDiagnosis struct {
+	Deployment        string           `json:"deployment"`
+	Desired           int32            `json:"desired"`
+	Ready             int32            `json:"ready"`
+	Pods              []dnsPodSummary  `json:"pods"`
+	Service           string           `json:"service"`
+	ClusterIP         string           `json:"clusterIP,omitempty"`
+	ReadyEndpoints    int              `json:"readyEndpoints"`
+	NotReadyEndpoints int              `json:"notReadyEndpoints"`
+	Corefile          *corefileSummary `json:"corefile,omitempty"`
+	LogMatches        []dnsLogMatch    `json:"logMatches"`
+	Findings          []finding        `json:"findings"`
+}
+
+// dnsInputs reúne lo que necesita analyzeDNS. Deployment, Service y Corefile son nil si
+// no existen o no se pudieron leer; EndpointSlices y WorkloadPods son nil si no se
+// pudieron listar.
+type dnsInputs struct {
+	Deployment     *appsv1.Deployment
+	Pods           []corev1.Pod
+	Service        *corev1.Service
+	EndpointSlices []discoveryv1.EndpointSlice
+	Corefile       *string
+	Logs           map[string]containerLogTail // por pod, y "(anterior)" para el log previo
+	WorkloadPods   []corev1.Pod
+}
+
+var diagnoseDNSCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Revisa la salud y la configuración de CoreDNS.",
+	Long: `El comando diagnose dns revisa el Deployment de CoreDNS (réplicas y pods), el
+Service kube-dns y sus endpoints, el Corefile del ConfigMap coredns (cache, bucles en
+forward, plugin loop), los reinicios y los patrones de error en los logs de CoreDNS, y
+los pods que usan dnsPolicy: Default o un ndots mayor que el predeterminado.
+
+Con -n solo se revisan los pods de ese namespace; sin -n, los de todo el clúster.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clients, err := GetKubeClients()
+		if err != nil {
+			return fmt.Errorf("creando clientes de Kubernetes: %w", err)
+		}
+		outputLower := strings.ToLower(diagnoseOutputFormat)
+		info := os.Stdout
+		if isStructuredOutput(outputLower) {
+			info = os.Stderr
+		}
+
+		fmt.Fprintln(info, "Diagnosticando DNS del clúster (CoreDNS)...")
+		in, err := collectDNSInputs(context.TODO(), clients.Core, diagnoseNamespace, diagnoseDNSTail)
+		if err != nil {
+			return err
+		}
+		diag := analyzeDNS(in)
+
+		if isStructuredOutput(outputLower) {
+			doc := struct {
+				outputHeader
+				dnsDiagnosis
+			}{newOutputHeader("DNSDiagnosis"), diag}
+			return writeStructuredOutput(os.Stdout, outputLower, doc)
+		}
+		printDNSDiagnosis(diag)
+		return nil
+	},
+}
+
+func init() {
+	diagnoseCmd.AddCommand(diagnoseDNSCmd)
+	diagnoseDNSCmd.Flags().Int64Var(&diagnoseDNSTail, "tail", 200, "Número de líneas de log de cada pod de CoreDNS a analizar")
+}
+
+// collectDNSInputs recupera el Deployment, los pods, el Service, los EndpointSlices y el
+// Corefile de CoreDNS, los logs de sus pods y los pods del namespace (o del clúster).
+func collectDNSInputs(ctx context.Context, clientset kubernetes.Interface, workloadNamespace string, tail int64) (dnsInputs, error) {
+	pods, err := clientset.CoreV1().Pods("kube-system").List(ctx, metav1.ListOptions{LabelSelector: kubeDNSLabel})
+	if err != nil {
+		return dnsInputs{}, fmt.Errorf("listando pods de CoreDNS: %w", err)
+	}
+	in := dnsInputs{Pods: pods.Items, Logs: map[string]containerLogTail{}}
+
+	if deploy, err := clientset.AppsV1().Deployments("kube-system").Get(ctx, coreDNSName, metav1.GetOptions{}); err == nil {
+		in.Deployment = deploy
+	} else if !apierrors.IsNotFound(err) {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudo leer el deployment coredns: %s\n", describeCollectError(err))
+	}
+	if svc, err := clientset.CoreV1().Services("kube-system").Get(ctx, kubeDNSService, metav1.GetOptions{}); err == nil {
+		in.Service = svc
+	} else if !apierrors.IsNotFound(err) {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudo leer el service kube-dns: %s\n", describeCollectError(err))
+	}
+	if in.Service != nil {
+		in.EndpointSlices, err = listServiceEndpointSlices(ctx, clientset, "kube-system", kubeDNSService)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar endpointslices: %s\n", describeCollectError(err))
+		}
+	}
+	if cm, err := clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, coreDNSName, metav1.GetOptions{}); err == nil {
+		if corefile, ok := cm.Data[corefileKey]; ok {
+			in.Corefile = &corefile
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudo leer el configmap coredns: %s\n", describeCollectError(err))
+	}
+	for _, p := range in.Pods {
+		if p.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		in.Logs[p.Name] = fetchLogTail(ctx, clientset, p, coreDNSName, false, tail)
+		for _, cs := range p.Status.ContainerStatuses {
+			if cs.Name == coreDNSName && cs.RestartCount > 0 {
+				in.Logs[p.Name+" (anterior)"] = fetchLogTail(ctx, clientset, p, coreDNSName, true, tail)
+			}
+		}
+	}
+	if list, err := clientset.CoreV1().Pods(workloadNamespace).List(ctx, metav1.ListOptions{}); err != nil {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar pods: %s\n", describeCollectError(err))
+	} else {
+		in.WorkloadPods = append([]corev1.Pod{}, list.Items...)
+	}
+	return in, nil
+}
+
+// parseCorefile extrae del Corefile los plugins que revisa diagnose dns y los destinos de
+// forward (y del antiguo proxy). Ignora comentarios y no valida la sintaxis.
+func parseCorefile(corefile string) corefileSummary {
+	s := corefileSummary{Forwards: []string{}}
+	for _, line := range strings.Split(corefile, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "cache":
+			s.Cache = true
+		case "loop":
+			s.Loop = true
+		case "autopath":
+			s.Autopath = true
+		case "forward", "proxy":
+			// forward FROM TO... [{]
+			for _, to := range fields[min(2, len(fields)):] {
+				if to == "{" {
+					break
+				}
+				s.Forwards = append(s.Forwards, to)
+			}
+		}
+	}
+	return s
+}
+
+// forwardHost devuelve la IP de un destino de forward ("dns://10.0.0.2:53", "127.0.0.1")
+// o "" si el destino no es una dirección IP (por ejemplo /etc/resolv.conf).
+func forwardHost(to string) string {
+	if i := strings.Index(to, "://"); i >= 0 {
+		to = to[i+3:]
+	}
+	if host, _, err := net.SplitHostPort(to); err == nil {
+		to = host
+	}
+	if ip := net.ParseIP(strings.Trim(to, "[]")); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+// podNdots devuelve el valor de ndots que fija el dnsConfig del pod, o 0 si no lo fija.
+func podNdots(pod corev1.Pod) int {
+	if pod.Spec.DNSConfig == nil {
+		return 0
+	}
+	for _, o := range pod.Spec.DNSConfig.Options {
+		if o.Name == "ndots" && o.Value != nil {
+			if n, err := strconv.Atoi(*o.Value); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// listPodNames devuelve "ns/nombre" de los primeros pods y cuántos quedan sin mostrar.
+func listPodNames(pods []corev1.Pod) string {
+	names := make([]string, 0, maxDNSPodsListed)
+	for i, p := range pods {
+		if i == maxDNSPodsListed {
+			names = append(names, fmt.Sprintf("y %d más", len(pods)-maxDNSPodsListed))
+			break
+		}
+		names = append(names, p.Namespace+"/"+p.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// analyzeDNS aplica las comprobaciones de diagnose dns.
+func analyzeDNS(in dnsInputs) dnsDiagnosis {
+	d := dnsDiagnosis{Deployment: coreDNSName, Service: kubeDNSService, Pods: []dnsPodSummary{}, LogMatches: []dnsLogMatch{}}
+	var findings []finding
+	add := func(severity, check, object, message, suggestion string) {
+		findings = append(findings, finding{Severity: severity, Check: check, Object: object, Message: message, Suggestion: suggestion})
+	}
+	deployObject := "Deployment kube-system/" + coreDNSName
+
+	// Deployment y pods.
+	if in.Deployment == nil {
+		add(severityCritical, "dns.deployment-missing", deployObject, "No existe el Deployment coredns en kube-system.",
+			"Reinstala el add-on: aws eks create-addon --cluster-name <cluster> --addon-name coredns")
+	} else {
+		d.Desired = 1
+		if in.Deployment.Spec.Replicas != nil {
+			d.Desired = *in.Deployment.Spec.Replicas
+		}
+		d.Ready = in.Deployment.Status.ReadyReplicas
+		switch {
+		case d.Ready == 0:
+			add(severityCritical, "dns.replicas-unavailable", deployObject, fmt.Sprintf("Ninguna de las %d réplicas de CoreDNS está lista: la resolución DNS del clúster no funciona.", d.Desired),
+				"Revisa los pods de CoreDNS más abajo o con 'eks-review diagnose pod <pod> -n kube-system'.")
+		case d.Ready < d.Desired:
+			add(severityWarning, "dns.replicas-unavailable", deployObject, fmt.Sprintf("Solo %d/%d réplicas de CoreDNS están listas.", d.Ready, d.Desired),
+				"Revisa los pods de CoreDNS que no están listos.")
+		}
+		if d.Desired < 2 {
+			add(severityWarning, "dns.single-replica", deployObject, fmt.Sprintf("CoreDNS tiene %d réplica: un solo fallo o drain deja el clúster sin DNS.", d.Desired),
+				"Escala CoreDNS a 2 réplicas como mínimo (o usa el autoescalado del add-on de EKS).")
+		}
+	}
+	nodes := map[string]bool{}
+	for _, p := range in.Pods {
+		if isTerminatedPod(p) {
+			continue
+		}
+		s := dnsPodSummary{Name: p.Name, Node: p.Spec.NodeName, Ready: isPodReady(p), Status: podDisplayStatus(p)}
+		for _, cs := range p.Status.ContainerStatuses {
+			s.Restarts += cs.RestartCount
+			if t := cs.LastTerminationState.Terminated; t != nil {
+				s.LastTermination = fmt.Sprintf("%s (exit %d)", t.Reason, t.ExitCode)
+			}
+		}
+		d.Pods = append(d.Pods, s)
+		if s.Ready && s.Node != "" {
+			nodes[s.Node] = true
+		}
+		object := "Pod kube-system/" + p.Name
+		if s.Restarts >= highRestartCount {
+			msg := fmt.Sprintf("El pod de CoreDNS acumula %d reinicios", s.Restarts)
+			if s.LastTermination != "" {
+				msg += "; última terminación: " + s.LastTermination
+			}
+			suggestion := fmt.Sprintf("Revisa sus logs anteriores: eks-review monitor logs --pod %s -n kube-system --previous", p.Name)
+			if strings.HasPrefix(s.LastTermination, "OOMKilled") {
+				suggestion = "CoreDNS se queda sin memoria: aumenta su límite de memoria (crece con el número de Services y Pods del clúster)."
+			}
+			add(severityWarning, "dns.pod-restarts", object, msg+".", suggestion)
+		}
+	}
+	if len(d.Pods) > 1 && len(nodes) == 1 {
+		add(severityWarning, "dns.same-node", deployObject, "Todas las réplicas listas de CoreDNS están en el mismo nodo.",
+			"Añade una podAntiAffinity o topologySpreadConstraints por nodo/zona para que un fallo de nodo no tumbe el DNS.")
+	}
+
+	// Service y endpoints.
+	svcObject := "Service kube-system/" + kubeDNSService
+	if in.Service == nil {
+		add(severityCritical, "dns.service-missing", svcObject, "No existe el Service kube-dns en kube-system: los pods no pueden resolver nombres.",
+			"Reinstala el add-on coredns; el Service debe conservar la ClusterIP configurada en el kubelet (clusterDNS).")
+	} else {
+		d.ClusterIP = in.Service.Spec.ClusterIP
+		var udp, tcp bool
+		for _, p := range in.Service.Spec.Ports {
+			if p.Port == 53 {
+				udp = udp || p.Protocol == corev1.ProtocolUDP
+				tcp = tcp || p.Protocol == corev1.ProtocolTCP || p.Protocol == ""
+			}
+		}
+		if !udp || !tcp {
+			add(severityWarning, "dns.service-ports", svcObject, "El Service kube-dns no expone el puerto 53 en UDP y TCP.",
+				"Las respuestas grandes se reintentan por TCP; expone 53/UDP y 53/TCP.")
+		}
+		if in.EndpointSlices != nil {
+			d.ReadyEndpoints, d.NotReadyEndpoints = countEndpoints(in.EndpointSlices)
+			switch {
+			case d.ReadyEndpoints == 0:
+				add(severityCritical, "dns.no-endpoints", svcObject, "El Service kube-dns no tiene endpoints listos.",
+					"Comprueba que los pods de CoreDNS estén listos y que sus etiquetas coincidan con el selector del Service.")
+			case d.NotReadyEndpoints > 0:
+				add(severityWarning, "dns.not-ready-endpoints", svcObject, fmt.Sprintf("kube-dns tiene %d endpoints no listos.", d.NotReadyEndpoints),
+					"Revisa los pods de CoreDNS que no están listos.")
+			}
+		}
+	}
+
+	// Corefile.
+	cmObject := "ConfigMap kube-system/" + coreDNSName
+	if in.Corefile == nil {
+		add(severityWarning, "dns.corefile-missing", cmObject, "No se pudo leer el Corefile del ConfigMap coredns.",
+			"Comprueba que el ConfigMap coredns existe y tiene la clave Corefile.")
+	} else {
+		cf := parseCorefile(*in.Corefile)
+		d.Corefile = &cf
+		if !cf.Cache {
+			add(severityWarning, "dns.cache-missing", cmObject, "El Corefile no tiene el plugin cache: cada consulta se reenvía al resolver de la VPC.",
+				"Añade 'cache 30' al bloque del servidor; reduce latencia y el riesgo de superar el límite de paquetes por ENI del resolver de la VPC.")
+		}
+		for _, to := range cf.Forwards {
+			host := forwardHost(to)
+			if host == "" {
+				continue
+			}
+			if ip := net.ParseIP(host); ip.IsLoopback() || host == d.ClusterIP {
+				add(severityCritical, "dns.forward-loop", cmObject, fmt.Sprintf("El Corefile reenvía a %s, que apunta de vuelta a CoreDNS: las consultas externas entran en bucle.", to),
+					"Reenvía a /etc/resolv.conf o al resolver de la VPC (base de la CIDR + 2).")
+			}
+		}
+		if !cf.Loop {
+			add(severityInfo, "dns.loop-plugin-missing", cmObject, "El Corefile no tiene el plugin loop, que detiene CoreDNS cuando detecta un bucle de reenvío.", "")
+		}
+	}
+
+	// Logs de CoreDNS.
+	names := make([]string, 0, len(in.Logs))
+	for name := range in.Logs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	matches := map[string]*dnsLogMatch{}
+	for _, name := range names {
+		for _, line := range in.Logs[name].Lines {
+			for _, p := range dnsLogPatterns {
+				if !p.Pattern.MatchString(line) {
+					continue
+				}
+				m, ok := matches[p.Name]
+				if !ok {
+					m = &dnsLogMatch{Pattern: p.Name, Example: truncateString(strings.TrimSpace(line), 160)}
+					matches[p.Name] = m
+				}
+				m.Count++
+				break
+			}
+		}
+	}
+	for _, p := range dnsLogPatterns {
+		if m, ok := matches[p.Name]; ok {
+			d.LogMatches = append(d.LogMatches, *m)
+		}
+	}
+	for _, m := range d.LogMatches {
+		switch m.Pattern {
+		case "loop":
+			add(severityCritical, "dns.loop-detected", deployObject, "CoreDNS ha detectado un bucle de reenvío: "+m.Example,
+				"El resolv.conf del nodo o el forward del Corefile apuntan a CoreDNS; reenvía al resolver de la VPC.")
+		case "timeout":
+			add(severityWarning, "dns.upstream-timeouts", deployObject, fmt.Sprintf("%d timeouts hacia el resolver upstream en los logs (ej. %s).", m.Count, m.Example),
+				"Revisa los security groups/NACL hacia el resolver de la VPC y el límite de 1024 paquetes/s por ENI; activa cache y NodeLocal DNSCache si hay mucho volumen.")
+		default:
+			add(severityWarning, "dns.log-errors", deployObject, fmt.Sprintf("%d líneas '%s' en los logs de CoreDNS (ej. %s).", m.Count, m.Pattern, m.Example),
+				"Revisa los logs completos con 'eks-review monitor logs --pod <pod> -n kube-system'.")
+		}
+	}
+
+	// Configuración DNS de los pods.
+	if in.WorkloadPods != nil {
+		var policyDefault, highNdots []corev1.Pod
+		for _, p := range in.WorkloadPods {
+			if p.Namespace == "kube-system" || p.Spec.HostNetwork || isTerminatedPod(p) {
+				continue
+			}
+			if p.Spec.DNSPolicy == corev1.DNSDefault {
+				policyDefault = append(policyDefault, p)
+			}
+			if podNdots(p) > defaultDNSNdots {
+				highNdots = append(highNdots, p)
+			}
+		}
+		if len(policyDefault) > 0 {
+			add(severityWarning, "dns.policy-default", fmt.Sprintf("%d pods", len(policyDefault)),
+				fmt.Sprintf("Pods con dnsPolicy: Default, que usan el resolv.conf del nodo y no resuelven nombres del clúster: %s.", listPodNames(policyDefault)),
+				"Salvo que sea intencionado, elimina dnsPolicy (el valor por defecto es ClusterFirst).")
+		}
+		if len(highNdots) > 0 {
+			add(severityWarning, "dns.ndots-high", fmt.Sprintf("%d pods", len(highNdots)),
+				fmt.Sprintf("Pods con ndots mayor que %d: cada nombre externo genera varias consultas fallidas antes de la buena: %s.", defaultDNSNdots, listPodNames(highNdots)),
+				"Baja ndots a 2 en dnsConfig.options o usa nombres completos acabados en punto.")
+		}
+	}
+
+	sortFindings(findings)
+	d.Findings = findings
+	if d.Findings == nil {
+		d.Findings = []finding{}
+	}
+	return d
+}
+
+// printDNSDiagnosis imprime el informe de diagnose dns.
+func printDNSDiagnosis(d dnsDiagnosis) {
+	fmt.Fprintln(os.Stdout, "\n--- CoreDNS ---")
+	rows := [][]string{
+		{"Deployment", fmt.Sprintf("kube-system/%s (%d/%d listas)", d.Deployment, d.Ready, d.Desired)},
+		{"Service", fmt.Sprintf("kube-system/%s %s", d.Service, valueOrNone(d.ClusterIP))},
+		{"Endpoints", fmt.Sprintf("%d listos, %d no listos", d.ReadyEndpoints, d.NotReadyEndpoints)},
+	}
+	if d.Corefile != nil {
+		rows = append(rows,
+			[]string{"Corefile cache/loop/autopath", fmt.Sprintf("%t/%t/%t", d.Corefile.Cache, d.Corefile.Loop, d.Corefile.Autopath)},
+			[]string{"Corefile forward", valueOrNone(strings.Join(d.Corefile.Forwards, " "))},
+		)
+	}
+	PrintBasicTable([]string{"CAMPO", "VALOR"}, rows)
+
+	if len(d.Pods) > 0 {
+		fmt.Fprintln(os.Stdout, "--- Pods ---")
+		rows = rows[:0]
+		for _, p := range d.Pods {
+			rows = append(rows, []string{p.Name, valueOrNone(p.Node), fmt.Sprintf("%t", p.Ready), p.Status, fmt.Sprintf("%d", p.Restarts), valueOrNone(p.LastTermination)})
+		}
+		PrintBasicTable([]string{"POD", "NODO", "LISTO", "ESTADO", "REINICIOS", "ÚLTIMA TERMINACIÓN"}, rows)
+	}
+
+	if len(d.LogMatches) > 0 {
+		fmt.Fprintln(os.Stdout, "--- Errores en logs ---")
+		rows = rows[:0]
+		for _, m := range d.LogMatches {
+			rows = append(rows, []string{m.Pattern, fmt.Sprintf("%d", m.Count), m.Example})
+		}
+		PrintBasicTable([]string{"PATRÓN", "LÍNEAS", "EJEMPLO"}, rows)
+	}
+
+	printFindings("Hallazgos", d.Findings)
+}
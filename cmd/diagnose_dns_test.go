@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+)
+
+const testCorefile = `.:53 {
+    errors
+    health
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+      pods insecure
+      fallthrough in-addr.arpa ip6.arpa
+    }
+    prometheus :9153
+    forward . /etc/resolv.conf
+    cache 30
+    loop
+    reload
+    loadbalance
+}
+`
+
+func testDNSInputs() dnsInputs {
+	replicas := int32(2)
+	ready := true
+	corefile := testCorefile
+	a := testSystemPod("coredns-a", "kube-dns", "node-a", true)
+	b := testSystemPod("coredns-b", "kube-dns", "node-b", true)
+	return dnsInputs{
+		Deployment: &appsv1.Deployment{
+			Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{ReadyReplicas: 2},
+		},
+		Pods: []corev1.Pod{a, b},
+		Service: &corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "172.20.0.10", Ports: []corev1.ServicePort{
+			{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+			{Name: "dns-tcp", Port: 53, Protocol: corev1.ProtocolTCP},
+		}}},
+		EndpointSlices: []discoveryv1.EndpointSlice{{Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.0.1.5"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+			{Addresses: []string{"10.0.2.5"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+		}}},
+		Corefile:     &corefile,
+		Logs:         map[string]containerLogTail{},
+		WorkloadPods: []corev1.Pod{testRunningPod("api-1", "node-a", "100m")},
+	}
+}
+
+func TestAnalyzeDNSHealthy(t *testing.T) {
+	d := analyzeDNS(testDNSInputs())
+	if len(d.Findings) != 0 {
+		t.Fatalf("expected no findings, got %v", findingChecks(d.Findings))
+	}
+	if d.ReadyEndpoints != 2 || d.Corefile == nil || !d.Corefile.Cache || d.Corefile.Forwards[0] != "/etc/resolv.conf" {
+		t.Errorf("unexpected diagnosis: %+v", d)
+	}
+}
+
+func TestAnalyzeDNSProblems(t *testing.T) {
+	in := testDNSInputs()
+	corefile := strings.NewReplacer("    cache 30\n", "", "/etc/resolv.conf", "172.20.0.10").Replace(testCorefile)
+	in.Corefile = &corefile
+	in.Pods[1].Spec.NodeName = "node-a"
+	in.Pods[1].Status.ContainerStatuses[0].RestartCount = 7
+	in.Pods[1].Status.ContainerStatuses[0].LastTerminationState.Terminated = &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}
+	in.Logs["coredns-a"] = containerLogTail{Lines: []string{
+		`[INFO] 10.0.1.7:4455 - 1 "A IN api.example.com. udp 33 false 512" NOERROR`,
+		`[ERROR] plugin/errors: 2 example.com. A: read udp 10.0.1.5:5353->10.0.0.2:53: i/o timeout`,
+		`[ERROR] plugin/errors: 2 other.com. A: read udp 10.0.1.5:5354->10.0.0.2:53: i/o timeout`,
+	}}
+	host := testRunningPod("resolver", "node-a", "100m")
+	host.Spec.DNSPolicy = corev1.DNSDefault
+	ndots := "10"
+	chatty := testRunningPod("chatty", "node-a", "100m")
+	chatty.Spec.DNSConfig = &corev1.PodDNSConfig{Options: []corev1.PodDNSConfigOption{{Name: "ndots", Value: &ndots}}}
+	in.WorkloadPods = append(in.WorkloadPods, host, chatty)
+
+	d := analyzeDNS(in)
+	got := strings.Join(findingChecks(d.Findings), ",")
+	want := "dns.forward-loop,dns.pod-restarts,dns.same-node,dns.cache-missing,dns.upstream-timeouts,dns.policy-default,dns.ndots-high"
+	if got != want {
+		t.Errorf("got checks %s, want %s", got, want)
+	}
+	if len(d.LogMatches) != 1 || d.LogMatches[0].Count != 2 {
+		t.Errorf("log matches: got %+v", d.LogMatches)
+	}
+}
+
+func TestAnalyzeDNSDown(t *testing.T) {
+	in := testDNSInputs()
+	in.Deployment.Status.ReadyReplicas = 0
+	in.EndpointSlices = []discoveryv1.EndpointSlice{}
+	in.Service.Spec.Ports = in.Service.Spec.Ports[:1]
+	if got := strings.Join(findingChecks(analyzeDNS(in).Findings), ","); got != "dns.replicas-unavailable,dns.no-endpoints,dns.service-ports" {
+		t.Errorf("got checks %s", got)
+	}
+
+	in = testDNSInputs()
+	in.Deployment, in.Service, in.Corefile = nil, nil, nil
+	if got := strings.Join(findingChecks(analyzeDNS(in).Findings), ","); got != "dns.deployment-missing,dns.service-missing,dns.corefile-missing" {
+		t.Errorf("missing objects: got checks %s", got)
+	}
+}
+
+func TestForwardHost(t *testing.T) {
+	tests := map[string]string{
+		"127.0.0.1":        "127.0.0.1",
+		"dns://10.0.0.2":   "10.0.0.2",
+		"10.0.0.2:53":      "10.0.0.2",
+		"[::1]:53":         "::1",
+		"/etc/resolv.conf": "",
+		"tls://1.1.1.1":    "1.1.1.1",
+	}
+	for in, want := range tests {
+		if got := forwardHost(in); got != want {
+			t.Errorf("forwardHost(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
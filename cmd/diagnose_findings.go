@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Severidades de los hallazgos de diagnose, de mayor a menor gravedad.
+const (
+	severityCritical = "critical"
+	severityWarning  = "warning"
+	severityInfo     = "info"
+)
+
+// finding es un problema detectado por un diagnóstico, con el siguiente paso sugerido.
+type finding struct {
+	Severity   string `json:"severity"`
+	Check      string `json:"check"`
+	Object     string `json:"object"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// severityRank devuelve un número mayor cuanto más grave es la severidad.
+func severityRank(severity string) int {
+	switch severity {
+	case severityCritical:
+		return 3
+	case severityWarning:
+		return 2
+	case severityInfo:
+		return 1
+	}
+	return 0
+}
+
+// sortFindings ordena por severidad. Dentro de una misma severidad conserva el orden
+// en que los generó el diagnóstico, que ya refleja la probabilidad de cada causa.
+func sortFindings(findings []finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return severityRank(findings[i].Severity) > severityRank(findings[j].Severity)
+	})
+}
+
+// severityColor devuelve el color con el que se resalta cada severidad.
+func severityColor(severity string) string {
+	switch severity {
+	case severityCritical:
+		return colorRed
+	case severityWarning:
+		return colorYellow
+	}
+	return colorGreen
+}
+
+// printFindings imprime los hallazgos numerados, ordenados por severidad.
+func printFindings(title string, findings []finding) {
+	fmt.Fprintf(os.Stdout, "\n--- %s ---\n", title)
+	if len(findings) == 0 {
+		fmt.Fprintln(os.Stdout, colorize("No se detectaron problemas.", colorGreen))
+		return
+	}
+	for i, f := range findings {
+		header := fmt.Sprintf("%d. [%s] %s", i+1, strings.ToUpper(f.Severity), f.Message)
+		fmt.Fprintln(os.Stdout, colorize(header, severityColor(f.Severity)))
+		if f.Object != "" {
+			fmt.Fprintf(os.Stdout, "   Objeto: %s\n", f.Object)
+		}
+		if f.Suggestion != "" {
+			fmt.Fprintf(os.Stdout, "   Siguiente paso: %s\n", f.Suggestion)
+		}
+	}
+}
@@ -0,0 +1,542 @@
+package cmd
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// certExpiryWarning es la antelación con la que se avisa de que un certificado va a caducar.
+const certExpiryWarning = 14 * 24 * time.Hour
+
+const (
+	ingressClassAnnotation        = "kubernetes.io/ingress.class"
+	ingressClassDefaultAnnotation = "ingressclass.kubernetes.io/is-default-class"
+	albController                 = "ingress.k8s.aws/alb"
+)
+
+// ingressControllerSelectors asocia el campo spec.controller de una IngressClass con la
+// etiqueta de los pods de los controladores más habituales en EKS.
+var ingressControllerSelectors = map[string]string{
+	albController:                   "app.kubernetes.io/name=aws-load-balancer-controller",
+	"k8s.io/ingress-nginx":          "app.kubernetes.io/name=ingress-nginx",
+	"traefik.io/ingress-controller": "app.kubernetes.io/name=traefik",
+}
+
+// legacyIngressControllers traduce la anotación kubernetes.io/ingress.class al controlador.
+var legacyIngressControllers = map[string]string{
+	"alb":     albController,
+	"nginx":   "k8s.io/ingress-nginx",
+	"traefik": "traefik.io/ingress-controller",
+}
+
+// ingressBackendCheck es el resultado de comprobar el backend de una regla.
+type ingressBackendCheck struct {
+	Host           string `json:"host"`
+	Path           string `json:"path"`
+	Service        string `json:"service,omitempty"`
+	Port           string `json:"port,omitempty"`
+	ReadyEndpoints int    `json:"readyEndpoints"`
+	Status         string `json:"status"`
+}
+
+// ingressTLSCheck es el resultado de comprobar un secret TLS.
+type ingressTLSCheck struct {
+	Secret   string    `json:"secret"`
+	Hosts    []string  `json:"hosts"`
+	Subject  string    `json:"subject,omitempty"`
+	NotAfter time.Time `json:"notAfter,omitempty"`
+	Status   string    `json:"status"`
+}
+
+// ingressDiagnosis es el informe de diagnose ingress.
+type ingressDiagnosis struct {
+	Namespace  string                `json:"namespace"`
+	Ingress    string                `json:"ingress"`
+	Class      string                `json:"class"`
+	Controller string                `json:"controller,omitempty"`
+	Address    string                `json:"address"`
+	Backends   []ingressBackendCheck `json:"backends"`
+	TLS        []ingressTLSCheck     `json:"tls"`
+	Findings   []finding             `json:"findings"`
+}
+
+// ingressInputs reúne lo que necesita analyzeIngress. Los campos nil indican que ese
+// recurso no se pudo recuperar y sus comprobaciones se omiten.
+type ingressInputs struct {
+	Ingress        networkingv1.Ingress
+	Classes        []networkingv1.IngressClass
+	ControllerPods []corev1.Pod
+	Services       map[string]corev1.Service
+	EndpointSlices map[string][]discoveryv1.EndpointSlice
+	Secrets        map[string]corev1.Secret
+	SecretErrors   map[string]error
+	AllIngresses   []networkingv1.Ingress
+	Events         []clusterEvent
+}
+
+var diagnoseIngressCmd = &cobra.Command{
+	Use:     "ingress <nombre-del-ingress>",
+	Aliases: []string{"ing"},
+	Short:   "Comprueba el enrutamiento completo de un Ingress.",
+	Long: `El comando diagnose ingress valida el camino completo de un Ingress: que su
+IngressClass existe y tiene un controlador en ejecución, que el Service y el puerto de
+cada regla existen y tienen endpoints listos, que los secrets TLS existen y contienen
+un certificado válido, vigente y que cubre los hosts, y que no hay reglas host/path en
+conflicto con otros Ingress de la misma clase.
+
+Cada problema se reporta como un hallazgo con su severidad y el siguiente paso.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clients, err := GetKubeClients()
+		if err != nil {
+			return fmt.Errorf("creando clientes de Kubernetes: %w", err)
+		}
+		namespace := GetEffectiveNamespace(diagnoseNamespace, false, "default", false)
+		outputLower := strings.ToLower(diagnoseOutputFormat)
+		info := os.Stdout
+		if isStructuredOutput(outputLower) {
+			info = os.Stderr
+		}
+
+		fmt.Fprintf(info, "Diagnosticando ingress '%s' en namespace '%s'...\n", args[0], namespace)
+		in, err := collectIngressInputs(context.TODO(), clients.Core, namespace, args[0])
+		if err != nil {
+			return err
+		}
+		diag := analyzeIngress(in, time.Now())
+
+		if isStructuredOutput(outputLower) {
+			doc := struct {
+				outputHeader
+				ingressDiagnosis
+			}{newOutputHeader("IngressDiagnosis"), diag}
+			return writeStructuredOutput(os.Stdout, outputLower, doc)
+		}
+		printIngressDiagnosis(diag)
+		return nil
+	},
+}
+
+func init() {
+	diagnoseCmd.AddCommand(diagnoseIngressCmd)
+}
+
+// collectIngressInputs recupera el Ingress y todo lo que referencia. Solo falla si no
+// se puede obtener el propio Ingress.
+func collectIngressInputs(ctx context.Context, clientset kubernetes.Interface, namespace, name string) (ingressInputs, error) {
+	ing, err := clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return ingressInputs{}, fmt.Errorf("obteniendo ingress '%s' en namespace '%s': %w", name, namespace, err)
+	}
+	in := ingressInputs{Ingress: *ing}
+	warn := func(resource string, err error) {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar %s: %s\n", resource, describeCollectError(err))
+	}
+
+	if list, err := clientset.NetworkingV1().IngressClasses().List(ctx, metav1.ListOptions{}); err != nil {
+		warn("ingressclasses", err)
+	} else {
+		in.Classes = append([]networkingv1.IngressClass{}, list.Items...)
+	}
+	if _, controller := resolveIngressClass(*ing, in.Classes); controller != "" {
+		if selector, ok := ingressControllerSelectors[controller]; ok {
+			if list, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{LabelSelector: selector}); err != nil {
+				warn("pods del controlador", err)
+			} else {
+				in.ControllerPods = append([]corev1.Pod{}, list.Items...)
+			}
+		}
+	}
+	if list, err := clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("services", err)
+	} else {
+		in.Services = map[string]corev1.Service{}
+		for _, svc := range list.Items {
+			in.Services[svc.Name] = svc
+		}
+	}
+	if list, err := clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("endpointslices", err)
+	} else {
+		in.EndpointSlices = map[string][]discoveryv1.EndpointSlice{}
+		for _, s := range list.Items {
+			svc := s.Labels[discoveryv1.LabelServiceName]
+			in.EndpointSlices[svc] = append(in.EndpointSlices[svc], s)
+		}
+	}
+	in.Secrets = map[string]corev1.Secret{}
+	in.SecretErrors = map[string]error{}
+	for _, tls := range ing.Spec.TLS {
+		if tls.SecretName == "" {
+			continue
+		}
+		secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, tls.SecretName, metav1.GetOptions{})
+		if err != nil {
+			in.SecretErrors[tls.SecretName] = err
+			continue
+		}
+		in.Secrets[tls.SecretName] = *secret
+	}
+	if list, err := clientset.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{}); err != nil {
+		warn("ingresses de todos los namespaces", err)
+	} else {
+		in.AllIngresses = list.Items
+	}
+	events, err := listClusterEvents(clientset, namespace)
+	if err != nil {
+		warn("eventos", err)
+	}
+	in.Events = events
+	return in, nil
+}
+
+// resolveIngressClass devuelve el nombre de la clase del Ingress y su controlador: primero
+// spec.ingressClassName, después la anotación heredada y por último la clase por defecto.
+func resolveIngressClass(ing networkingv1.Ingress, classes []networkingv1.IngressClass) (name, controller string) {
+	if ing.Spec.IngressClassName != nil {
+		name = *ing.Spec.IngressClassName
+	} else if a, ok := ing.Annotations[ingressClassAnnotation]; ok {
+		name = a
+	}
+	for _, c := range classes {
+		if name == "" && c.Annotations[ingressClassDefaultAnnotation] == "true" {
+			name = c.Name
+		}
+		if c.Name == name {
+			return name, c.Spec.Controller
+		}
+	}
+	if ing.Spec.IngressClassName == nil {
+		if c, ok := legacyIngressControllers[name]; ok {
+			return name, c
+		}
+	}
+	return name, ""
+}
+
+// ingressRulePath es un par host/path de un Ingress con su backend.
+type ingressRulePath struct {
+	Host    string
+	Path    string
+	Backend networkingv1.IngressBackend
+}
+
+// ingressRulePaths devuelve los pares host/path del Ingress con su backend, incluido el
+// backend por defecto como host "*" y path "/*".
+func ingressRulePaths(ing networkingv1.Ingress) []ingressRulePath {
+	var result []ingressRulePath
+	if ing.Spec.DefaultBackend != nil {
+		result = append(result, ingressRulePath{Host: "*", Path: "/*", Backend: *ing.Spec.DefaultBackend})
+	}
+	for _, rule := range ing.Spec.Rules {
+		host := valueOrDefault(rule.Host, "*")
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, p := range rule.HTTP.Paths {
+			result = append(result, ingressRulePath{Host: host, Path: valueOrDefault(p.Path, "/"), Backend: p.Backend})
+		}
+	}
+	return result
+}
+
+// servicePortMatches busca el puerto del Service referenciado por el backend.
+func servicePortMatches(svc corev1.Service, port networkingv1.ServiceBackendPort) bool {
+	for _, p := range svc.Spec.Ports {
+		if (port.Name != "" && p.Name == port.Name) || (port.Name == "" && p.Port == port.Number) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatBackendPort(port networkingv1.ServiceBackendPort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	return fmt.Sprintf("%d", port.Number)
+}
+
+// parseLeafCertificate decodifica el primer certificado PEM de tls.crt.
+func parseLeafCertificate(data []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("tls.crt no contiene ningún certificado PEM")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// analyzeIngress aplica las comprobaciones de diagnose ingress.
+func analyzeIngress(in ingressInputs, now time.Time) ingressDiagnosis {
+	ing := in.Ingress
+	className, controller := resolveIngressClass(ing, in.Classes)
+	d := ingressDiagnosis{
+		Namespace:  ing.Namespace,
+		Ingress:    ing.Name,
+		Class:      className,
+		Controller: controller,
+		Address:    ingressAddress(ing),
+		Backends:   []ingressBackendCheck{},
+		TLS:        []ingressTLSCheck{},
+	}
+	object := fmt.Sprintf("Ingress %s/%s", ing.Namespace, ing.Name)
+	var findings []finding
+	add := func(severity, check, message, suggestion string) {
+		findings = append(findings, finding{Severity: severity, Check: check, Object: object, Message: message, Suggestion: suggestion})
+	}
+
+	// Clase y controlador.
+	switch {
+	case className == "" && in.Classes != nil:
+		add(severityCritical, "ingress.no-class", "El Ingress no indica ingressClassName y no hay ninguna IngressClass por defecto: ningún controlador lo atenderá.",
+			"Define spec.ingressClassName (ej. alb o nginx) o marca una IngressClass con ingressclass.kubernetes.io/is-default-class=true.")
+	case controller == "" && in.Classes != nil:
+		add(severityCritical, "ingress.class-missing", fmt.Sprintf("La IngressClass '%s' no existe.", className),
+			"Crea la IngressClass o corrige ingressClassName; lista las existentes con 'kubectl get ingressclass'.")
+	}
+	if ing.Spec.IngressClassName == nil && ing.Annotations[ingressClassAnnotation] != "" {
+		add(severityInfo, "ingress.legacy-annotation", fmt.Sprintf("Usa la anotación obsoleta %s=%s en lugar de spec.ingressClassName.", ingressClassAnnotation, ing.Annotations[ingressClassAnnotation]),
+			"Migra a spec.ingressClassName.")
+	}
+	if controller != "" {
+		if _, known := ingressControllerSelectors[controller]; !known {
+			add(severityInfo, "ingress.controller-unknown", fmt.Sprintf("No se puede comprobar si el controlador '%s' está en ejecución.", controller), "")
+		} else if in.ControllerPods != nil {
+			running := 0
+			for _, p := range in.ControllerPods {
+				if p.Status.Phase == corev1.PodRunning && isPodReady(p) {
+					running++
+				}
+			}
+			if running == 0 {
+				add(severityCritical, "ingress.controller-down", fmt.Sprintf("No hay pods listos del controlador '%s' (%d encontrados).", controller, len(in.ControllerPods)),
+					fmt.Sprintf("Instala o revisa el controlador (pods con %s); sin él el Ingress no se reconcilia.", ingressControllerSelectors[controller]))
+			}
+		}
+	}
+
+	// Backends.
+	isALB := controller == albController
+	targetTypeIP := ing.Annotations["alb.ingress.kubernetes.io/target-type"] == "ip"
+	for _, rp := range ingressRulePaths(ing) {
+		check := ingressBackendCheck{Host: rp.Host, Path: rp.Path, Status: "OK"}
+		where := fmt.Sprintf("%s%s", rp.Host, rp.Path)
+		if rp.Backend.Service == nil {
+			check.Status = "recurso"
+			d.Backends = append(d.Backends, check)
+			continue
+		}
+		check.Service = rp.Backend.Service.Name
+		check.Port = formatBackendPort(rp.Backend.Service.Port)
+		if in.Services == nil {
+			check.Status = "desconocido"
+			d.Backends = append(d.Backends, check)
+			continue
+		}
+		svc, ok := in.Services[check.Service]
+		switch {
+		case !ok:
+			check.Status = "sin service"
+			add(severityCritical, "ingress.backend-missing", fmt.Sprintf("La regla %s apunta al Service '%s', que no existe.", where, check.Service),
+				"Crea el Service o corrige el nombre en la regla del Ingress.")
+		case !servicePortMatches(svc, rp.Backend.Service.Port):
+			check.Status = "sin puerto"
+			add(severityCritical, "ingress.backend-port", fmt.Sprintf("La regla %s usa el puerto %s, que el Service '%s' no expone (%s).", where, check.Port, check.Service, strings.Join(servicePorts(svc), ",")),
+				"Usa un puerto (número o nombre) definido en el Service.")
+		default:
+			if isALB && !targetTypeIP && svc.Spec.Type == corev1.ServiceTypeClusterIP {
+				add(severityCritical, "ingress.alb-target-type", fmt.Sprintf("El Service '%s' es ClusterIP y el ALB usa target-type instance, que necesita NodePort.", check.Service),
+					"Añade la anotación alb.ingress.kubernetes.io/target-type: ip o cambia el Service a NodePort.")
+			}
+			if in.EndpointSlices != nil {
+				ready, _ := countEndpoints(in.EndpointSlices[check.Service])
+				check.ReadyEndpoints = ready
+				if ready == 0 {
+					check.Status = "sin endpoints"
+					add(severityCritical, "ingress.backend-no-endpoints", fmt.Sprintf("El Service '%s' de la regla %s no tiene endpoints listos.", check.Service, where),
+						fmt.Sprintf("Ejecuta 'eks-review diagnose service %s -n %s'.", check.Service, ing.Namespace))
+				}
+			}
+		}
+		d.Backends = append(d.Backends, check)
+	}
+
+	// TLS. El AWS Load Balancer Controller no usa los secrets: obtiene los certificados de ACM.
+	for _, tls := range ing.Spec.TLS {
+		hosts := tls.Hosts
+		check := ingressTLSCheck{Secret: tls.SecretName, Hosts: append([]string{}, hosts...), Status: "OK"}
+		secretObject := fmt.Sprintf("Secret %s/%s", ing.Namespace, tls.SecretName)
+		addTLS := func(severity, check, message, suggestion string) {
+			findings = append(findings, finding{Severity: severity, Check: check, Object: secretObject, Message: message, Suggestion: suggestion})
+		}
+		if isALB {
+			check.Status = "ACM"
+			d.TLS = append(d.TLS, check)
+			continue
+		}
+		if tls.SecretName == "" {
+			check.Status = "sin secret"
+			d.TLS = append(d.TLS, check)
+			continue
+		}
+		if err, failed := in.SecretErrors[tls.SecretName]; failed {
+			check.Status = "no legible"
+			if apierrors.IsNotFound(err) {
+				check.Status = "no existe"
+				addTLS(severityCritical, "ingress.tls-secret-missing", fmt.Sprintf("El secret TLS '%s' no existe.", tls.SecretName),
+					"Crea el secret (kubectl create secret tls) o revisa el Certificate de cert-manager que debería generarlo.")
+			} else {
+				addTLS(severityInfo, "ingress.tls-secret-unreadable", fmt.Sprintf("No se pudo leer el secret TLS '%s': %s", tls.SecretName, describeCollectError(err)), "")
+			}
+			d.TLS = append(d.TLS, check)
+			continue
+		}
+		secret, ok := in.Secrets[tls.SecretName]
+		if !ok {
+			d.TLS = append(d.TLS, check)
+			continue
+		}
+		cert, err := parseLeafCertificate(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			check.Status = "inválido"
+			addTLS(severityCritical, "ingress.tls-invalid", fmt.Sprintf("El secret '%s' no contiene un certificado válido: %v", tls.SecretName, err),
+				"Regenera el secret con un certificado PEM en tls.crt.")
+			d.TLS = append(d.TLS, check)
+			continue
+		}
+		check.Subject = cert.Subject.CommonName
+		check.NotAfter = cert.NotAfter.UTC()
+		switch {
+		case now.After(cert.NotAfter):
+			check.Status = "caducado"
+			addTLS(severityCritical, "ingress.tls-expired", fmt.Sprintf("El certificado de '%s' caducó el %s.", tls.SecretName, cert.NotAfter.UTC().Format("2006-01-02")),
+				"Renueva el certificado; si lo gestiona cert-manager, revisa el estado del Certificate y del Issuer.")
+		case now.Before(cert.NotBefore):
+			check.Status = "aún no válido"
+			addTLS(severityWarning, "ingress.tls-not-yet-valid", fmt.Sprintf("El certificado de '%s' no es válido hasta el %s.", tls.SecretName, cert.NotBefore.UTC().Format("2006-01-02")), "")
+		case cert.NotAfter.Sub(now) < certExpiryWarning:
+			check.Status = "caduca pronto"
+			addTLS(severityWarning, "ingress.tls-expiring", fmt.Sprintf("El certificado de '%s' caduca en %d días.", tls.SecretName, int(cert.NotAfter.Sub(now).Hours()/24)),
+				"Renueva el certificado antes de que caduque.")
+		}
+		var uncovered []string
+		for _, h := range hosts {
+			if cert.VerifyHostname(h) != nil {
+				uncovered = append(uncovered, h)
+			}
+		}
+		if len(uncovered) > 0 {
+			check.Status = "no cubre hosts"
+			addTLS(severityCritical, "ingress.tls-hosts", fmt.Sprintf("El certificado de '%s' no cubre: %s (SAN: %s).", tls.SecretName, strings.Join(uncovered, ", "), strings.Join(cert.DNSNames, ", ")),
+				"Emite un certificado que incluya esos hosts en los Subject Alternative Names.")
+		}
+		d.TLS = append(d.TLS, check)
+	}
+
+	// Conflictos host/path con otros Ingress de la misma clase (y dentro del mismo).
+	owners := map[string]string{}
+	for _, other := range in.AllIngresses {
+		if other.Namespace == ing.Namespace && other.Name == ing.Name {
+			continue
+		}
+		if otherClass, _ := resolveIngressClass(other, in.Classes); otherClass != className {
+			continue
+		}
+		for _, rp := range ingressRulePaths(other) {
+			owners[rp.Host+rp.Path] = other.Namespace + "/" + other.Name
+		}
+	}
+	seen := map[string]bool{}
+	for _, rp := range ingressRulePaths(ing) {
+		key := rp.Host + rp.Path
+		if seen[key] {
+			add(severityWarning, "ingress.duplicate-rule", fmt.Sprintf("La regla %s está duplicada dentro del Ingress.", key), "Elimina la regla repetida; solo una de ellas se aplicará.")
+		}
+		seen[key] = true
+		if owner, ok := owners[key]; ok && rp.Host != "*" {
+			add(severityWarning, "ingress.conflict", fmt.Sprintf("La regla %s también la define el Ingress %s de la clase '%s'.", key, owner, className),
+				"El controlador aplicará solo una de las dos (normalmente la más antigua); elimina o renombra una de ellas.")
+		}
+	}
+
+	if d.Address == "<none>" || d.Address == "<pending>" {
+		age := now.Sub(ing.CreationTimestamp.Time)
+		msg := fmt.Sprintf("El Ingress no tiene dirección asignada tras %s.", age.Truncate(time.Second))
+		for _, e := range in.Events {
+			if e.Kind == "Ingress" && e.Namespace == ing.Namespace && e.Name == ing.Name && e.Type == "Warning" {
+				msg += fmt.Sprintf(" Último aviso: %s: %s", e.Reason, truncateString(e.Message, 160))
+				break
+			}
+		}
+		severity := severityWarning
+		if age < loadBalancerGracePeriod {
+			severity = severityInfo
+		}
+		add(severity, "ingress.no-address", msg, "Revisa los logs del controlador de Ingress y los eventos del Ingress.")
+	}
+
+	sortFindings(findings)
+	d.Findings = findings
+	if d.Findings == nil {
+		d.Findings = []finding{}
+	}
+	return d
+}
+
+// printIngressDiagnosis imprime el informe de diagnose ingress.
+func printIngressDiagnosis(d ingressDiagnosis) {
+	fmt.Fprintf(os.Stdout, "\n--- Ingress %s/%s ---\n", d.Namespace, d.Ingress)
+	PrintBasicTable([]string{"CAMPO", "VALOR"}, [][]string{
+		{"Clase", valueOrNone(d.Class)},
+		{"Controlador", valueOrNone(d.Controller)},
+		{"Dirección", d.Address},
+	})
+
+	if len(d.Backends) > 0 {
+		fmt.Fprintln(os.Stdout, "--- Reglas ---")
+		rows := make([][]string, 0, len(d.Backends))
+		for _, b := range d.Backends {
+			backend := "<none>"
+			if b.Service != "" {
+				backend = b.Service + ":" + b.Port
+			}
+			rows = append(rows, []string{b.Host, b.Path, backend, fmt.Sprintf("%d", b.ReadyEndpoints), b.Status})
+		}
+		PrintBasicTable([]string{"HOST", "PATH", "BACKEND", "ENDPOINTS LISTOS", "ESTADO"}, rows)
+	}
+
+	if len(d.TLS) > 0 {
+		fmt.Fprintln(os.Stdout, "--- TLS ---")
+		rows := make([][]string, 0, len(d.TLS))
+		for _, t := range d.TLS {
+			expires := "<none>"
+			if !t.NotAfter.IsZero() {
+				expires = t.NotAfter.Format("2006-01-02")
+			}
+			rows = append(rows, []string{valueOrNone(t.Secret), strings.Join(t.Hosts, ","), expires, t.Status})
+		}
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+		PrintBasicTable([]string{"SECRET", "HOSTS", "EXPIRA", "ESTADO"}, rows)
+	}
+
+	printFindings("Hallazgos", d.Findings)
+}
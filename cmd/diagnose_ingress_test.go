@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func testCertificatePEM(t *testing.T, notAfter time.Time, hosts ...string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: hosts[0]},
+		DNSNames:     hosts,
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func testIngress(name, class, host, path, service string, port int32) networkingv1.Ingress {
+	prefix := networkingv1.PathTypePrefix
+	return networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: name},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: &class,
+			Rules: []networkingv1.IngressRule{{
+				Host: host,
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{{
+					Path: path, PathType: &prefix,
+					Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+						Name: service, Port: networkingv1.ServiceBackendPort{Number: port},
+					}},
+				}}}},
+			}},
+		},
+		Status: networkingv1.IngressStatus{LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+			Ingress: []networkingv1.IngressLoadBalancerIngress{{Hostname: "lb.example.com"}},
+		}},
+	}
+}
+
+func testIngressInputs(ing networkingv1.Ingress) ingressInputs {
+	ready := true
+	return ingressInputs{
+		Ingress: ing,
+		Classes: []networkingv1.IngressClass{{ObjectMeta: metav1.ObjectMeta{Name: "nginx"}, Spec: networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"}}},
+		ControllerPods: []corev1.Pod{{
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		}},
+		Services: map[string]corev1.Service{
+			"api": {ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "api"}, Spec: corev1.ServiceSpec{
+				Type: corev1.ServiceTypeClusterIP, Ports: []corev1.ServicePort{{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP}},
+			}},
+			"web": {ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "web"}, Spec: corev1.ServiceSpec{
+				Type: corev1.ServiceTypeClusterIP, Ports: []corev1.ServicePort{{Port: 80, Protocol: corev1.ProtocolTCP}},
+			}},
+		},
+		EndpointSlices: map[string][]discoveryv1.EndpointSlice{
+			"api": {{Endpoints: []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}}}}},
+		},
+		Secrets:      map[string]corev1.Secret{},
+		SecretErrors: map[string]error{},
+	}
+}
+
+func TestAnalyzeIngressHealthy(t *testing.T) {
+	d := analyzeIngress(testIngressInputs(testIngress("api", "nginx", "api.example.com", "/", "api", 80)), time.Now())
+	if got := strings.Join(findingChecks(d.Findings), ","); got != "" {
+		t.Errorf("expected no findings, got %s", got)
+	}
+	if d.Controller != "k8s.io/ingress-nginx" || d.Backends[0].ReadyEndpoints != 1 {
+		t.Errorf("unexpected diagnosis: %+v", d)
+	}
+}
+
+func TestAnalyzeIngressBackendsAndController(t *testing.T) {
+	ing := testIngress("api", "nginx", "api.example.com", "/", "api", 8080)
+	ing.Spec.Rules[0].HTTP.Paths = append(ing.Spec.Rules[0].HTTP.Paths,
+		networkingv1.HTTPIngressPath{Path: "/web", Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 80}}}},
+		networkingv1.HTTPIngressPath{Path: "/old", Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "gone", Port: networkingv1.ServiceBackendPort{Number: 80}}}},
+	)
+	in := testIngressInputs(ing)
+	in.ControllerPods = []corev1.Pod{}
+
+	got := strings.Join(findingChecks(analyzeIngress(in, time.Now()).Findings), ",")
+	for _, want := range []string{"ingress.controller-down", "ingress.backend-port", "ingress.backend-no-endpoints", "ingress.backend-missing"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %s in %s", want, got)
+		}
+	}
+
+	in = testIngressInputs(testIngress("api", "missing", "api.example.com", "/", "api", 80))
+	if got := strings.Join(findingChecks(analyzeIngress(in, time.Now()).Findings), ","); got != "ingress.class-missing" {
+		t.Errorf("missing class: got %s", got)
+	}
+}
+
+func TestAnalyzeIngressTLS(t *testing.T) {
+	now := time.Now()
+	ing := testIngress("api", "nginx", "api.example.com", "/", "api", 80)
+	ing.Spec.TLS = []networkingv1.IngressTLS{
+		{Hosts: []string{"api.example.com"}, SecretName: "valid"},
+		{Hosts: []string{"api.example.com"}, SecretName: "expired"},
+		{Hosts: []string{"www.example.com"}, SecretName: "wrong-host"},
+		{Hosts: []string{"api.example.com"}, SecretName: "missing"},
+	}
+	in := testIngressInputs(ing)
+	secret := func(pem []byte) corev1.Secret {
+		return corev1.Secret{Type: corev1.SecretTypeTLS, Data: map[string][]byte{corev1.TLSCertKey: pem}}
+	}
+	in.Secrets["valid"] = secret(testCertificatePEM(t, now.Add(60*24*time.Hour), "*.example.com"))
+	in.Secrets["expired"] = secret(testCertificatePEM(t, now.Add(-24*time.Hour), "api.example.com"))
+	in.Secrets["wrong-host"] = secret(testCertificatePEM(t, now.Add(60*24*time.Hour), "api.example.com"))
+	in.SecretErrors["missing"] = apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "missing")
+
+	d := analyzeIngress(in, now)
+	want := map[string]string{"valid": "OK", "expired": "caducado", "wrong-host": "no cubre hosts", "missing": "no existe"}
+	for _, tls := range d.TLS {
+		if tls.Status != want[tls.Secret] {
+			t.Errorf("secret %s: got status %q, want %q", tls.Secret, tls.Status, want[tls.Secret])
+		}
+	}
+	if got := strings.Join(findingChecks(d.Findings), ","); got != "ingress.tls-expired,ingress.tls-hosts,ingress.tls-secret-missing" {
+		t.Errorf("got checks %s", got)
+	}
+}
+
+func TestAnalyzeIngressConflicts(t *testing.T) {
+	ing := testIngress("api", "nginx", "api.example.com", "/", "api", 80)
+	in := testIngressInputs(ing)
+	in.AllIngresses = []networkingv1.Ingress{
+		ing,
+		testIngress("api-old", "nginx", "api.example.com", "/", "api", 80),
+		testIngress("api-alb", "alb", "api.example.com", "/", "api", 80),
+	}
+	d := analyzeIngress(in, time.Now())
+	if got := strings.Join(findingChecks(d.Findings), ","); got != "ingress.conflict" {
+		t.Fatalf("got checks %s, want ingress.conflict", got)
+	}
+	if !strings.Contains(d.Findings[0].Message, "prod/api-old") {
+		t.Errorf("conflict should name the other ingress, got %q", d.Findings[0].Message)
+	}
+}
+
+func TestResolveIngressClass(t *testing.T) {
+	classes := []networkingv1.IngressClass{{
+		ObjectMeta: metav1.ObjectMeta{Name: "alb", Annotations: map[string]string{ingressClassDefaultAnnotation: "true"}},
+		Spec:       networkingv1.IngressClassSpec{Controller: albController},
+	}}
+	if name, controller := resolveIngressClass(networkingv1.Ingress{}, classes); name != "alb" || controller != albController {
+		t.Errorf("default class: got %s/%s", name, controller)
+	}
+	legacy := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{ingressClassAnnotation: "nginx"}}}
+	if name, controller := resolveIngressClass(legacy, nil); name != "nginx" || controller != "k8s.io/ingress-nginx" {
+		t.Errorf("legacy annotation: got %s/%s", name, controller)
+	}
+}
@@ -0,0 +1,657 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	diagnoseJobsAllNamespaces  bool
+	diagnoseJobsTail           int64
+	diagnoseJobsStaleIntervals int
+)
+
+const (
+	// defaultBackoffLimit es el backoffLimit que aplica Kubernetes si el Job no lo define.
+	defaultBackoffLimit = 6
+	// missedScheduleGrace es el margen que se da al controlador para crear el Job de una
+	// activación cuando el CronJob no define startingDeadlineSeconds.
+	missedScheduleGrace = 2 * time.Minute
+	// maxMissedSchedules es el número de activaciones perdidas a partir del cual el
+	// controlador de CronJobs deja de contarlas.
+	maxMissedSchedules = 100
+	// maxJobFailedPods es el número máximo de pods fallidos que se detallan por Job.
+	maxJobFailedPods = 5
+)
+
+// jobFailedPod es un pod de un Job que terminó con error.
+type jobFailedPod struct {
+	Pod         string            `json:"pod"`
+	Container   string            `json:"container,omitempty"`
+	Termination *terminationInfo  `json:"termination"`
+	Log         *containerLogTail `json:"log,omitempty"`
+	previous    bool
+}
+
+// jobReport resume el estado de un Job.
+type jobReport struct {
+	Namespace    string         `json:"namespace"`
+	Name         string         `json:"name"`
+	CronJob      string         `json:"cronJob,omitempty"`
+	Status       string         `json:"status"`
+	Reason       string         `json:"reason,omitempty"`
+	Succeeded    int32          `json:"succeeded"`
+	Completions  int32          `json:"completions"`
+	Failed       int32          `json:"failed"`
+	BackoffLimit int32          `json:"backoffLimit"`
+	Duration     string         `json:"duration,omitempty"`
+	FailedPods   []jobFailedPod `json:"failedPods,omitempty"`
+}
+
+// cronJobReport resume la programación y el historial de un CronJob.
+type cronJobReport struct {
+	Namespace         string     `json:"namespace"`
+	Name              string     `json:"name"`
+	Schedule          string     `json:"schedule"`
+	TimeZone          string     `json:"timeZone,omitempty"`
+	ConcurrencyPolicy string     `json:"concurrencyPolicy"`
+	Suspended         bool       `json:"suspended"`
+	Active            int        `json:"active"`
+	LastSchedule      *time.Time `json:"lastScheduleTime,omitempty"`
+	LastSuccessful    *time.Time `json:"lastSuccessfulTime,omitempty"`
+	NextSchedule      *time.Time `json:"nextScheduleTime,omitempty"`
+	MissedSchedules   int        `json:"missedSchedules"`
+}
+
+// jobsDiagnosis es el informe de diagnose jobs.
+type jobsDiagnosis struct {
+	Namespace string          `json:"namespace,omitempty"`
+	CronJobs  []cronJobReport `json:"cronJobs"`
+	Jobs      []jobReport     `json:"jobs"`
+	Findings  []finding       `json:"findings"`
+}
+
+// jobsInputs son los datos que necesita analyzeJobs. CronJobs, Pods y Events a nil
+// indican que no se pudieron listar y se omiten las comprobaciones que dependen de ellos.
+type jobsInputs struct {
+	Jobs     []batchv1.Job
+	CronJobs []batchv1.CronJob
+	Pods     []corev1.Pod
+	Events   []clusterEvent
+	Logs     map[string]containerLogTail // por jobLogKey
+}
+
+var diagnoseJobsCmd = &cobra.Command{
+	Use:     "jobs",
+	Aliases: []string{"cronjobs"},
+	Short:   "Analiza los Jobs fallidos y los CronJobs que no se ejecutan como deberían.",
+	Long: `El comando diagnose jobs revisa los Jobs y CronJobs del namespace:
+
+  - Jobs fallidos por agotar backoffLimit (BackoffLimitExceeded) o por superar
+    activeDeadlineSeconds (DeadlineExceeded), con el exit code y el final del log del
+    último pod que falló,
+  - Jobs que siguen reintentando tras fallos,
+  - CronJobs cuya última ejecución con éxito es más antigua que N intervalos de su
+    programación (calculados a partir de la expresión cron),
+  - activaciones perdidas, conflictos de concurrencyPolicy (Forbid con un Job que no
+    termina, Allow con ejecuciones solapadas, Replace que cancela Jobs en curso),
+  - CronJobs suspendidos o con una programación no válida.
+
+Los fallos de Jobs de un CronJob que ya volvió a terminar con éxito no se reportan.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if diagnoseJobsStaleIntervals < 1 {
+			return fmt.Errorf("--stale-intervals debe ser mayor que 0")
+		}
+		clients, err := GetKubeClients()
+		if err != nil {
+			return fmt.Errorf("creando clientes de Kubernetes: %w", err)
+		}
+		namespace := GetEffectiveNamespace(diagnoseNamespace, diagnoseJobsAllNamespaces, "default", false)
+		outputLower := strings.ToLower(diagnoseOutputFormat)
+		info := os.Stdout
+		if isStructuredOutput(outputLower) {
+			info = os.Stderr
+		}
+
+		fmt.Fprintf(info, "Analizando Jobs y CronJobs en %s...\n", clusterScopeLabel(namespace))
+		in, err := collectJobsInputs(context.TODO(), clients.Core, namespace, diagnoseJobsTail)
+		if err != nil {
+			return err
+		}
+		d := analyzeJobs(in, time.Now(), diagnoseJobsStaleIntervals)
+		d.Namespace = namespace
+
+		if isStructuredOutput(outputLower) {
+			doc := struct {
+				outputHeader
+				jobsDiagnosis
+			}{newOutputHeader("JobsDiagnosis"), d}
+			return writeStructuredOutput(os.Stdout, outputLower, doc)
+		}
+		printJobsDiagnosis(d)
+		return nil
+	},
+}
+
+func init() {
+	diagnoseCmd.AddCommand(diagnoseJobsCmd)
+	diagnoseJobsCmd.Flags().BoolVarP(&diagnoseJobsAllNamespaces, "all-namespaces", "A", false, "Analizar los Jobs y CronJobs de todos los namespaces")
+	diagnoseJobsCmd.Flags().Int64Var(&diagnoseJobsTail, "tail", 20, "Número de líneas de log del último pod fallido de cada Job")
+	diagnoseJobsCmd.Flags().IntVar(&diagnoseJobsStaleIntervals, "stale-intervals", 3, "Número de intervalos de la programación sin una ejecución con éxito a partir del cual se avisa")
+}
+
+// collectJobsInputs lista los Jobs, CronJobs, pods y eventos del ámbito y recupera el
+// log del último pod fallido de cada Job que requiere atención. Solo falla si no se
+// pueden listar los Jobs.
+func collectJobsInputs(ctx context.Context, clientset kubernetes.Interface, namespace string, tail int64) (jobsInputs, error) {
+	jobs, err := clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return jobsInputs{}, fmt.Errorf("listando jobs: %w", err)
+	}
+	in := jobsInputs{Jobs: jobs.Items, Logs: map[string]containerLogTail{}}
+	warn := func(resource string, err error) {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar %s: %s\n", resource, describeCollectError(err))
+	}
+	if list, err := clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("cronjobs", err)
+	} else {
+		in.CronJobs = append([]batchv1.CronJob{}, list.Items...)
+	}
+	if list, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("pods", err)
+	} else {
+		in.Pods = append([]corev1.Pod{}, list.Items...)
+	}
+	if in.Events, err = listClusterEvents(clientset, namespace); err != nil {
+		warn("eventos", err)
+	}
+
+	for _, job := range in.Jobs {
+		if job.Status.Failed == 0 || jobSuperseded(job, in.CronJobs) {
+			continue
+		}
+		failed := jobFailedPods(job, in.Pods)
+		if len(failed) == 0 || failed[0].Container == "" {
+			continue
+		}
+		for _, p := range in.Pods {
+			if p.Namespace == job.Namespace && p.Name == failed[0].Pod {
+				in.Logs[jobLogKey(p.Namespace, p.Name, failed[0].Container)] = fetchLogTail(ctx, clientset, p, failed[0].Container, failed[0].previous, tail)
+			}
+		}
+	}
+	return in, nil
+}
+
+// jobLogKey es la clave de jobsInputs.Logs: "namespace/pod/contenedor".
+func jobLogKey(namespace, pod, container string) string {
+	return namespace + "/" + pod + "/" + container
+}
+
+// jobCronJob devuelve el nombre del CronJob que creó el Job, o "".
+func jobCronJob(job batchv1.Job) string {
+	if owner := metav1.GetControllerOf(&job); owner != nil && owner.Kind == "CronJob" {
+		return owner.Name
+	}
+	return ""
+}
+
+// jobSuperseded indica si el Job pertenece a un CronJob que terminó con éxito después
+// de crearlo: sus fallos ya no son relevantes.
+func jobSuperseded(job batchv1.Job, cronJobs []batchv1.CronJob) bool {
+	name := jobCronJob(job)
+	if name == "" {
+		return false
+	}
+	for _, cj := range cronJobs {
+		if cj.Namespace == job.Namespace && cj.Name == name && cj.Status.LastSuccessfulTime != nil {
+			return job.CreationTimestamp.Time.Before(cj.Status.LastSuccessfulTime.Time)
+		}
+	}
+	return false
+}
+
+// jobCondition devuelve la condición del tipo indicado si está activa, o nil.
+func jobCondition(job batchv1.Job, condType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i, c := range job.Status.Conditions {
+		if c.Type == condType && c.Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// jobFailedPods devuelve los pods del Job que terminaron con error, del más reciente al
+// más antiguo. Para cada pod se toma el primer contenedor con exit code distinto de
+// cero (estado actual o, con restartPolicy OnFailure, última terminación); si ninguno
+// terminó, la razón del propio pod (ej. Evicted, DeadlineExceeded).
+func jobFailedPods(job batchv1.Job, pods []corev1.Pod) []jobFailedPod {
+	var failed []jobFailedPod
+	for _, p := range pods {
+		owner := metav1.GetControllerOf(&p)
+		if p.Namespace != job.Namespace || owner == nil || owner.Kind != "Job" || owner.Name != job.Name {
+			continue
+		}
+		var entry *jobFailedPod
+		statuses := append(append([]corev1.ContainerStatus{}, p.Status.InitContainerStatuses...), p.Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			if t := cs.State.Terminated; t != nil && (t.ExitCode != 0 || t.Reason == "OOMKilled") {
+				entry = &jobFailedPod{Pod: p.Name, Container: cs.Name, Termination: newTerminationInfo(t)}
+			} else if t := cs.LastTerminationState.Terminated; t != nil && (t.ExitCode != 0 || t.Reason == "OOMKilled") {
+				entry = &jobFailedPod{Pod: p.Name, Container: cs.Name, Termination: newTerminationInfo(t), previous: true}
+			}
+			if entry != nil {
+				break
+			}
+		}
+		if entry == nil && p.Status.Phase == corev1.PodFailed {
+			finished := p.CreationTimestamp.Time
+			for _, c := range p.Status.Conditions {
+				if c.LastTransitionTime.After(finished) {
+					finished = c.LastTransitionTime.Time
+				}
+			}
+			entry = &jobFailedPod{Pod: p.Name, Termination: &terminationInfo{Reason: valueOrDefault(p.Status.Reason, "Failed"), Message: p.Status.Message, FinishedAt: finished}}
+		}
+		if entry != nil {
+			failed = append(failed, *entry)
+		}
+	}
+	sort.SliceStable(failed, func(i, j int) bool {
+		return failed[i].Termination.FinishedAt.After(failed[j].Termination.FinishedAt)
+	})
+	return failed
+}
+
+// terminationSummary describe una terminación como "exit 1 (Error)".
+func terminationSummary(t *terminationInfo) string {
+	s := fmt.Sprintf("exit %d (%s)", t.ExitCode, valueOrDefault(t.Reason, "Error"))
+	if t.Signal > 0 {
+		s = fmt.Sprintf("exit %d (%s, señal %d)", t.ExitCode, valueOrDefault(t.Reason, "Error"), t.Signal)
+	}
+	return s
+}
+
+// jobFailureSuggestion propone una acción según cómo terminó el último pod fallido.
+func jobFailureSuggestion(job batchv1.Job, last *jobFailedPod) string {
+	if last == nil {
+		return fmt.Sprintf("Revisa los eventos del Job: kubectl describe job %s -n %s", job.Name, job.Namespace)
+	}
+	t := last.Termination
+	switch {
+	case t.Reason == "OOMKilled":
+		return fmt.Sprintf("El contenedor '%s' se quedó sin memoria: aumenta resources.limits.memory o reduce el tamaño del lote que procesa el Job.", last.Container)
+	case t.ExitCode == 126 || t.ExitCode == 127:
+		return fmt.Sprintf("El contenedor '%s' no pudo ejecutar su comando: revisa command/args y el ENTRYPOINT de la imagen.", last.Container)
+	case last.Container == "":
+		return fmt.Sprintf("El pod %s falló sin que terminara ningún contenedor (%s): revisa sus eventos.", last.Pod, t.Reason)
+	}
+	return fmt.Sprintf("Corrige la causa antes de relanzarlo; log completo: eks-review monitor logs --pod %s -n %s -c %s", last.Pod, job.Namespace, last.Container)
+}
+
+// cronJobLocation devuelve la zona horaria en la que se evalúa la programación: la de
+// spec.timeZone o UTC, la del controlador en EKS.
+func cronJobLocation(cj batchv1.CronJob) *time.Location {
+	if cj.Spec.TimeZone != nil {
+		if loc, err := time.LoadLocation(*cj.Spec.TimeZone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// analyzeJobs aplica las comprobaciones de diagnose jobs.
+func analyzeJobs(in jobsInputs, now time.Time, staleIntervals int) jobsDiagnosis {
+	d := jobsDiagnosis{CronJobs: []cronJobReport{}, Jobs: []jobReport{}}
+	var findings []finding
+
+	jobs := append([]batchv1.Job{}, in.Jobs...)
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if jobs[i].Namespace != jobs[j].Namespace {
+			return jobs[i].Namespace < jobs[j].Namespace
+		}
+		return jobs[i].CreationTimestamp.Time.After(jobs[j].CreationTimestamp.Time)
+	})
+	for _, job := range jobs {
+		object := fmt.Sprintf("Job %s/%s", job.Namespace, job.Name)
+		add := func(severity, check, message, suggestion string) {
+			findings = append(findings, finding{Severity: severity, Check: check, Object: object, Message: message, Suggestion: suggestion})
+		}
+		backoffLimit := int32(defaultBackoffLimit)
+		if job.Spec.BackoffLimit != nil {
+			backoffLimit = *job.Spec.BackoffLimit
+		}
+		r := jobReport{
+			Namespace:    job.Namespace,
+			Name:         job.Name,
+			CronJob:      jobCronJob(job),
+			Status:       jobState(job),
+			Succeeded:    job.Status.Succeeded,
+			Completions:  jobCompletions(job),
+			Failed:       job.Status.Failed,
+			BackoffLimit: backoffLimit,
+		}
+		if job.Status.StartTime != nil {
+			end := now
+			if job.Status.CompletionTime != nil {
+				end = job.Status.CompletionTime.Time
+			} else if c := jobCondition(job, batchv1.JobFailed); c != nil && !c.LastTransitionTime.IsZero() {
+				end = c.LastTransitionTime.Time
+			}
+			r.Duration = end.Sub(job.Status.StartTime.Time).Truncate(time.Second).String()
+		}
+		failedCond := jobCondition(job, batchv1.JobFailed)
+		if failedCond != nil {
+			r.Reason = failedCond.Reason
+		}
+
+		if (job.Status.Failed == 0 && failedCond == nil) || jobSuperseded(job, in.CronJobs) {
+			d.Jobs = append(d.Jobs, r)
+			continue
+		}
+		var last *jobFailedPod
+		if in.Pods != nil {
+			failed := jobFailedPods(job, in.Pods)
+			for i := range failed {
+				if l, ok := in.Logs[jobLogKey(job.Namespace, failed[i].Pod, failed[i].Container)]; ok && failed[i].Container != "" {
+					failed[i].Log = &l
+				}
+			}
+			if len(failed) > 0 {
+				last = &failed[0]
+			}
+			if len(failed) > maxJobFailedPods {
+				failed = failed[:maxJobFailedPods]
+			}
+			r.FailedPods = failed
+		}
+		d.Jobs = append(d.Jobs, r)
+
+		lastFailure := ""
+		if last != nil {
+			lastFailure = fmt.Sprintf(" Último fallo: pod %s", last.Pod)
+			if last.Container != "" {
+				lastFailure += fmt.Sprintf(", contenedor '%s'", last.Container)
+			}
+			lastFailure += ", " + terminationSummary(last.Termination) + "."
+			if last.Log != nil {
+				if line := lastLogErrorLine(last.Log.Lines); line != "" {
+					lastFailure += fmt.Sprintf(" Último error en el log: %q", truncateString(line, 160))
+				}
+			}
+		}
+		switch {
+		case failedCond != nil && failedCond.Reason == "BackoffLimitExceeded":
+			add(severityCritical, "job.backoff-limit-exceeded", fmt.Sprintf("El Job agotó sus reintentos (backoffLimit=%d) con %d %s.%s",
+				backoffLimit, job.Status.Failed, pluralize(int(job.Status.Failed), "pod fallido", "pods fallidos"), lastFailure),
+				jobFailureSuggestion(job, last))
+		case failedCond != nil && failedCond.Reason == "DeadlineExceeded":
+			deadline := "activeDeadlineSeconds"
+			if job.Spec.ActiveDeadlineSeconds != nil {
+				deadline = fmt.Sprintf("activeDeadlineSeconds=%d", *job.Spec.ActiveDeadlineSeconds)
+			}
+			msg := fmt.Sprintf("El Job superó %s y Kubernetes terminó sus pods", deadline)
+			if r.Duration != "" {
+				msg += fmt.Sprintf(" tras %s", r.Duration)
+			}
+			add(severityCritical, "job.deadline-exceeded", msg+"."+lastFailure,
+				"Comprueba si el Job se quedó bloqueado (dependencias externas, locks) o si el volumen de trabajo ha crecido; en ese caso, sube activeDeadlineSeconds o divide el trabajo.")
+		case failedCond != nil:
+			add(severityCritical, "job.failed", fmt.Sprintf("El Job falló (%s): %s%s", valueOrDefault(failedCond.Reason, "Failed"), valueOrNone(failedCond.Message), lastFailure),
+				jobFailureSuggestion(job, last))
+		case jobCondition(job, batchv1.JobComplete) == nil:
+			add(severityWarning, "job.retrying", fmt.Sprintf("El Job lleva %d %s de %d reintentos permitidos y sigue en ejecución.%s",
+				job.Status.Failed, pluralize(int(job.Status.Failed), "fallo", "fallos"), backoffLimit, lastFailure),
+				jobFailureSuggestion(job, last))
+		}
+	}
+
+	cronJobs := append([]batchv1.CronJob{}, in.CronJobs...)
+	sort.SliceStable(cronJobs, func(i, j int) bool {
+		if cronJobs[i].Namespace != cronJobs[j].Namespace {
+			return cronJobs[i].Namespace < cronJobs[j].Namespace
+		}
+		return cronJobs[i].Name < cronJobs[j].Name
+	})
+	for _, cj := range cronJobs {
+		object := fmt.Sprintf("CronJob %s/%s", cj.Namespace, cj.Name)
+		add := func(severity, check, message, suggestion string) {
+			findings = append(findings, finding{Severity: severity, Check: check, Object: object, Message: message, Suggestion: suggestion})
+		}
+		r := cronJobReport{
+			Namespace:         cj.Namespace,
+			Name:              cj.Name,
+			Schedule:          cj.Spec.Schedule,
+			ConcurrencyPolicy: valueOrDefault(string(cj.Spec.ConcurrencyPolicy), string(batchv1.AllowConcurrent)),
+			Suspended:         cj.Spec.Suspend != nil && *cj.Spec.Suspend,
+			Active:            len(cj.Status.Active),
+		}
+		if cj.Spec.TimeZone != nil {
+			r.TimeZone = *cj.Spec.TimeZone
+		}
+		if cj.Status.LastScheduleTime != nil {
+			t := cj.Status.LastScheduleTime.Time
+			r.LastSchedule = &t
+		}
+		if cj.Status.LastSuccessfulTime != nil {
+			t := cj.Status.LastSuccessfulTime.Time
+			r.LastSuccessful = &t
+		}
+		var cjEvents []clusterEvent
+		for _, e := range in.Events {
+			if e.Kind == "CronJob" && e.Namespace == cj.Namespace && e.Name == cj.Name {
+				cjEvents = append(cjEvents, e)
+			}
+		}
+		eventCount := func(reason string) (int32, string) {
+			var count int32
+			message := ""
+			for _, e := range cjEvents {
+				if e.Reason == reason {
+					count += e.Count
+					message = e.Message
+				}
+			}
+			return count, message
+		}
+		if count, message := eventCount("FailedCreate"); count > 0 {
+			add(severityCritical, "cronjob.create-failed", fmt.Sprintf("El controlador no pudo crear el Job en %d %s: %s", count, pluralize(int(count), "ocasión", "ocasiones"), truncateString(message, 160)),
+				"Revisa las ResourceQuotas, LimitRanges y webhooks de admisión del namespace.")
+		}
+
+		schedule, err := parseCronSchedule(cj.Spec.Schedule)
+		if err != nil {
+			add(severityCritical, "cronjob.schedule-invalid", fmt.Sprintf("La programación '%s' no es válida: %s.", cj.Spec.Schedule, err),
+				"Corrige spec.schedule con una expresión cron de cinco campos (ej. '0 3 * * *').")
+			d.CronJobs = append(d.CronJobs, r)
+			continue
+		}
+		loc := cronJobLocation(cj)
+		localNow := now.In(loc)
+		if next := schedule.next(localNow); !next.IsZero() {
+			r.NextSchedule = &next
+		}
+
+		if r.Suspended {
+			add(severityInfo, "cronjob.suspended", "El CronJob está suspendido (spec.suspend=true) y no crea Jobs nuevos.",
+				fmt.Sprintf("Si no es intencionado, reactívalo: kubectl patch cronjob %s -n %s -p '{\"spec\":{\"suspend\":false}}'", cj.Name, cj.Namespace))
+			d.CronJobs = append(d.CronJobs, r)
+			continue
+		}
+
+		// Ejecuciones que el controlador debería haber lanzado ya y no constan.
+		reference := cj.CreationTimestamp.Time
+		if r.LastSchedule != nil {
+			reference = *r.LastSchedule
+		}
+		grace := missedScheduleGrace
+		if cj.Spec.StartingDeadlineSeconds != nil {
+			grace = time.Duration(*cj.Spec.StartingDeadlineSeconds) * time.Second
+		}
+		missed, _ := schedule.activationsBetween(reference.In(loc), localNow.Add(-grace), maxMissedSchedules)
+		r.MissedSchedules = missed
+
+		// Conflictos de concurrencia.
+		forbidBlocked := false
+		switch cj.Spec.ConcurrencyPolicy {
+		case batchv1.ForbidConcurrent:
+			skipped, _ := eventCount("JobAlreadyActive")
+			var oldest *batchv1.Job
+			for _, ref := range cj.Status.Active {
+				for i, job := range in.Jobs {
+					if job.Namespace == cj.Namespace && job.Name == ref.Name && (oldest == nil || job.CreationTimestamp.Before(&oldest.CreationTimestamp)) {
+						oldest = &in.Jobs[i]
+					}
+				}
+			}
+			if oldest != nil {
+				blocked, _ := schedule.activationsBetween(oldest.CreationTimestamp.Time.In(loc), localNow, maxMissedSchedules)
+				if blocked > 0 {
+					forbidBlocked = true
+					add(severityWarning, "cronjob.concurrency-conflict", fmt.Sprintf("El Job %s lleva en ejecución %s y, con concurrencyPolicy=Forbid, %d %s programadas se han omitido mientras tanto.",
+						oldest.Name, now.Sub(oldest.CreationTimestamp.Time).Truncate(time.Second), blocked, pluralize(blocked, "ejecución", "ejecuciones")),
+						"Revisa si el Job está bloqueado; si su duración normal supera el intervalo, espacia la programación o define activeDeadlineSeconds.")
+				}
+			} else if skipped > 0 {
+				add(severityWarning, "cronjob.concurrency-conflict", fmt.Sprintf("Se omitieron %d %s porque el Job anterior seguía en ejecución (concurrencyPolicy=Forbid).", skipped, pluralize(int(skipped), "ejecución", "ejecuciones")),
+					"La duración de los Jobs supera a veces el intervalo: espacia la programación o acota su duración con activeDeadlineSeconds.")
+			}
+		case batchv1.ReplaceConcurrent:
+			if replaced, _ := eventCount("SuccessfulDelete"); replaced > 0 {
+				add(severityWarning, "cronjob.concurrency-conflict", fmt.Sprintf("Se cancelaron %d %s en curso al llegar la siguiente activación (concurrencyPolicy=Replace).", replaced, pluralize(int(replaced), "Job", "Jobs")),
+					"Los Jobs no terminan dentro del intervalo y nunca llegan a completarse: espacia la programación o reduce el trabajo de cada ejecución.")
+			}
+		default:
+			if r.Active > 1 {
+				add(severityWarning, "cronjob.concurrency-conflict", fmt.Sprintf("Hay %d Jobs del CronJob ejecutándose a la vez (concurrencyPolicy=Allow).", r.Active),
+					"Si las ejecuciones no deben solaparse, usa concurrencyPolicy: Forbid o Replace y acota la duración con activeDeadlineSeconds.")
+			}
+		}
+
+		if missed > 0 && !forbidBlocked {
+			count := fmt.Sprintf("%d", missed)
+			if missed >= maxMissedSchedules {
+				count = fmt.Sprintf("más de %d", maxMissedSchedules)
+			}
+			since := "su creación"
+			if r.LastSchedule != nil {
+				since = "la última programada (" + r.LastSchedule.UTC().Format(time.RFC3339) + ")"
+			}
+			msg := fmt.Sprintf("No constan %s %s desde %s.", count, pluralize(missed, "ejecución programada", "ejecuciones programadas"), since)
+			if tooMany, _ := eventCount("TooManyMissedTimes"); tooMany > 0 {
+				msg += " El controlador ha registrado eventos TooManyMissedTimes."
+			}
+			suggestion := "Revisa los eventos del CronJob y el estado del controlador; si el clúster estuvo parado, las ejecuciones perdidas no se recuperan."
+			if cj.Spec.StartingDeadlineSeconds != nil {
+				suggestion = fmt.Sprintf("startingDeadlineSeconds=%d es muy ajustado: si el controlador no crea el Job a tiempo, la ejecución se pierde. Súbelo o elimínalo.", *cj.Spec.StartingDeadlineSeconds)
+			}
+			add(severityWarning, "cronjob.missed-schedules", msg, suggestion)
+		}
+
+		// Última ejecución con éxito demasiado antigua.
+		successRef := cj.CreationTimestamp.Time
+		if r.LastSuccessful != nil {
+			successRef = *r.LastSuccessful
+		}
+		runs, _ := schedule.activationsBetween(successRef.In(loc), localNow, maxMissedSchedules)
+		if runs > staleIntervals {
+			var msg string
+			if r.LastSuccessful == nil {
+				msg = fmt.Sprintf("El CronJob nunca ha terminado con éxito y se han programado %d ejecuciones desde su creación.", runs)
+			} else {
+				msg = fmt.Sprintf("La última ejecución con éxito fue %s y desde entonces se han programado %d ejecuciones (umbral: %d intervalos).",
+					strings.ToLower(formatEventAge(*r.LastSuccessful)), runs, staleIntervals)
+			}
+			add(severityCritical, "cronjob.stale-success", msg,
+				"Revisa los hallazgos de sus Jobs y el log de la última ejecución fallida; si los Jobs terminan bien pero no se programan, revisa las activaciones perdidas y la concurrencyPolicy.")
+		}
+		d.CronJobs = append(d.CronJobs, r)
+	}
+
+	sortFindings(findings)
+	d.Findings = findings
+	if d.Findings == nil {
+		d.Findings = []finding{}
+	}
+	return d
+}
+
+// printJobsDiagnosis imprime el informe de diagnose jobs.
+func printJobsDiagnosis(d jobsDiagnosis) {
+	fmt.Fprintf(os.Stdout, "\n--- CronJobs (%d) ---\n", len(d.CronJobs))
+	if len(d.CronJobs) == 0 {
+		fmt.Fprintln(os.Stdout, "No se encontraron cronjobs.")
+	} else {
+		formatTime := func(t *time.Time) string {
+			if t == nil {
+				return "<none>"
+			}
+			return t.UTC().Format(time.RFC3339)
+		}
+		rows := make([][]string, 0, len(d.CronJobs))
+		for _, cj := range d.CronJobs {
+			schedule := cj.Schedule
+			if cj.TimeZone != "" {
+				schedule += " (" + cj.TimeZone + ")"
+			}
+			rows = append(rows, []string{cj.Namespace + "/" + cj.Name, schedule, cj.ConcurrencyPolicy, fmt.Sprintf("%t", cj.Suspended), fmt.Sprintf("%d", cj.Active),
+				formatTime(cj.LastSchedule), formatTime(cj.LastSuccessful), formatTime(cj.NextSchedule)})
+		}
+		PrintBasicTable([]string{"NOMBRE", "PROGRAMACIÓN", "CONCURRENCIA", "SUSPENDIDO", "ACTIVOS", "ÚLTIMA PROGRAMACIÓN", "ÚLTIMO ÉXITO", "SIGUIENTE"}, rows)
+	}
+
+	fmt.Fprintf(os.Stdout, "--- Jobs (%d) ---\n", len(d.Jobs))
+	if len(d.Jobs) == 0 {
+		fmt.Fprintln(os.Stdout, "No se encontraron jobs.")
+	} else {
+		rows := make([][]string, 0, len(d.Jobs))
+		for _, j := range d.Jobs {
+			status := j.Status
+			if j.Reason != "" {
+				status += " (" + j.Reason + ")"
+			}
+			rows = append(rows, []string{j.Namespace + "/" + j.Name, valueOrNone(j.CronJob), status, fmt.Sprintf("%d/%d", j.Succeeded, j.Completions),
+				fmt.Sprintf("%d/%d", j.Failed, j.BackoffLimit), valueOrNone(j.Duration)})
+		}
+		PrintBasicTable([]string{"NOMBRE", "CRONJOB", "ESTADO", "COMPLETADOS", "FALLOS/LÍMITE", "DURACIÓN"}, rows)
+	}
+
+	for _, j := range d.Jobs {
+		if len(j.FailedPods) == 0 {
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "--- Pods fallidos del Job %s/%s ---\n", j.Namespace, j.Name)
+		rows := make([][]string, 0, len(j.FailedPods))
+		for _, p := range j.FailedPods {
+			rows = append(rows, []string{p.Pod, valueOrNone(p.Container), terminationSummary(p.Termination), formatEventAge(p.Termination.FinishedAt)})
+		}
+		PrintBasicTable([]string{"POD", "CONTENEDOR", "TERMINACIÓN", "FINALIZADO"}, rows)
+		for _, p := range j.FailedPods {
+			if p.Log == nil {
+				continue
+			}
+			fmt.Fprintf(os.Stdout, "--- Log de '%s' en %s ---\n", p.Container, p.Pod)
+			if p.Log.Error != "" {
+				fmt.Fprintf(os.Stdout, "No disponible: %s\n", p.Log.Error)
+			} else if len(p.Log.Lines) == 0 {
+				fmt.Fprintln(os.Stdout, "(vacío)")
+			}
+			for _, line := range p.Log.Lines {
+				fmt.Fprintln(os.Stdout, line)
+			}
+		}
+	}
+
+	printFindings("Hallazgos", d.Findings)
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var testJobsNow = time.Date(2026, 3, 14, 11, 58, 0, 0, time.UTC)
+
+// testJob devuelve un Job de prod creado hace una hora, opcionalmente de un CronJob.
+func testJob(name, cronJob string) batchv1.Job {
+	controller := true
+	job := batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: name, CreationTimestamp: metav1.NewTime(testJobsNow.Add(-time.Hour))}}
+	if cronJob != "" {
+		job.OwnerReferences = []metav1.OwnerReference{{Kind: "CronJob", Name: cronJob, Controller: &controller}}
+	}
+	start := metav1.NewTime(testJobsNow.Add(-time.Hour))
+	job.Status.StartTime = &start
+	return job
+}
+
+// testJobPod devuelve un pod del Job cuyo contenedor "app" terminó con el exit code indicado.
+func testJobPod(name, job string, exitCode int32) corev1.Pod {
+	controller := true
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: name, OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: job, Controller: &controller}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodFailed,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				Reason: "Error", ExitCode: exitCode, FinishedAt: metav1.NewTime(testJobsNow.Add(-30 * time.Minute)),
+			}}}},
+		},
+	}
+}
+
+func testFailedCondition(reason string) []batchv1.JobCondition {
+	return []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: reason, LastTransitionTime: metav1.NewTime(testJobsNow.Add(-20 * time.Minute))}}
+}
+
+// testCronJob devuelve un CronJob de prod creado hace 30 días.
+func testCronJob(name, schedule string, lastSchedule, lastSuccess time.Time) batchv1.CronJob {
+	cj := batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: name, CreationTimestamp: metav1.NewTime(testJobsNow.Add(-30 * 24 * time.Hour))},
+		Spec:       batchv1.CronJobSpec{Schedule: schedule},
+	}
+	if !lastSchedule.IsZero() {
+		t := metav1.NewTime(lastSchedule)
+		cj.Status.LastScheduleTime = &t
+	}
+	if !lastSuccess.IsZero() {
+		t := metav1.NewTime(lastSuccess)
+		cj.Status.LastSuccessfulTime = &t
+	}
+	return cj
+}
+
+// objectChecks devuelve los hallazgos como "objeto:check", ordenados.
+func objectChecks(findings []finding) []string {
+	var out []string
+	for _, f := range findings {
+		out = append(out, f.Object+":"+f.Check)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestAnalyzeJobsFailures(t *testing.T) {
+	backoff := int32(2)
+	failed := testJob("backup-1", "")
+	failed.Spec.BackoffLimit = &backoff
+	failed.Status.Failed = 3
+	failed.Status.Conditions = testFailedCondition("BackoffLimitExceeded")
+
+	deadline := int64(600)
+	slow := testJob("report-1", "")
+	slow.Spec.ActiveDeadlineSeconds = &deadline
+	slow.Status.Failed = 1
+	slow.Status.Conditions = testFailedCondition("DeadlineExceeded")
+
+	retrying := testJob("import-1", "")
+	retrying.Status.Failed = 1
+	retrying.Status.Active = 1
+
+	// Falló, pero su CronJob volvió a terminar con éxito después.
+	old := testJob("nightly-1", "nightly")
+	old.Status.Failed = 7
+	old.Status.Conditions = testFailedCondition("BackoffLimitExceeded")
+	nightly := testCronJob("nightly", "0 * * * *", testJobsNow.Add(-58*time.Minute), testJobsNow.Add(-50*time.Minute))
+
+	in := jobsInputs{
+		Jobs:     []batchv1.Job{failed, slow, retrying, old, testJob("ok-1", "")},
+		CronJobs: []batchv1.CronJob{nightly},
+		Pods:     []corev1.Pod{testJobPod("backup-1-a", "backup-1", 1), testJobPod("nightly-1-a", "nightly-1", 1)},
+		Logs: map[string]containerLogTail{"prod/backup-1-a/app": {Container: "app", Lines: []string{
+			"starting backup", "ERROR: connection refused (db:5432)", "exiting",
+		}}},
+	}
+	d := analyzeJobs(in, testJobsNow, 3)
+	want := "Job prod/backup-1:job.backoff-limit-exceeded,Job prod/import-1:job.retrying,Job prod/report-1:job.deadline-exceeded"
+	if got := strings.Join(objectChecks(d.Findings), ","); got != want {
+		t.Fatalf("got checks %s, want %s", got, want)
+	}
+	f := d.Findings[0]
+	if f.Object != "Job prod/backup-1" {
+		f = d.Findings[1]
+	}
+	if !strings.Contains(f.Message, "backoffLimit=2") || !strings.Contains(f.Message, "exit 1 (Error)") || !strings.Contains(f.Message, "connection refused") {
+		t.Errorf("unexpected backoff message: %q", f.Message)
+	}
+	for _, j := range d.Jobs {
+		if j.Name == "backup-1" && (len(j.FailedPods) != 1 || j.FailedPods[0].Log == nil || j.FailedPods[0].Termination.ExitCode != 1) {
+			t.Errorf("unexpected failed pods: %+v", j.FailedPods)
+		}
+		if j.Name == "report-1" && j.Duration != "40m0s" {
+			t.Errorf("deadline job duration: got %s", j.Duration)
+		}
+	}
+}
+
+func TestAnalyzeJobsCronJobs(t *testing.T) {
+	hour := func(h int) time.Time { return time.Date(2026, 3, 14, h, 0, 0, 0, time.UTC) }
+
+	stale := testCronJob("stale", "0 * * * *", hour(11), hour(7).Add(5*time.Minute))
+	missed := testCronJob("missed", "0 */6 * * *", hour(-6), hour(-6).Add(10*time.Minute))
+	healthy := testCronJob("healthy", "0 */6 * * *", hour(6), hour(6).Add(10*time.Minute))
+
+	forbid := testCronJob("forbid", "*/5 * * * *", hour(11), hour(10).Add(50*time.Minute))
+	forbid.Spec.ConcurrencyPolicy = batchv1.ForbidConcurrent
+	forbid.Status.Active = []corev1.ObjectReference{{Name: "forbid-1"}}
+	running := testJob("forbid-1", "forbid")
+	running.CreationTimestamp = metav1.NewTime(hour(11))
+	running.Status.Active = 1
+
+	allow := testCronJob("allow", "*/30 * * * *", hour(11).Add(30*time.Minute), hour(11).Add(20*time.Minute))
+	allow.Status.Active = []corev1.ObjectReference{{Name: "allow-1"}, {Name: "allow-2"}}
+
+	suspend := true
+	suspended := testCronJob("suspended", "0 * * * *", hour(-48), hour(-48))
+	suspended.Spec.Suspend = &suspend
+
+	invalid := testCronJob("invalid", "0 25 * * *", time.Time{}, time.Time{})
+
+	in := jobsInputs{
+		Jobs:     []batchv1.Job{running},
+		CronJobs: []batchv1.CronJob{stale, missed, healthy, forbid, allow, suspended, invalid},
+		Pods:     []corev1.Pod{},
+	}
+	d := analyzeJobs(in, testJobsNow, 3)
+	want := []string{
+		"CronJob prod/allow:cronjob.concurrency-conflict",
+		"CronJob prod/forbid:cronjob.concurrency-conflict",
+		"CronJob prod/forbid:cronjob.stale-success",
+		"CronJob prod/invalid:cronjob.schedule-invalid",
+		"CronJob prod/missed:cronjob.missed-schedules",
+		"CronJob prod/stale:cronjob.stale-success",
+		"CronJob prod/suspended:cronjob.suspended",
+	}
+	if got := strings.Join(objectChecks(d.Findings), ","); got != strings.Join(want, ",") {
+		t.Fatalf("got checks %s", got)
+	}
+	for _, f := range d.Findings {
+		if f.Check == "cronjob.missed-schedules" && !strings.Contains(f.Message, "No constan 2 ejecuciones programadas") {
+			t.Errorf("unexpected missed message: %q", f.Message)
+		}
+	}
+	for _, cj := range d.CronJobs {
+		if cj.Name == "healthy" && (cj.NextSchedule == nil || !cj.NextSchedule.Equal(hour(12))) {
+			t.Errorf("healthy next schedule: got %v", cj.NextSchedule)
+		}
+	}
+}
@@ -0,0 +1,411 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Veredictos de diagnose node.
+const (
+	nodeVerdictHealthy       = "healthy"
+	nodeVerdictDegraded      = "degraded"
+	nodeVerdictShouldReplace = "should-replace"
+)
+
+const (
+	nodeLeaseNamespace = "kube-node-lease"
+	// leaseStaleAfter es la antigüedad del Lease del kubelet a partir de la cual el
+	// latido se considera perdido (el kubelet lo renueva cada 10s).
+	leaseStaleAfter = time.Minute
+	// notReadyReplaceAfter es el tiempo NotReady a partir del cual conviene reemplazar el nodo.
+	notReadyReplaceAfter = 10 * time.Minute
+	// saturationPercent es el porcentaje de requests sobre allocatable que se considera saturado.
+	saturationPercent = 95
+)
+
+// nodeSystemComponents son los DaemonSets de kube-system que deben tener un pod sano en
+// cada nodo de EKS, identificados por la etiqueta k8s-app.
+var nodeSystemComponents = []string{"aws-node", "kube-proxy"}
+
+// nodeProblemConditions son condiciones de node-problem-detector que indican que el
+// sistema operativo del nodo está dañado.
+var nodeProblemConditions = map[corev1.NodeConditionType]bool{
+	"KernelDeadlock":        true,
+	"ReadonlyFilesystem":    true,
+	"CorruptDockerOverlay2": true,
+}
+
+// evictedPod es un pod desalojado del nodo.
+type evictedPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Message   string `json:"message"`
+}
+
+// systemPodStatus es el estado de un pod de sistema (aws-node, kube-proxy) en el nodo.
+type systemPodStatus struct {
+	Component string `json:"component"`
+	Pod       string `json:"pod,omitempty"`
+	Ready     bool   `json:"ready"`
+	Status    string `json:"status"`
+	Restarts  int32  `json:"restarts"`
+}
+
+// nodeDiagnosis es el informe de diagnose node.
+type nodeDiagnosis struct {
+	nodeSummary
+	Verdict       string                 `json:"verdict"`
+	LastHeartbeat *time.Time             `json:"lastHeartbeat,omitempty"`
+	Conditions    []corev1.NodeCondition `json:"conditions"`
+	EvictedPods   []evictedPod           `json:"evictedPods"`
+	SystemPods    []systemPodStatus      `json:"systemPods"`
+	Events        []clusterEvent         `json:"events"`
+	Findings      []finding              `json:"findings"`
+}
+
+// nodeDiagnosisInputs reúne lo que necesita analyzeNode. Lease es nil si no se pudo leer
+// y SystemDaemonSets es nil si no se pudieron listar los DaemonSets de kube-system.
+type nodeDiagnosisInputs struct {
+	Node             corev1.Node
+	Pods             []corev1.Pod
+	Lease            *coordinationv1.Lease
+	SystemDaemonSets map[string]bool
+	Usage            corev1.ResourceList
+	Events           []clusterEvent
+}
+
+var diagnoseNodeCmd = &cobra.Command{
+	Use:   "node <nombre-del-nodo>",
+	Short: "Evalúa la salud de un nodo y si conviene reemplazarlo.",
+	Long: `El comando diagnose node revisa la condición Ready y las de presión, la
+antigüedad del último latido del kubelet (Lease en kube-node-lease), si el nodo está
+cordoned, los pods desalojados, la saturación de requests frente a allocatable, el
+estado de los pods aws-node y kube-proxy del nodo y sus eventos.
+
+Termina con un veredicto: healthy, degraded o should-replace.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clients, err := GetKubeClients()
+		if err != nil {
+			return fmt.Errorf("creando clientes de Kubernetes: %w", err)
+		}
+		outputLower := strings.ToLower(diagnoseOutputFormat)
+		info := os.Stdout
+		if isStructuredOutput(outputLower) {
+			info = os.Stderr
+		}
+
+		fmt.Fprintf(info, "Diagnosticando nodo '%s'...\n", args[0])
+		in, err := collectNodeDiagnosisInputs(context.TODO(), clients, args[0])
+		if err != nil {
+			return err
+		}
+		diag := analyzeNode(in, time.Now())
+
+		if isStructuredOutput(outputLower) {
+			doc := struct {
+				outputHeader
+				nodeDiagnosis
+			}{newOutputHeader("NodeDiagnosis"), diag}
+			return writeStructuredOutput(os.Stdout, outputLower, doc)
+		}
+		printNodeDiagnosis(diag)
+		return nil
+	},
+}
+
+func init() {
+	diagnoseCmd.AddCommand(diagnoseNodeCmd)
+}
+
+// collectNodeDiagnosisInputs recupera el nodo, sus pods, su Lease, los DaemonSets de
+// sistema, su uso (si hay metrics-server) y sus eventos.
+func collectNodeDiagnosisInputs(ctx context.Context, clients *KubeClients, nodeName string) (nodeDiagnosisInputs, error) {
+	clientset := clients.Core
+	node, err := clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nodeDiagnosisInputs{}, fmt.Errorf("obteniendo nodo '%s': %w", nodeName, err)
+	}
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + nodeName})
+	if err != nil {
+		return nodeDiagnosisInputs{}, fmt.Errorf("listando pods del nodo '%s': %w", nodeName, err)
+	}
+	in := nodeDiagnosisInputs{Node: *node, Pods: pods.Items}
+
+	if lease, err := clientset.CoordinationV1().Leases(nodeLeaseNamespace).Get(ctx, nodeName, metav1.GetOptions{}); err == nil {
+		in.Lease = lease
+	} else if Verbose {
+		fmt.Fprintf(os.Stderr, "DEBUG: No se pudo leer el Lease del nodo: %v\n", err)
+	}
+	if list, err := clientset.AppsV1().DaemonSets("kube-system").List(ctx, metav1.ListOptions{}); err != nil {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar daemonsets de kube-system: %s\n", describeCollectError(err))
+	} else {
+		in.SystemDaemonSets = map[string]bool{}
+		for _, ds := range list.Items {
+			in.SystemDaemonSets[ds.Name] = true
+		}
+	}
+	if clients.Metrics != nil {
+		if m, err := clients.Metrics.MetricsV1beta1().NodeMetricses().Get(ctx, nodeName, metav1.GetOptions{}); err == nil {
+			in.Usage = m.Usage
+		} else if Verbose {
+			fmt.Fprintf(os.Stderr, "DEBUG: No se pudieron obtener métricas del nodo %s: %v\n", nodeName, err)
+		}
+	}
+	events, err := listNodeEvents(clientset, nodeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar eventos: %s\n", describeCollectError(err))
+	}
+	in.Events = dedupeEvents(filterEvents(events, eventFilter{ForKind: "Node", ForName: nodeName}, time.Now()))
+	sortEventsByRecent(in.Events)
+	return in, nil
+}
+
+// analyzeNode aplica las comprobaciones de diagnose node y calcula el veredicto.
+func analyzeNode(in nodeDiagnosisInputs, now time.Time) nodeDiagnosis {
+	node := in.Node
+	var usage map[string]corev1.ResourceList
+	if in.Usage != nil {
+		usage = map[string]corev1.ResourceList{node.Name: in.Usage}
+	}
+	d := nodeDiagnosis{
+		nodeSummary: buildNodeSummaries([]corev1.Node{node}, in.Pods, usage)[0],
+		Conditions:  node.Status.Conditions,
+		EvictedPods: []evictedPod{},
+		SystemPods:  []systemPodStatus{},
+		Events:      in.Events,
+	}
+	if d.Conditions == nil {
+		d.Conditions = []corev1.NodeCondition{}
+	}
+	if d.Events == nil {
+		d.Events = []clusterEvent{}
+	}
+	object := "Node " + node.Name
+	var findings []finding
+	// replace marca los hallazgos que por sí solos justifican reemplazar el nodo.
+	replace := false
+	add := func(severity, check, message, suggestion string) {
+		findings = append(findings, finding{Severity: severity, Check: check, Object: object, Message: message, Suggestion: suggestion})
+	}
+
+	// Ready y latido.
+	for _, c := range node.Status.Conditions {
+		switch {
+		case c.Type == corev1.NodeReady && c.Status != corev1.ConditionTrue:
+			since := now.Sub(c.LastTransitionTime.Time).Truncate(time.Second)
+			msg := fmt.Sprintf("El nodo está %s desde hace %s (%s).", getNodeStatus(node), since, valueOrDefault(c.Message, c.Reason))
+			if since >= notReadyReplaceAfter {
+				replace = true
+				add(severityCritical, "node.not-ready", msg, "Lleva demasiado tiempo sin recuperarse: drena y reemplaza el nodo (termina la instancia EC2 y deja que el nodegroup/Karpenter cree otra).")
+			} else {
+				add(severityCritical, "node.not-ready", msg, "Revisa el kubelet y el runtime en la instancia (SSM: journalctl -u kubelet) y los pods aws-node/kube-proxy.")
+			}
+		case c.Type == corev1.NodeNetworkUnavailable && c.Status == corev1.ConditionTrue:
+			add(severityCritical, "node.network-unavailable", fmt.Sprintf("NetworkUnavailable: %s", valueOrDefault(c.Message, c.Reason)),
+				"Revisa el pod aws-node del nodo (CNI) y los límites de ENIs/IPs de la instancia.")
+		case (c.Type == corev1.NodeMemoryPressure || c.Type == corev1.NodeDiskPressure || c.Type == corev1.NodePIDPressure) && c.Status == corev1.ConditionTrue:
+			add(severityWarning, "node.pressure", fmt.Sprintf("%s activo: %s", c.Type, valueOrDefault(c.Message, c.Reason)),
+				"El kubelet desaloja pods mientras dure la presión; revisa los pods que más consumen o amplía el disco/instancia.")
+		case nodeProblemConditions[c.Type] && c.Status == corev1.ConditionTrue:
+			replace = true
+			add(severityCritical, "node.os-problem", fmt.Sprintf("node-problem-detector informa %s: %s", c.Type, valueOrDefault(c.Message, c.Reason)),
+				"El sistema operativo del nodo está dañado: drénalo y reemplázalo.")
+		}
+	}
+	if in.Lease != nil && in.Lease.Spec.RenewTime != nil {
+		renew := in.Lease.Spec.RenewTime.Time
+		d.LastHeartbeat = &renew
+		if age := now.Sub(renew); age > leaseStaleAfter {
+			if age >= notReadyReplaceAfter {
+				replace = true
+			}
+			add(severityCritical, "node.heartbeat-stale", fmt.Sprintf("El kubelet no renueva su Lease desde hace %s.", age.Truncate(time.Second)),
+				"El kubelet no se comunica con el API server: comprueba que la instancia sigue en ejecución y su conectividad (security groups, endpoint del clúster).")
+		}
+	}
+
+	// Cordon y retirada.
+	if node.Spec.Unschedulable {
+		add(severityWarning, "node.cordoned", "El nodo está cordoned (SchedulingDisabled): no recibe pods nuevos.",
+			fmt.Sprintf("Si no hay un drain o una actualización en curso, ejecuta 'kubectl uncordon %s'.", node.Name))
+	}
+	for _, t := range node.Spec.Taints {
+		if t.Key == "ToBeDeletedByClusterAutoscaler" || t.Key == "karpenter.sh/disrupted" || t.Key == "karpenter.sh/disruption" {
+			add(severityInfo, "node.being-removed", fmt.Sprintf("El nodo tiene el taint %s: el autoescalador lo está retirando.", t.Key), "")
+		}
+	}
+
+	// Pods desalojados y pods de sistema.
+	for _, p := range in.Pods {
+		if p.Status.Reason == "Evicted" {
+			d.EvictedPods = append(d.EvictedPods, evictedPod{Namespace: p.Namespace, Name: p.Name, Message: p.Status.Message})
+		}
+	}
+	if n := len(d.EvictedPods); n > 0 {
+		add(severityWarning, "node.evictions", fmt.Sprintf("Pods desalojados en este nodo: %d (ej. %s/%s: %s).", n,
+			d.EvictedPods[0].Namespace, d.EvictedPods[0].Name, truncateString(d.EvictedPods[0].Message, 100)),
+			"Revisa los requests/limits de memoria y disco efímero de esos pods; borra los pods Evicted cuando hayas terminado.")
+	}
+	for _, component := range nodeSystemComponents {
+		status := systemPodStatus{Component: component, Status: "ausente"}
+		for _, p := range in.Pods {
+			if p.Namespace != "kube-system" || p.Labels["k8s-app"] != component || isTerminatedPod(p) {
+				continue
+			}
+			status = systemPodStatus{Component: component, Pod: p.Name, Ready: isPodReady(p), Status: podDisplayStatus(p)}
+			for _, cs := range p.Status.ContainerStatuses {
+				status.Restarts += cs.RestartCount
+			}
+		}
+		if status.Pod == "" && (in.SystemDaemonSets == nil || !in.SystemDaemonSets[component]) {
+			continue
+		}
+		d.SystemPods = append(d.SystemPods, status)
+		switch {
+		case status.Pod == "":
+			add(severityCritical, "node.system-pod-missing", fmt.Sprintf("No hay pod de %s en el nodo aunque el DaemonSet existe.", component),
+				fmt.Sprintf("Revisa los nodeSelector/tolerations del DaemonSet %s y los eventos del nodo.", component))
+		case !status.Ready:
+			add(severityCritical, "node.system-pod-unhealthy", fmt.Sprintf("El pod %s (%s) no está listo: %s.", status.Pod, component, status.Status),
+				fmt.Sprintf("Ejecuta 'eks-review diagnose pod %s -n kube-system'; sin %s el nodo no tiene red de pods o servicios.", status.Pod, component))
+		case status.Restarts >= highRestartCount:
+			add(severityWarning, "node.system-pod-restarts", fmt.Sprintf("El pod %s (%s) acumula %d reinicios.", status.Pod, component, status.Restarts),
+				fmt.Sprintf("Revisa sus logs anteriores: eks-review monitor logs --pod %s -n kube-system --previous", status.Pod))
+		}
+	}
+
+	// Saturación de allocatable.
+	if d.CPUAllocatable > 0 && d.CPURequested*100 >= d.CPUAllocatable*saturationPercent {
+		add(severityWarning, "node.cpu-saturated", fmt.Sprintf("Los requests de CPU ocupan el %s del allocatable (%s de %s).", formatPercent(d.CPURequested, d.CPUAllocatable), formatMilliCPU(d.CPURequested), formatMilliCPU(d.CPUAllocatable)),
+			"El nodo no admite más pods con requests de CPU; revisa los requests sobredimensionados con 'eks-review monitor top pods'.")
+	}
+	if d.MemoryAllocatable > 0 && d.MemoryRequested*100 >= d.MemoryAllocatable*saturationPercent {
+		add(severityWarning, "node.memory-saturated", fmt.Sprintf("Los requests de memoria ocupan el %s del allocatable (%s de %s).", formatPercent(d.MemoryRequested, d.MemoryAllocatable), formatBytes(d.MemoryRequested), formatBytes(d.MemoryAllocatable)),
+			"El nodo no admite más pods con requests de memoria; revisa los requests o usa instancias mayores.")
+	}
+	if d.PodsAllocatable > 0 && int64(d.Pods) >= d.PodsAllocatable {
+		add(severityWarning, "node.pods-full", fmt.Sprintf("El nodo aloja %d/%d pods, su máximo.", d.Pods, d.PodsAllocatable),
+			"En EKS el máximo depende de las ENIs/IPs de la instancia; activa prefix delegation o usa instancias mayores.")
+	}
+	if d.MemoryUsage != nil && d.MemoryAllocatable > 0 && *d.MemoryUsage*100 >= d.MemoryAllocatable*saturationPercent {
+		add(severityWarning, "node.memory-usage", fmt.Sprintf("El uso real de memoria es el %s del allocatable.", formatPercent(*d.MemoryUsage, d.MemoryAllocatable)),
+			"Riesgo inminente de MemoryPressure y desalojos; identifica los pods que más consumen.")
+	}
+
+	// Eventos de aviso del nodo.
+	var warningReasons []string
+	seen := map[string]bool{}
+	for _, e := range in.Events {
+		if e.Type == "Warning" && !seen[e.Reason] {
+			seen[e.Reason] = true
+			warningReasons = append(warningReasons, e.Reason)
+		}
+	}
+	if len(warningReasons) > 0 {
+		add(severityWarning, "node.warning-events", fmt.Sprintf("Eventos de aviso recientes en el nodo: %s.", strings.Join(warningReasons, ", ")),
+			"Revisa la tabla de eventos; SystemOOM, Rebooted o FreeDiskSpaceFailed repetidos suelen anticipar un fallo del nodo.")
+	}
+
+	sortFindings(findings)
+	d.Findings = findings
+	if d.Findings == nil {
+		d.Findings = []finding{}
+	}
+	d.Verdict = nodeHealthVerdict(findings, replace)
+	return d
+}
+
+// nodeHealthVerdict resume los hallazgos: should-replace si alguno justifica reemplazar el nodo,
+// degraded si hay algún aviso o problema crítico y healthy en otro caso.
+func nodeHealthVerdict(findings []finding, replace bool) string {
+	if replace {
+		return nodeVerdictShouldReplace
+	}
+	for _, f := range findings {
+		if f.Severity == severityCritical || f.Severity == severityWarning {
+			return nodeVerdictDegraded
+		}
+	}
+	return nodeVerdictHealthy
+}
+
+func nodeVerdictColor(verdict string) string {
+	switch verdict {
+	case nodeVerdictShouldReplace:
+		return colorRed
+	case nodeVerdictDegraded:
+		return colorYellow
+	}
+	return colorGreen
+}
+
+// printNodeDiagnosis imprime el informe de diagnose node.
+func printNodeDiagnosis(d nodeDiagnosis) {
+	fmt.Fprintf(os.Stdout, "\n--- Nodo %s ---\n", d.Name)
+	heartbeat := "<none>"
+	if d.LastHeartbeat != nil {
+		heartbeat = formatEventAge(*d.LastHeartbeat)
+	}
+	PrintBasicTable([]string{"CAMPO", "VALOR"}, [][]string{
+		{"Estado", d.Status},
+		{"Último latido (Lease)", heartbeat},
+		{"Nodegroup", valueOrNone(d.EKS.Nodegroup)},
+		{"Pods", fmt.Sprintf("%d/%d", d.Pods, d.PodsAllocatable)},
+		{"CPU (requests)", fmt.Sprintf("%s/%s (%s)", formatMilliCPU(d.CPURequested), formatMilliCPU(d.CPUAllocatable), formatPercent(d.CPURequested, d.CPUAllocatable))},
+		{"Memoria (requests)", fmt.Sprintf("%s/%s (%s)", formatBytes(d.MemoryRequested), formatBytes(d.MemoryAllocatable), formatPercent(d.MemoryRequested, d.MemoryAllocatable))},
+	})
+
+	fmt.Fprintln(os.Stdout, "--- Condiciones ---")
+	rows := make([][]string, 0, len(d.Conditions))
+	for _, c := range d.Conditions {
+		rows = append(rows, []string{string(c.Type), string(c.Status), valueOrNone(c.Reason), formatEventAge(c.LastTransitionTime.Time)})
+	}
+	PrintBasicTable([]string{"TIPO", "ESTADO", "RAZÓN", "ÚLTIMO CAMBIO"}, rows)
+
+	if len(d.SystemPods) > 0 {
+		fmt.Fprintln(os.Stdout, "--- Pods de sistema ---")
+		rows = rows[:0]
+		for _, p := range d.SystemPods {
+			rows = append(rows, []string{p.Component, valueOrNone(p.Pod), fmt.Sprintf("%t", p.Ready), p.Status, fmt.Sprintf("%d", p.Restarts)})
+		}
+		PrintBasicTable([]string{"COMPONENTE", "POD", "LISTO", "ESTADO", "REINICIOS"}, rows)
+	}
+
+	if len(d.EvictedPods) > 0 {
+		fmt.Fprintf(os.Stdout, "--- Pods desalojados (%d) ---\n", len(d.EvictedPods))
+		rows = rows[:0]
+		for i, p := range d.EvictedPods {
+			if i == maxNodeDetailRows {
+				break
+			}
+			rows = append(rows, []string{p.Namespace, p.Name, truncateString(p.Message, 100)})
+		}
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+		PrintBasicTable([]string{"NAMESPACE", "POD", "MENSAJE"}, rows)
+	}
+
+	if len(d.Events) > 0 {
+		fmt.Fprintf(os.Stdout, "--- Eventos (%d) ---\n", len(d.Events))
+		rows = rows[:0]
+		for i, e := range d.Events {
+			if i == maxNodeDetailRows {
+				break
+			}
+			rows = append(rows, []string{formatEventAge(e.LastSeen), fmt.Sprintf("%d", e.Count), e.Type, e.Reason, truncateString(e.Message, 100)})
+		}
+		PrintBasicTable([]string{"ÚLTIMA VEZ", "CUENTA", "TIPO", "RAZÓN", "MENSAJE"}, rows)
+	}
+
+	printFindings("Hallazgos", d.Findings)
+	fmt.Fprintf(os.Stdout, "\nVeredicto: %s\n", colorize(d.Verdict, nodeVerdictColor(d.Verdict)))
+}
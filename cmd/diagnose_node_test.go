@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testSystemPod(name, component, node string, ready bool) corev1.Pod {
+	p := testServicePod(name, map[string]string{"k8s-app": component}, ready)
+	p.Namespace = "kube-system"
+	p.Spec.NodeName = node
+	return p
+}
+
+func testNodeInputs(now time.Time) nodeDiagnosisInputs {
+	renew := metav1.NewMicroTime(now.Add(-5 * time.Second))
+	return nodeDiagnosisInputs{
+		Node: testSchedulingNode("ip-10-0-1-1", "us-east-1a", "2"),
+		Pods: []corev1.Pod{
+			testSystemPod("aws-node-abc", "aws-node", "ip-10-0-1-1", true),
+			testSystemPod("kube-proxy-abc", "kube-proxy", "ip-10-0-1-1", true),
+			testRunningPod("api-1", "ip-10-0-1-1", "500m"),
+		},
+		Lease:            &coordinationv1.Lease{Spec: coordinationv1.LeaseSpec{RenewTime: &renew}},
+		SystemDaemonSets: map[string]bool{"aws-node": true, "kube-proxy": true},
+	}
+}
+
+func TestAnalyzeNodeHealthy(t *testing.T) {
+	now := time.Now()
+	d := analyzeNode(testNodeInputs(now), now)
+	if d.Verdict != nodeVerdictHealthy || len(d.Findings) != 0 {
+		t.Fatalf("expected healthy node without findings, got %s %v", d.Verdict, findingChecks(d.Findings))
+	}
+	if len(d.SystemPods) != 2 || !d.SystemPods[0].Ready {
+		t.Errorf("unexpected system pods: %+v", d.SystemPods)
+	}
+}
+
+func TestAnalyzeNodeDegraded(t *testing.T) {
+	now := time.Now()
+	in := testNodeInputs(now)
+	in.Node.Spec.Unschedulable = true
+	in.Node.Status.Conditions = append(in.Node.Status.Conditions, corev1.NodeCondition{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue, Reason: "KubeletHasDiskPressure"})
+	in.Pods[0].Status.Conditions[0].Status = corev1.ConditionFalse
+	in.Pods = append(in.Pods[:1], in.Pods[2:]...)
+	in.Pods = append(in.Pods, testRunningPod("big", "ip-10-0-1-1", "1500m"))
+	evicted := testRunningPod("old", "ip-10-0-1-1", "100m")
+	evicted.Status = corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", Message: "The node was low on resource: ephemeral-storage."}
+	in.Pods = append(in.Pods, evicted)
+	in.Events = []clusterEvent{{Type: "Warning", Reason: "FreeDiskSpaceFailed"}, {Type: "Normal", Reason: "NodeNotSchedulable"}}
+
+	d := analyzeNode(in, now)
+	got := strings.Join(findingChecks(d.Findings), ",")
+	want := "node.system-pod-unhealthy,node.system-pod-missing,node.pressure,node.cordoned,node.evictions,node.cpu-saturated,node.warning-events"
+	if got != want {
+		t.Errorf("got checks %s, want %s", got, want)
+	}
+	if d.Verdict != nodeVerdictDegraded {
+		t.Errorf("got verdict %s, want degraded", d.Verdict)
+	}
+}
+
+func TestAnalyzeNodeShouldReplace(t *testing.T) {
+	now := time.Now()
+	in := testNodeInputs(now)
+	in.Node.Status.Conditions[0] = corev1.NodeCondition{
+		Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Reason: "NodeStatusUnknown",
+		LastTransitionTime: metav1.NewTime(now.Add(-30 * time.Minute)),
+	}
+	renew := metav1.NewMicroTime(now.Add(-30 * time.Minute))
+	in.Lease.Spec.RenewTime = &renew
+
+	d := analyzeNode(in, now)
+	if got := strings.Join(findingChecks(d.Findings), ","); got != "node.not-ready,node.heartbeat-stale" {
+		t.Errorf("got checks %s", got)
+	}
+	if d.Verdict != nodeVerdictShouldReplace {
+		t.Errorf("got verdict %s, want should-replace", d.Verdict)
+	}
+
+	in = testNodeInputs(now)
+	in.Node.Status.Conditions = append(in.Node.Status.Conditions, corev1.NodeCondition{Type: "ReadonlyFilesystem", Status: corev1.ConditionTrue})
+	if d := analyzeNode(in, now); d.Verdict != nodeVerdictShouldReplace {
+		t.Errorf("read-only filesystem: got verdict %s, want should-replace", d.Verdict)
+	}
+}
@@ -0,0 +1,845 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+var diagnosePendingAllNamespaces bool
+
+// maxPendingNodeRows limita la tabla por nodo; con más nodos basta el resumen por restricción.
+const maxPendingNodeRows = 20
+
+// Restricciones de scheduling evaluadas por diagnose pending. Los recursos extendidos
+// (ej. nvidia.com/gpu) se reportan como "insufficient-<recurso>".
+const (
+	constraintUnschedulable   = "unschedulable"
+	constraintNotReady        = "not-ready"
+	constraintNodeSelector    = "node-selector"
+	constraintNodeAffinity    = "node-affinity"
+	constraintTaint           = "taint"
+	constraintInsufficient    = "insufficient-"
+	constraintTooManyPods     = "too-many-pods"
+	constraintPodAffinity     = "pod-affinity"
+	constraintPodAntiAffinity = "pod-anti-affinity"
+	constraintTopologySpread  = "topology-spread"
+	constraintVolumeZone      = "volume-zone"
+)
+
+// constraintSuggestions es el siguiente paso sugerido cuando una restricción descarta nodos.
+var constraintSuggestions = map[string]string{
+	constraintUnschedulable:   "Los nodos están cordoned o drenándose; espera a que termine la rotación, ejecuta 'kubectl uncordon' o añade capacidad.",
+	constraintNotReady:        "Revisa los nodos NotReady con 'eks-review diagnose node <nodo>'.",
+	constraintNodeSelector:    "Comprueba que algún nodegroup tiene las etiquetas del nodeSelector o corrige el nodeSelector del pod.",
+	constraintNodeAffinity:    "Revisa la nodeAffinity requerida (requiredDuringScheduling…) frente a las etiquetas de los nodos (zona, tipo de instancia, nodegroup).",
+	constraintTaint:           "Añade la toleration correspondiente al pod o prográmalo en un nodegroup sin ese taint.",
+	constraintTooManyPods:     "Los nodos han alcanzado su máximo de pods; en EKS depende de las ENIs/IPs del tipo de instancia (activa prefix delegation o usa instancias mayores).",
+	constraintPodAffinity:     "Ningún dominio de topología tiene pods que cumplan la podAffinity requerida; revisa el labelSelector y el topologyKey.",
+	constraintPodAntiAffinity: "La podAntiAffinity requerida impide compartir dominio con pods existentes: hay más réplicas que dominios (nodos/zonas). Añade nodos o usa preferredDuringScheduling.",
+	constraintTopologySpread:  "Con whenUnsatisfiable=DoNotSchedule se superaría maxSkew; añade nodos en los dominios con menos pods o usa ScheduleAnyway.",
+	constraintVolumeZone:      "El volumen persistente está en una zona sin nodos disponibles (EBS es zonal); añade nodos en esa zona o usa volumeBindingMode WaitForFirstConsumer.",
+}
+
+// suggestionForConstraint devuelve la sugerencia de una restricción, incluidas las de recursos.
+func suggestionForConstraint(constraint string) string {
+	if s, ok := constraintSuggestions[constraint]; ok {
+		return s
+	}
+	if strings.HasPrefix(constraint, constraintInsufficient) {
+		return "Reduce los requests del pod o añade capacidad (escala el nodegroup o revisa Cluster Autoscaler/Karpenter)."
+	}
+	return ""
+}
+
+// schedulingReason explica por qué una restricción descarta un nodo.
+type schedulingReason struct {
+	Constraint string `json:"constraint"`
+	Detail     string `json:"detail"`
+}
+
+// nodeVerdict es el resultado de evaluar un pod contra un nodo.
+type nodeVerdict struct {
+	Node    string             `json:"node"`
+	Fits    bool               `json:"fits"`
+	Reasons []schedulingReason `json:"reasons"`
+}
+
+// constraintElimination resume qué nodos descarta cada restricción.
+type constraintElimination struct {
+	Constraint string   `json:"constraint"`
+	Nodes      []string `json:"nodes"`
+}
+
+// pendingPodDiagnosis es el análisis de scheduling de un pod Pending.
+type pendingPodDiagnosis struct {
+	Namespace        string                  `json:"namespace"`
+	Pod              string                  `json:"pod"`
+	PendingFor       string                  `json:"pendingFor"`
+	CPURequest       int64                   `json:"cpuRequestMillis"`
+	MemoryRequest    int64                   `json:"memoryRequestBytes"`
+	SchedulerMessage string                  `json:"schedulerMessage,omitempty"`
+	FittingNodes     int                     `json:"fittingNodes"`
+	TotalNodes       int                     `json:"totalNodes"`
+	Nodes            []nodeVerdict           `json:"nodes"`
+	Eliminations     []constraintElimination `json:"eliminations"`
+	Findings         []finding               `json:"findings"`
+}
+
+// nodeAllocation son los recursos ya reservados en un nodo por los pods programados.
+type nodeAllocation struct {
+	CPU      int64
+	Memory   int64
+	Pods     int64
+	Extended map[corev1.ResourceName]int64
+}
+
+// schedulingSnapshot es el estado del clúster contra el que se evalúan los pods Pending.
+type schedulingSnapshot struct {
+	Nodes          []corev1.Node
+	Pods           []corev1.Pod
+	PVCs           map[string]corev1.PersistentVolumeClaim
+	PVs            map[string]corev1.PersistentVolume
+	StorageClasses map[string]storagev1.StorageClass
+	Events         []clusterEvent
+
+	allocated map[string]*nodeAllocation
+	nodesByID map[string]corev1.Node
+}
+
+// newSchedulingSnapshot precalcula los recursos reservados en cada nodo. pvcs es nil
+// cuando no se pudieron listar; en ese caso no se reportan PVC inexistentes.
+func newSchedulingSnapshot(nodes []corev1.Node, pods []corev1.Pod, pvcs []corev1.PersistentVolumeClaim, pvs []corev1.PersistentVolume, classes []storagev1.StorageClass, events []clusterEvent) schedulingSnapshot {
+	s := schedulingSnapshot{
+		Nodes:          nodes,
+		Pods:           pods,
+		PVCs:           map[string]corev1.PersistentVolumeClaim{},
+		PVs:            map[string]corev1.PersistentVolume{},
+		StorageClasses: map[string]storagev1.StorageClass{},
+		Events:         events,
+		allocated:      map[string]*nodeAllocation{},
+		nodesByID:      map[string]corev1.Node{},
+	}
+	if pvcs == nil {
+		s.PVCs = nil
+	}
+	for _, pvc := range pvcs {
+		s.PVCs[pvc.Namespace+"/"+pvc.Name] = pvc
+	}
+	for _, pv := range pvs {
+		s.PVs[pv.Name] = pv
+	}
+	for _, sc := range classes {
+		s.StorageClasses[sc.Name] = sc
+	}
+	for _, n := range nodes {
+		s.nodesByID[n.Name] = n
+		s.allocated[n.Name] = &nodeAllocation{Extended: map[corev1.ResourceName]int64{}}
+	}
+	for _, p := range pods {
+		a, ok := s.allocated[p.Spec.NodeName]
+		if !ok || isTerminatedPod(p) {
+			continue
+		}
+		cpu, mem := podSchedulingRequests(p)
+		a.CPU += cpu
+		a.Memory += mem
+		a.Pods++
+		for name, v := range podExtendedRequests(p) {
+			a.Extended[name] += v
+		}
+	}
+	return s
+}
+
+func isTerminatedPod(p corev1.Pod) bool {
+	return p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed
+}
+
+// isUnscheduledPod indica si el pod está Pending y el scheduler aún no le ha asignado nodo.
+func isUnscheduledPod(p corev1.Pod) bool {
+	return p.Status.Phase == corev1.PodPending && p.Spec.NodeName == "" && p.DeletionTimestamp == nil
+}
+
+// podExtendedRequests suma los requests de recursos distintos de CPU y memoria.
+func podExtendedRequests(pod corev1.Pod) map[corev1.ResourceName]int64 {
+	result := map[corev1.ResourceName]int64{}
+	for _, c := range pod.Spec.Containers {
+		for name, q := range c.Resources.Requests {
+			if name == corev1.ResourceCPU || name == corev1.ResourceMemory || name == corev1.ResourceEphemeralStorage {
+				continue
+			}
+			result[name] += q.Value()
+		}
+	}
+	return result
+}
+
+var diagnosePendingCmd = &cobra.Command{
+	Use:   "pending [nombre-del-pod]",
+	Short: "Explica por qué los pods Pending no se pueden programar.",
+	Long: `El comando diagnose pending evalúa cada pod Pending sin nodo asignado contra todos
+los nodos del clúster, como lo haría el scheduler: nodos cordoned o NotReady,
+nodeSelector, nodeAffinity requerida, taints y tolerations, requests frente a la
+capacidad libre (CPU, memoria, número de pods y recursos extendidos), podAffinity y
+podAntiAffinity, topologySpreadConstraints con DoNotSchedule y la zona de los
+volúmenes persistentes ya enlazados.
+
+Para cada pod muestra el último evento FailedScheduling, qué restricción descarta
+qué nodos y las causas probables con el siguiente paso sugerido.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clients, err := GetKubeClients()
+		if err != nil {
+			return fmt.Errorf("creando clientes de Kubernetes: %w", err)
+		}
+		namespace := GetEffectiveNamespace(diagnoseNamespace, diagnosePendingAllNamespaces, "default", false)
+		outputLower := strings.ToLower(diagnoseOutputFormat)
+		info := os.Stdout
+		if isStructuredOutput(outputLower) {
+			info = os.Stderr
+		}
+
+		scope := fmt.Sprintf("namespace '%s'", namespace)
+		if namespace == "" {
+			scope = "todos los namespaces"
+		}
+		fmt.Fprintf(info, "Analizando pods Pending en %s...\n", scope)
+
+		snap, err := collectSchedulingSnapshot(context.TODO(), clients.Core, namespace)
+		if err != nil {
+			return err
+		}
+
+		var pending []corev1.Pod
+		for _, p := range snap.Pods {
+			if namespace != "" && p.Namespace != namespace {
+				continue
+			}
+			if len(args) == 1 {
+				if p.Name != args[0] {
+					continue
+				}
+				if !isUnscheduledPod(p) {
+					return fmt.Errorf("el pod '%s' no está Pending sin nodo (fase %s, nodo '%s'); usa 'eks-review diagnose pod %s'", p.Name, p.Status.Phase, p.Spec.NodeName, p.Name)
+				}
+			}
+			if isUnscheduledPod(p) {
+				pending = append(pending, p)
+			}
+		}
+		if len(args) == 1 && len(pending) == 0 {
+			return fmt.Errorf("no se encontró el pod '%s' en %s", args[0], scope)
+		}
+		sort.Slice(pending, func(i, j int) bool {
+			if pending[i].Namespace != pending[j].Namespace {
+				return pending[i].Namespace < pending[j].Namespace
+			}
+			return pending[i].Name < pending[j].Name
+		})
+
+		results := make([]pendingPodDiagnosis, 0, len(pending))
+		for _, p := range pending {
+			results = append(results, analyzePendingPod(p, snap, time.Now()))
+		}
+
+		if isStructuredOutput(outputLower) {
+			return writeStructuredOutput(os.Stdout, outputLower, newOutputList("PendingPodDiagnosisList", results))
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(os.Stdout, colorize(fmt.Sprintf("No hay pods Pending sin programar en %s.", scope), colorGreen))
+			return nil
+		}
+		for _, r := range results {
+			printPendingPodDiagnosis(r)
+		}
+		return nil
+	},
+}
+
+func init() {
+	diagnoseCmd.AddCommand(diagnosePendingCmd)
+	diagnosePendingCmd.Flags().BoolVarP(&diagnosePendingAllNamespaces, "all-namespaces", "A", false, "Analizar los pods Pending de todos los namespaces")
+}
+
+// collectSchedulingSnapshot recupera nodos y pods de todo el clúster (necesarios para la
+// capacidad libre y la afinidad entre pods) y los volúmenes y eventos del namespace.
+// Solo los nodos y los pods son imprescindibles; el resto se omite con un aviso.
+func collectSchedulingSnapshot(ctx context.Context, clientset kubernetes.Interface, namespace string) (schedulingSnapshot, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return schedulingSnapshot{}, fmt.Errorf("listando nodos: %w", err)
+	}
+	pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return schedulingSnapshot{}, fmt.Errorf("listando pods: %w", err)
+	}
+
+	warn := func(resource string, err error) {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar %s: %s\n", resource, describeCollectError(err))
+	}
+	var pvcs []corev1.PersistentVolumeClaim
+	if list, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("persistentvolumeclaims", err)
+	} else {
+		pvcs = append([]corev1.PersistentVolumeClaim{}, list.Items...)
+	}
+	var pvs []corev1.PersistentVolume
+	if list, err := clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{}); err != nil {
+		warn("persistentvolumes", err)
+	} else {
+		pvs = list.Items
+	}
+	var classes []storagev1.StorageClass
+	if list, err := clientset.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{}); err != nil {
+		warn("storageclasses", err)
+	} else {
+		classes = list.Items
+	}
+	events, err := listClusterEvents(clientset, namespace)
+	if err != nil {
+		warn("eventos", err)
+	}
+	return newSchedulingSnapshot(nodes.Items, pods.Items, pvcs, pvs, classes, events), nil
+}
+
+// analyzePendingPod evalúa el pod contra cada nodo y resume qué restricción descarta cada uno.
+func analyzePendingPod(pod corev1.Pod, snap schedulingSnapshot, now time.Time) pendingPodDiagnosis {
+	cpu, mem := podSchedulingRequests(pod)
+	d := pendingPodDiagnosis{
+		Namespace:     pod.Namespace,
+		Pod:           pod.Name,
+		PendingFor:    now.Sub(pod.CreationTimestamp.Time).Truncate(time.Second).String(),
+		CPURequest:    cpu,
+		MemoryRequest: mem,
+		TotalNodes:    len(snap.Nodes),
+		Nodes:         []nodeVerdict{},
+		Eliminations:  []constraintElimination{},
+	}
+	d.SchedulerMessage = lastFailedSchedulingMessage(pod, snap.Events)
+
+	volumeTerms, findings := podVolumeNodeAffinity(pod, snap)
+	spread := newTopologySpreadState(pod, snap)
+
+	byConstraint := map[string][]string{}
+	for _, node := range snap.Nodes {
+		reasons := evaluateNode(pod, node, snap, volumeTerms, spread)
+		v := nodeVerdict{Node: node.Name, Fits: len(reasons) == 0, Reasons: reasons}
+		if v.Reasons == nil {
+			v.Reasons = []schedulingReason{}
+		}
+		if v.Fits {
+			d.FittingNodes++
+		}
+		seen := map[string]bool{}
+		for _, r := range reasons {
+			if !seen[r.Constraint] {
+				seen[r.Constraint] = true
+				byConstraint[r.Constraint] = append(byConstraint[r.Constraint], node.Name)
+			}
+		}
+		d.Nodes = append(d.Nodes, v)
+	}
+	for c, nodes := range byConstraint {
+		d.Eliminations = append(d.Eliminations, constraintElimination{Constraint: c, Nodes: nodes})
+	}
+	sort.Slice(d.Eliminations, func(i, j int) bool {
+		if len(d.Eliminations[i].Nodes) != len(d.Eliminations[j].Nodes) {
+			return len(d.Eliminations[i].Nodes) > len(d.Eliminations[j].Nodes)
+		}
+		return d.Eliminations[i].Constraint < d.Eliminations[j].Constraint
+	})
+
+	d.Findings = append(findings, pendingFindings(pod, d)...)
+	sortFindings(d.Findings)
+	return d
+}
+
+// pendingFindings convierte el resultado por nodo en causas probables.
+func pendingFindings(pod corev1.Pod, d pendingPodDiagnosis) []finding {
+	var findings []finding
+	object := fmt.Sprintf("Pod %s/%s", pod.Namespace, pod.Name)
+	add := func(severity, check, message, suggestion string) {
+		findings = append(findings, finding{Severity: severity, Check: check, Object: object, Message: message, Suggestion: suggestion})
+	}
+
+	if len(pod.Spec.SchedulingGates) > 0 {
+		var gates []string
+		for _, g := range pod.Spec.SchedulingGates {
+			gates = append(gates, g.Name)
+		}
+		add(severityCritical, "pending.scheduling-gates", fmt.Sprintf("El pod tiene schedulingGates (%s): el scheduler no lo considerará hasta que se eliminen.", strings.Join(gates, ", ")),
+			"Revisa qué controlador añadió las gates y por qué no las ha retirado.")
+	}
+	if pod.Spec.SchedulerName != "" && pod.Spec.SchedulerName != corev1.DefaultSchedulerName {
+		add(severityWarning, "pending.scheduler-name", fmt.Sprintf("El pod usa el scheduler '%s'; este análisis reproduce las reglas del scheduler por defecto.", pod.Spec.SchedulerName),
+			"Comprueba que ese scheduler está desplegado y funcionando.")
+	}
+
+	switch {
+	case d.TotalNodes == 0:
+		add(severityCritical, "pending.no-nodes", "El clúster no tiene nodos registrados.",
+			"Revisa los nodegroups/Karpenter y que los nodos pueden unirse al clúster (aws-auth, rol IAM del nodo).")
+	case d.FittingNodes == 0:
+		blocking := false
+		for _, e := range d.Eliminations {
+			if len(e.Nodes) == d.TotalNodes {
+				blocking = true
+				add(severityCritical, "pending."+e.Constraint, fmt.Sprintf("La restricción %s descarta los %d nodos.", e.Constraint, d.TotalNodes),
+					suggestionForConstraint(e.Constraint))
+			}
+		}
+		if !blocking {
+			var parts []string
+			for _, e := range d.Eliminations {
+				parts = append(parts, fmt.Sprintf("%s (%d)", e.Constraint, len(e.Nodes)))
+			}
+			suggestion := ""
+			if len(d.Eliminations) > 0 {
+				suggestion = suggestionForConstraint(d.Eliminations[0].Constraint)
+			}
+			add(severityCritical, "pending.combined", fmt.Sprintf("Ningún nodo cumple todas las restricciones a la vez; nodos descartados por: %s.", strings.Join(parts, ", ")),
+				suggestion)
+		}
+	default:
+		add(severityWarning, "pending.fits-somewhere", fmt.Sprintf("%d de %d nodos cumplen las restricciones evaluadas, pero el pod sigue sin programar.", d.FittingNodes, d.TotalNodes),
+			"Revisa el último evento FailedScheduling: pueden intervenir ResourceQuotas, preemption, volúmenes sin provisionar o un scheduler sobrecargado.")
+	}
+	return findings
+}
+
+// lastFailedSchedulingMessage devuelve el mensaje del evento FailedScheduling más reciente del pod.
+func lastFailedSchedulingMessage(pod corev1.Pod, events []clusterEvent) string {
+	var latest clusterEvent
+	for _, e := range events {
+		if e.Kind == "Pod" && e.Namespace == pod.Namespace && e.Name == pod.Name && e.Reason == "FailedScheduling" && !e.LastSeen.Before(latest.LastSeen) {
+			latest = e
+		}
+	}
+	return latest.Message
+}
+
+// evaluateNode devuelve los motivos por los que el nodo no puede alojar el pod.
+func evaluateNode(pod corev1.Pod, node corev1.Node, snap schedulingSnapshot, volumeTerms []corev1.NodeSelectorTerm, spread []topologySpreadState) []schedulingReason {
+	var reasons []schedulingReason
+	add := func(constraint, format string, a ...interface{}) {
+		reasons = append(reasons, schedulingReason{Constraint: constraint, Detail: fmt.Sprintf(format, a...)})
+	}
+
+	if node.Spec.Unschedulable && !toleratesTaint(pod, corev1.Taint{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule}) {
+		add(constraintUnschedulable, "nodo cordoned (SchedulingDisabled)")
+	}
+	if getNodeStatus(node) != "Ready" {
+		add(constraintNotReady, "nodo %s", getNodeStatus(node))
+	}
+	for k, v := range pod.Spec.NodeSelector {
+		if actual, ok := node.Labels[k]; !ok || actual != v {
+			add(constraintNodeSelector, "falta la etiqueta %s=%s", k, v)
+		}
+	}
+	if a := pod.Spec.Affinity; a != nil && a.NodeAffinity != nil && a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		if !nodeMatchesSelectorTerms(node, a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) {
+			add(constraintNodeAffinity, "no cumple ningún término de la nodeAffinity requerida")
+		}
+	}
+	for _, t := range node.Spec.Taints {
+		if t.Effect == corev1.TaintEffectPreferNoSchedule || t.Key == corev1.TaintNodeUnschedulable {
+			continue
+		}
+		if !toleratesTaint(pod, t) {
+			add(constraintTaint, "taint %s sin toleration", formatTaints([]corev1.Taint{t})[0])
+		}
+	}
+
+	alloc := snap.allocated[node.Name]
+	if alloc == nil {
+		alloc = &nodeAllocation{Extended: map[corev1.ResourceName]int64{}}
+	}
+	cpu, mem := podSchedulingRequests(pod)
+	cpuAlloc := node.Status.Allocatable[corev1.ResourceCPU]
+	memAlloc := node.Status.Allocatable[corev1.ResourceMemory]
+	podsAlloc := node.Status.Allocatable[corev1.ResourcePods]
+	if free := cpuAlloc.MilliValue() - alloc.CPU; cpu > 0 && cpu > free {
+		add(constraintInsufficient+"cpu", "pide %s, libres %s de %s", formatMilliCPU(cpu), formatMilliCPU(max64(free, 0)), formatMilliCPU(cpuAlloc.MilliValue()))
+	}
+	if free := memAlloc.Value() - alloc.Memory; mem > 0 && mem > free {
+		add(constraintInsufficient+"memory", "pide %s, libres %s de %s", formatBytes(mem), formatBytes(max64(free, 0)), formatBytes(memAlloc.Value()))
+	}
+	if podsAlloc.Value() > 0 && alloc.Pods >= podsAlloc.Value() {
+		add(constraintTooManyPods, "%d/%d pods", alloc.Pods, podsAlloc.Value())
+	}
+	for name, req := range podExtendedRequests(pod) {
+		q := node.Status.Allocatable[name]
+		if free := q.Value() - alloc.Extended[name]; req > free {
+			add(constraintInsufficient+string(name), "pide %d, libres %d", req, max64(free, 0))
+		}
+	}
+
+	if a := pod.Spec.Affinity; a != nil && a.PodAffinity != nil {
+		for _, term := range a.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			if !podAffinityTermSatisfied(pod, term, node, snap) {
+				add(constraintPodAffinity, "no hay pods que cumplan la afinidad en el mismo %s", term.TopologyKey)
+			}
+		}
+	}
+	if a := pod.Spec.Affinity; a != nil && a.PodAntiAffinity != nil {
+		for _, term := range a.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			if owner := podAntiAffinityConflict(pod, term, node, snap); owner != "" {
+				add(constraintPodAntiAffinity, "el pod %s ya ocupa el mismo %s", owner, term.TopologyKey)
+			}
+		}
+	}
+	for _, s := range spread {
+		if detail := s.violation(node); detail != "" {
+			add(constraintTopologySpread, "%s", detail)
+		}
+	}
+	if len(volumeTerms) > 0 && !nodeMatchesAllSelectorTerms(node, volumeTerms) {
+		add(constraintVolumeZone, "el volumen persistente no es accesible desde este nodo (zona %s)", valueOrNone(node.Labels[corev1.LabelTopologyZone]))
+	}
+	return reasons
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func toleratesTaint(pod corev1.Pod, taint corev1.Taint) bool {
+	for _, tol := range pod.Spec.Tolerations {
+		if tol.ToleratesTaint(&taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeMatchesSelectorTerms indica si el nodo cumple alguno de los términos (se combinan con OR).
+func nodeMatchesSelectorTerms(node corev1.Node, terms []corev1.NodeSelectorTerm) bool {
+	for _, term := range terms {
+		if nodeMatchesSelectorTerm(node, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeMatchesAllSelectorTerms exige cada conjunto de términos por separado; se usa para
+// combinar la nodeAffinity de varios volúmenes.
+func nodeMatchesAllSelectorTerms(node corev1.Node, terms []corev1.NodeSelectorTerm) bool {
+	for _, term := range terms {
+		if !nodeMatchesSelectorTerm(node, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeMatchesSelectorTerm evalúa un término: todas sus expresiones deben cumplirse.
+// Un término vacío no selecciona ningún nodo, como en el scheduler.
+func nodeMatchesSelectorTerm(node corev1.Node, term corev1.NodeSelectorTerm) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, req := range term.MatchExpressions {
+		value, ok := node.Labels[req.Key]
+		if !nodeRequirementMatches(req, value, ok) {
+			return false
+		}
+	}
+	for _, req := range term.MatchFields {
+		if req.Key != "metadata.name" || !nodeRequirementMatches(req, node.Name, true) {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeRequirementMatches(req corev1.NodeSelectorRequirement, value string, present bool) bool {
+	contains := func() bool {
+		for _, v := range req.Values {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	}
+	switch req.Operator {
+	case corev1.NodeSelectorOpIn:
+		return present && contains()
+	case corev1.NodeSelectorOpNotIn:
+		return !present || !contains()
+	case corev1.NodeSelectorOpExists:
+		return present
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !present
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !present || len(req.Values) != 1 {
+			return false
+		}
+		actual, err1 := strconv.ParseInt(value, 10, 64)
+		limit, err2 := strconv.ParseInt(req.Values[0], 10, 64)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if req.Operator == corev1.NodeSelectorOpGt {
+			return actual > limit
+		}
+		return actual < limit
+	}
+	return false
+}
+
+// podVolumeNodeAffinity devuelve la nodeAffinity de los PV ya enlazados a los PVC del pod
+// y los hallazgos de PVC inexistentes o sin enlazar.
+func podVolumeNodeAffinity(pod corev1.Pod, snap schedulingSnapshot) ([]corev1.NodeSelectorTerm, []finding) {
+	var terms []corev1.NodeSelectorTerm
+	var findings []finding
+	object := fmt.Sprintf("Pod %s/%s", pod.Namespace, pod.Name)
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim == nil {
+			continue
+		}
+		claim := vol.PersistentVolumeClaim.ClaimName
+		pvc, ok := snap.PVCs[pod.Namespace+"/"+claim]
+		if !ok {
+			if snap.PVCs != nil {
+				findings = append(findings, finding{Severity: severityCritical, Check: "pending.pvc-missing", Object: object,
+					Message:    fmt.Sprintf("El PVC '%s' referenciado por el pod no existe.", claim),
+					Suggestion: "Crea el PVC o corrige claimName; en StatefulSets revisa volumeClaimTemplates."})
+			}
+			continue
+		}
+		if pvc.Status.Phase != corev1.ClaimBound || pvc.Spec.VolumeName == "" {
+			if !isWaitForFirstConsumer(pvc, snap) {
+				findings = append(findings, finding{Severity: severityCritical, Check: "pending.pvc-unbound", Object: fmt.Sprintf("PersistentVolumeClaim %s/%s", pvc.Namespace, pvc.Name),
+					Message:    fmt.Sprintf("El PVC '%s' está %s y su StorageClass no espera al primer consumidor.", claim, valueOrDefault(string(pvc.Status.Phase), "Pending")),
+					Suggestion: "Revisa los eventos del PVC y el provisioner de la StorageClass (ej. el controlador ebs.csi.aws.com y su rol IAM)."})
+			}
+			continue
+		}
+		pv, ok := snap.PVs[pvc.Spec.VolumeName]
+		if !ok || pv.Spec.NodeAffinity == nil || pv.Spec.NodeAffinity.Required == nil {
+			continue
+		}
+		// Los términos de un mismo PV se combinan con OR; se resumen en uno solo
+		// cuando hay uno, que es el caso habitual de EBS (zona).
+		if len(pv.Spec.NodeAffinity.Required.NodeSelectorTerms) == 1 {
+			terms = append(terms, pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0])
+		}
+	}
+	return terms, findings
+}
+
+func isWaitForFirstConsumer(pvc corev1.PersistentVolumeClaim, snap schedulingSnapshot) bool {
+	if pvc.Spec.StorageClassName == nil {
+		return false
+	}
+	sc, ok := snap.StorageClasses[*pvc.Spec.StorageClassName]
+	return ok && sc.VolumeBindingMode != nil && *sc.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer
+}
+
+// affinityTermPods devuelve los pods programados que cumplen el labelSelector del término
+// en los namespaces a los que aplica. Un namespaceSelector no vacío se trata como "todos
+// los namespaces", ya que no se listan las etiquetas de los namespaces.
+func affinityTermPods(pod corev1.Pod, term corev1.PodAffinityTerm, snap schedulingSnapshot) []corev1.Pod {
+	selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+	if err != nil || term.LabelSelector == nil {
+		return nil
+	}
+	namespaces := map[string]bool{}
+	for _, ns := range term.Namespaces {
+		namespaces[ns] = true
+	}
+	allNamespaces := term.NamespaceSelector != nil
+	if len(namespaces) == 0 && !allNamespaces {
+		namespaces[pod.Namespace] = true
+	}
+	var result []corev1.Pod
+	for _, p := range snap.Pods {
+		if p.Spec.NodeName == "" || isTerminatedPod(p) || (p.Namespace == pod.Namespace && p.Name == pod.Name) {
+			continue
+		}
+		if !allNamespaces && !namespaces[p.Namespace] {
+			continue
+		}
+		if selector.Matches(labels.Set(p.Labels)) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// sameTopology indica si el nodo del pod existente comparte el valor de topologyKey con el candidato.
+func sameTopology(existing corev1.Pod, candidate corev1.Node, topologyKey string, snap schedulingSnapshot) bool {
+	value, ok := candidate.Labels[topologyKey]
+	if !ok {
+		return false
+	}
+	node, found := snap.nodesByID[existing.Spec.NodeName]
+	return found && node.Labels[topologyKey] == value
+}
+
+func podAffinityTermSatisfied(pod corev1.Pod, term corev1.PodAffinityTerm, node corev1.Node, snap schedulingSnapshot) bool {
+	matches := affinityTermPods(pod, term, snap)
+	if len(matches) == 0 {
+		// El scheduler permite el primer pod de un grupo que tiene afinidad consigo mismo.
+		selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+		return err == nil && selector.Matches(labels.Set(pod.Labels))
+	}
+	for _, p := range matches {
+		if sameTopology(p, node, term.TopologyKey, snap) {
+			return true
+		}
+	}
+	return false
+}
+
+// podAntiAffinityConflict devuelve el pod existente que impide usar el nodo, o "".
+func podAntiAffinityConflict(pod corev1.Pod, term corev1.PodAffinityTerm, node corev1.Node, snap schedulingSnapshot) string {
+	for _, p := range affinityTermPods(pod, term, snap) {
+		if sameTopology(p, node, term.TopologyKey, snap) {
+			return p.Namespace + "/" + p.Name
+		}
+	}
+	return ""
+}
+
+// topologySpreadState precalcula los pods por dominio de una topologySpreadConstraint
+// con whenUnsatisfiable=DoNotSchedule.
+type topologySpreadState struct {
+	constraint corev1.TopologySpreadConstraint
+	counts     map[string]int
+	selfMatch  int
+}
+
+func newTopologySpreadState(pod corev1.Pod, snap schedulingSnapshot) []topologySpreadState {
+	var states []topologySpreadState
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		if c.WhenUnsatisfiable != corev1.DoNotSchedule {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(c.LabelSelector)
+		if err != nil {
+			continue
+		}
+		s := topologySpreadState{constraint: c, counts: map[string]int{}}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			s.selfMatch = 1
+		}
+		// Solo cuentan los dominios de nodos que cumplen el nodeSelector y la nodeAffinity
+		// del pod (nodeAffinityPolicy=Honor, el valor por defecto).
+		for _, n := range snap.Nodes {
+			value, ok := n.Labels[c.TopologyKey]
+			if !ok || !nodePassesNodeAffinity(pod, n) {
+				continue
+			}
+			if _, exists := s.counts[value]; !exists {
+				s.counts[value] = 0
+			}
+		}
+		for _, p := range snap.Pods {
+			if p.Namespace != pod.Namespace || p.Spec.NodeName == "" || isTerminatedPod(p) || !selector.Matches(labels.Set(p.Labels)) {
+				continue
+			}
+			node, ok := snap.nodesByID[p.Spec.NodeName]
+			if !ok {
+				continue
+			}
+			if value, ok := node.Labels[c.TopologyKey]; ok {
+				if _, eligible := s.counts[value]; eligible {
+					s.counts[value]++
+				}
+			}
+		}
+		states = append(states, s)
+	}
+	return states
+}
+
+// violation devuelve el motivo por el que el nodo superaría maxSkew, o "".
+func (s topologySpreadState) violation(node corev1.Node) string {
+	value, ok := node.Labels[s.constraint.TopologyKey]
+	if !ok {
+		return fmt.Sprintf("el nodo no tiene la etiqueta %s", s.constraint.TopologyKey)
+	}
+	minCount := -1
+	for _, c := range s.counts {
+		if minCount < 0 || c < minCount {
+			minCount = c
+		}
+	}
+	if minCount < 0 {
+		minCount = 0
+	}
+	skew := s.counts[value] + s.selfMatch - minCount
+	if skew > int(s.constraint.MaxSkew) {
+		return fmt.Sprintf("%s=%s tendría skew %d > maxSkew %d", s.constraint.TopologyKey, value, skew, s.constraint.MaxSkew)
+	}
+	return ""
+}
+
+func nodePassesNodeAffinity(pod corev1.Pod, node corev1.Node) bool {
+	for k, v := range pod.Spec.NodeSelector {
+		if node.Labels[k] != v {
+			return false
+		}
+	}
+	if a := pod.Spec.Affinity; a != nil && a.NodeAffinity != nil && a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		return nodeMatchesSelectorTerms(node, a.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+	}
+	return true
+}
+
+// printPendingPodDiagnosis imprime el análisis de un pod Pending.
+func printPendingPodDiagnosis(d pendingPodDiagnosis) {
+	fmt.Fprintf(os.Stdout, "\n=== Pod %s/%s (Pending desde hace %s) ===\n", d.Namespace, d.Pod, d.PendingFor)
+	fmt.Fprintf(os.Stdout, "Requests: CPU %s, memoria %s\n", formatMilliCPU(d.CPURequest), formatBytes(d.MemoryRequest))
+	if d.SchedulerMessage != "" {
+		fmt.Fprintf(os.Stdout, "Último FailedScheduling: %s\n", d.SchedulerMessage)
+	}
+	fmt.Fprintf(os.Stdout, "Nodos que cumplen las restricciones: %d/%d\n", d.FittingNodes, d.TotalNodes)
+
+	if len(d.Eliminations) > 0 {
+		fmt.Fprintln(os.Stdout, "\n--- Nodos descartados por restricción ---")
+		rows := make([][]string, 0, len(d.Eliminations))
+		for _, e := range d.Eliminations {
+			rows = append(rows, []string{e.Constraint, fmt.Sprintf("%d/%d", len(e.Nodes), d.TotalNodes), truncateString(strings.Join(e.Nodes, ","), 80)})
+		}
+		PrintBasicTable([]string{"RESTRICCIÓN", "NODOS", "NOMBRES"}, rows)
+	}
+
+	if len(d.Nodes) > 0 && len(d.Nodes) <= maxPendingNodeRows {
+		fmt.Fprintln(os.Stdout, "--- Evaluación por nodo ---")
+		rows := make([][]string, 0, len(d.Nodes))
+		for _, v := range d.Nodes {
+			fits := "sí"
+			var details []string
+			for _, r := range v.Reasons {
+				details = append(details, fmt.Sprintf("%s: %s", r.Constraint, r.Detail))
+			}
+			if !v.Fits {
+				fits = "no"
+			}
+			rows = append(rows, []string{v.Node, fits, valueOrNone(truncateString(strings.Join(details, "; "), 120))})
+		}
+		PrintBasicTable([]string{"NODO", "CABE", "MOTIVOS"}, rows)
+	} else if len(d.Nodes) > maxPendingNodeRows {
+		fmt.Fprintf(os.Stdout, "(%d nodos; usa -o json para ver la evaluación de cada uno)\n", len(d.Nodes))
+	}
+
+	printFindings("Causas probables", d.Findings)
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testSchedulingNode(name, zone, cpu string, taints ...corev1.Taint) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{
+			corev1.LabelTopologyZone: zone,
+			corev1.LabelHostname:     name,
+			"role":                   "app",
+		}},
+		Spec: corev1.NodeSpec{Taints: taints},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+		},
+	}
+}
+
+func testPendingPod(name, cpu string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: name, Labels: map[string]string{"app": "api"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{testContainer("app", cpu, "", "", "")}},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+}
+
+func testRunningPod(name, node, cpu string) corev1.Pod {
+	p := testPendingPod(name, cpu)
+	p.Spec.NodeName = node
+	p.Status.Phase = corev1.PodRunning
+	return p
+}
+
+func eliminatedBy(d pendingPodDiagnosis, constraint string) []string {
+	for _, e := range d.Eliminations {
+		if e.Constraint == constraint {
+			return e.Nodes
+		}
+	}
+	return nil
+}
+
+func TestAnalyzePendingPodConstraints(t *testing.T) {
+	gpuTaint := corev1.Taint{Key: "nvidia.com/gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+	nodes := []corev1.Node{
+		testSchedulingNode("a", "us-east-1a", "2"),
+		testSchedulingNode("b", "us-east-1b", "2", gpuTaint),
+		testSchedulingNode("c", "us-east-1c", "4"),
+	}
+	delete(nodes[2].Labels, "role")
+	pods := []corev1.Pod{testRunningPod("busy", "a", "1800m")}
+	snap := newSchedulingSnapshot(nodes, pods, []corev1.PersistentVolumeClaim{}, nil, nil, []clusterEvent{
+		{Namespace: "prod", Kind: "Pod", Name: "api-1", Reason: "FailedScheduling", Message: "0/3 nodes are available", LastSeen: time.Now()},
+	})
+
+	pod := testPendingPod("api-1", "500m")
+	pod.Spec.NodeSelector = map[string]string{"role": "app"}
+	d := analyzePendingPod(pod, snap, time.Now())
+
+	if d.FittingNodes != 0 || d.TotalNodes != 3 {
+		t.Fatalf("got %d/%d fitting nodes, want 0/3", d.FittingNodes, d.TotalNodes)
+	}
+	if got := eliminatedBy(d, "insufficient-cpu"); len(got) != 1 || got[0] != "a" {
+		t.Errorf("insufficient-cpu: got %v, want [a]", got)
+	}
+	if got := eliminatedBy(d, constraintTaint); len(got) != 1 || got[0] != "b" {
+		t.Errorf("taint: got %v, want [b]", got)
+	}
+	if got := eliminatedBy(d, constraintNodeSelector); len(got) != 1 || got[0] != "c" {
+		t.Errorf("node-selector: got %v, want [c]", got)
+	}
+	if d.SchedulerMessage != "0/3 nodes are available" {
+		t.Errorf("scheduler message: got %q", d.SchedulerMessage)
+	}
+	if len(d.Findings) != 1 || d.Findings[0].Check != "pending.combined" || d.Findings[0].Severity != severityCritical {
+		t.Errorf("expected a single critical pending.combined finding, got %+v", d.Findings)
+	}
+
+	pod.Spec.Tolerations = []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}}
+	d = analyzePendingPod(pod, snap, time.Now())
+	if d.FittingNodes != 1 || !d.Nodes[1].Fits {
+		t.Errorf("with toleration node b should fit, got %+v", d.Nodes)
+	}
+	if len(d.Findings) != 1 || d.Findings[0].Check != "pending.fits-somewhere" {
+		t.Errorf("expected pending.fits-somewhere, got %+v", d.Findings)
+	}
+}
+
+func TestAnalyzePendingPodBlockingConstraint(t *testing.T) {
+	nodes := []corev1.Node{testSchedulingNode("a", "us-east-1a", "2"), testSchedulingNode("b", "us-east-1b", "2")}
+	snap := newSchedulingSnapshot(nodes, nil, nil, nil, nil, nil)
+	pod := testPendingPod("api-1", "100m")
+	pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"us-east-1c"}}},
+		}}},
+	}}
+	d := analyzePendingPod(pod, snap, time.Now())
+	if len(d.Findings) != 1 || d.Findings[0].Check != "pending.node-affinity" {
+		t.Errorf("expected pending.node-affinity, got %+v", d.Findings)
+	}
+}
+
+func TestAnalyzePendingPodInterPodConstraints(t *testing.T) {
+	nodes := []corev1.Node{testSchedulingNode("a", "us-east-1a", "4"), testSchedulingNode("b", "us-east-1b", "4")}
+	pods := []corev1.Pod{testRunningPod("api-0", "a", "100m"), testRunningPod("api-2", "a", "100m")}
+	snap := newSchedulingSnapshot(nodes, pods, nil, nil, nil, nil)
+
+	pod := testPendingPod("api-1", "100m")
+	pod.Spec.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			TopologyKey:   corev1.LabelHostname,
+		}},
+	}}
+	d := analyzePendingPod(pod, snap, time.Now())
+	if got := eliminatedBy(d, constraintPodAntiAffinity); len(got) != 1 || got[0] != "a" {
+		t.Errorf("pod-anti-affinity: got %v, want [a]", got)
+	}
+
+	pod.Spec.Affinity = nil
+	pod.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+		MaxSkew: 1, TopologyKey: corev1.LabelTopologyZone, WhenUnsatisfiable: corev1.DoNotSchedule,
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+	}}
+	d = analyzePendingPod(pod, snap, time.Now())
+	if got := eliminatedBy(d, constraintTopologySpread); len(got) != 1 || got[0] != "a" {
+		t.Errorf("topology-spread: got %v, want [a]", got)
+	}
+}
+
+func TestAnalyzePendingPodVolumes(t *testing.T) {
+	nodes := []corev1.Node{testSchedulingNode("a", "us-east-1a", "4"), testSchedulingNode("b", "us-east-1b", "4")}
+	immediate := storagev1.VolumeBindingImmediate
+	class := "gp2"
+	pvcs := []corev1.PersistentVolumeClaim{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "data"}, Spec: corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"}, Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "unbound"}, Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &class}, Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}},
+	}
+	pvs := []corev1.PersistentVolume{{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: corev1.PersistentVolumeSpec{NodeAffinity: &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpIn, Values: []string{"us-east-1b"}}},
+		}}}}},
+	}}
+	classes := []storagev1.StorageClass{{ObjectMeta: metav1.ObjectMeta{Name: class}, VolumeBindingMode: &immediate}}
+	snap := newSchedulingSnapshot(nodes, nil, pvcs, pvs, classes, nil)
+
+	pod := testPendingPod("db-0", "100m")
+	for _, claim := range []string{"data", "unbound", "missing"} {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{Name: claim, VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+		}})
+	}
+	d := analyzePendingPod(pod, snap, time.Now())
+	if got := eliminatedBy(d, constraintVolumeZone); len(got) != 1 || got[0] != "a" {
+		t.Errorf("volume-zone: got %v, want [a]", got)
+	}
+	var checks []string
+	for _, f := range d.Findings {
+		checks = append(checks, f.Check)
+	}
+	if got := strings.Join(checks, ","); !strings.Contains(got, "pending.pvc-unbound") || !strings.Contains(got, "pending.pvc-missing") {
+		t.Errorf("expected pvc-unbound and pvc-missing findings, got %s", got)
+	}
+}
+
+func TestNodeRequirementMatches(t *testing.T) {
+	tests := []struct {
+		op      corev1.NodeSelectorOperator
+		values  []string
+		value   string
+		present bool
+		want    bool
+	}{
+		{corev1.NodeSelectorOpIn, []string{"a", "b"}, "b", true, true},
+		{corev1.NodeSelectorOpIn, []string{"a"}, "", false, false},
+		{corev1.NodeSelectorOpNotIn, []string{"a"}, "", false, true},
+		{corev1.NodeSelectorOpExists, nil, "x", true, true},
+		{corev1.NodeSelectorOpDoesNotExist, nil, "x", true, false},
+		{corev1.NodeSelectorOpGt, []string{"4"}, "8", true, true},
+		{corev1.NodeSelectorOpLt, []string{"4"}, "8", true, false},
+	}
+	for _, tt := range tests {
+		req := corev1.NodeSelectorRequirement{Key: "k", Operator: tt.op, Values: tt.values}
+		if got := nodeRequirementMatches(req, tt.value, tt.present); got != tt.want {
+			t.Errorf("%s %v on %q (present=%t): got %t, want %t", tt.op, tt.values, tt.value, tt.present, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,570 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var diagnosePodTail int64
+
+// highRestartCount es el número de reinicios a partir del cual se avisa aunque el
+// contenedor esté funcionando en este momento.
+const highRestartCount = 5
+
+// terminationInfo describe la última terminación de un contenedor.
+type terminationInfo struct {
+	Reason     string    `json:"reason"`
+	ExitCode   int32     `json:"exitCode"`
+	Signal     int32     `json:"signal,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	FinishedAt time.Time `json:"finishedAt,omitempty"`
+}
+
+// containerDiagnosis resume el estado de un contenedor del pod.
+type containerDiagnosis struct {
+	Name            string           `json:"name"`
+	Init            bool             `json:"init,omitempty"`
+	Ready           bool             `json:"ready"`
+	State           string           `json:"state"`
+	Restarts        int32            `json:"restarts"`
+	LastTermination *terminationInfo `json:"lastTermination,omitempty"`
+	Liveness        string           `json:"livenessProbe,omitempty"`
+	Readiness       string           `json:"readinessProbe,omitempty"`
+	Startup         string           `json:"startupProbe,omitempty"`
+}
+
+// containerLogTail son las últimas líneas de log de un contenedor.
+type containerLogTail struct {
+	Container string   `json:"container"`
+	Previous  bool     `json:"previous"`
+	Lines     []string `json:"lines"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// podDiagnosis es el informe completo de diagnose pod.
+type podDiagnosis struct {
+	Namespace      string                 `json:"namespace"`
+	Pod            string                 `json:"pod"`
+	Status         string                 `json:"status"`
+	Node           string                 `json:"node,omitempty"`
+	QOSClass       string                 `json:"qosClass,omitempty"`
+	Containers     []containerDiagnosis   `json:"containers"`
+	Events         []clusterEvent         `json:"events"`
+	NodeConditions []corev1.NodeCondition `json:"nodeConditions,omitempty"`
+	Logs           []containerLogTail     `json:"logs"`
+	Findings       []finding              `json:"findings"`
+}
+
+var diagnosePodCmd = &cobra.Command{
+	Use:   "pod <nombre-del-pod>",
+	Short: "Analiza un pod que falla y propone las causas más probables.",
+	Long: `El comando diagnose pod recopila en una sola ejecución todo lo necesario para
+entender por qué falla un pod: estado de cada contenedor, última terminación con
+su motivo y código de salida (OOMKilled, Error, señal recibida), historial de
+reinicios, configuración de las probes, eventos relacionados, condiciones del
+nodo y las últimas líneas del log actual y anterior de cada contenedor.
+
+Al final muestra una lista ordenada de causas probables con el siguiente paso
+sugerido para cada una.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clients, err := GetKubeClients()
+		if err != nil {
+			return fmt.Errorf("creando clientes de Kubernetes: %w", err)
+		}
+		namespace := GetEffectiveNamespace(diagnoseNamespace, false, "default", false)
+		outputLower := strings.ToLower(diagnoseOutputFormat)
+		info := os.Stdout
+		if isStructuredOutput(outputLower) {
+			info = os.Stderr
+		}
+
+		fmt.Fprintf(info, "Diagnosticando pod '%s' en namespace '%s'...\n", args[0], namespace)
+		diag, err := collectPodDiagnosis(context.TODO(), clients.Core, namespace, args[0], diagnosePodTail)
+		if err != nil {
+			return err
+		}
+
+		if isStructuredOutput(outputLower) {
+			doc := struct {
+				outputHeader
+				podDiagnosis
+			}{newOutputHeader("PodDiagnosis"), diag}
+			return writeStructuredOutput(os.Stdout, outputLower, doc)
+		}
+		printPodDiagnosis(diag)
+		return nil
+	},
+}
+
+func init() {
+	diagnoseCmd.AddCommand(diagnosePodCmd)
+	diagnosePodCmd.Flags().Int64Var(&diagnosePodTail, "tail", 20, "Número de líneas de log a mostrar por contenedor")
+}
+
+// collectPodDiagnosis recupera el pod, sus eventos, su nodo y sus logs y los analiza.
+// Solo falla si no se puede obtener el pod; el resto de la información es opcional.
+func collectPodDiagnosis(ctx context.Context, clientset kubernetes.Interface, namespace, name string, tail int64) (podDiagnosis, error) {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return podDiagnosis{}, fmt.Errorf("obteniendo pod '%s' en namespace '%s': %w", name, namespace, err)
+	}
+
+	events, err := listClusterEvents(clientset, namespace)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar eventos: %v\n", err)
+	}
+	events = dedupeEvents(filterEvents(events, eventFilter{ForKind: "Pod", ForName: pod.Name}, time.Now()))
+	sortEventsByRecent(events)
+
+	var node *corev1.Node
+	if pod.Spec.NodeName != "" {
+		node, err = clientset.CoreV1().Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Advertencia: no se pudo obtener el nodo '%s': %v\n", pod.Spec.NodeName, err)
+			node = nil
+		}
+	}
+
+	logs := collectPodLogTails(ctx, clientset, *pod, tail)
+	diag := buildPodDiagnosis(*pod, events, node, logs)
+	return diag, nil
+}
+
+// collectPodLogTails obtiene las últimas líneas del log actual de cada contenedor que
+// ha arrancado y, si se ha reiniciado, también las del log anterior.
+func collectPodLogTails(ctx context.Context, clientset kubernetes.Interface, pod corev1.Pod, tail int64) []containerLogTail {
+	var tails []containerLogTail
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		started := cs.State.Running != nil || cs.State.Terminated != nil
+		if started {
+			tails = append(tails, fetchLogTail(ctx, clientset, pod, cs.Name, false, tail))
+		}
+		if cs.RestartCount > 0 || cs.LastTerminationState.Terminated != nil {
+			tails = append(tails, fetchLogTail(ctx, clientset, pod, cs.Name, true, tail))
+		}
+	}
+	return tails
+}
+
+func fetchLogTail(ctx context.Context, clientset kubernetes.Interface, pod corev1.Pod, container string, previous bool, tail int64) containerLogTail {
+	t := containerLogTail{Container: container, Previous: previous, Lines: []string{}}
+	opts := &corev1.PodLogOptions{Container: container, Previous: previous, TailLines: &tail}
+	raw, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).DoRaw(ctx)
+	if err != nil {
+		t.Error = err.Error()
+		return t
+	}
+	scanner := NewLineScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if scanner.Truncated() {
+			line += " [truncado]"
+		}
+		t.Lines = append(t.Lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Error = err.Error()
+	}
+	return t
+}
+
+// buildPodDiagnosis construye el informe a partir de los datos ya recuperados.
+func buildPodDiagnosis(pod corev1.Pod, events []clusterEvent, node *corev1.Node, logs []containerLogTail) podDiagnosis {
+	d := podDiagnosis{
+		Namespace: pod.Namespace,
+		Pod:       pod.Name,
+		Status:    podDisplayStatus(pod),
+		Node:      pod.Spec.NodeName,
+		QOSClass:  string(pod.Status.QOSClass),
+		Events:    events,
+		Logs:      logs,
+	}
+	if d.Events == nil {
+		d.Events = []clusterEvent{}
+	}
+	if d.Logs == nil {
+		d.Logs = []containerLogTail{}
+	}
+	if node != nil {
+		d.NodeConditions = node.Status.Conditions
+	}
+
+	specs := map[string]corev1.Container{}
+	for _, c := range pod.Spec.InitContainers {
+		specs[c.Name] = c
+	}
+	for _, c := range pod.Spec.Containers {
+		specs[c.Name] = c
+	}
+	addContainers := func(statuses []corev1.ContainerStatus, init bool) {
+		for _, cs := range statuses {
+			cd := containerDiagnosis{
+				Name:     cs.Name,
+				Init:     init,
+				Ready:    cs.Ready,
+				State:    containerStateString(cs.State),
+				Restarts: cs.RestartCount,
+			}
+			if t := cs.LastTerminationState.Terminated; t != nil {
+				cd.LastTermination = newTerminationInfo(t)
+			} else if t := cs.State.Terminated; t != nil {
+				cd.LastTermination = newTerminationInfo(t)
+			}
+			if spec, ok := specs[cs.Name]; ok {
+				cd.Liveness = probeSummary(spec.LivenessProbe)
+				cd.Readiness = probeSummary(spec.ReadinessProbe)
+				cd.Startup = probeSummary(spec.StartupProbe)
+			}
+			d.Containers = append(d.Containers, cd)
+		}
+	}
+	addContainers(pod.Status.InitContainerStatuses, true)
+	addContainers(pod.Status.ContainerStatuses, false)
+	if d.Containers == nil {
+		d.Containers = []containerDiagnosis{}
+	}
+
+	d.Findings = analyzePod(pod, events, node, logs)
+	return d
+}
+
+func newTerminationInfo(t *corev1.ContainerStateTerminated) *terminationInfo {
+	info := &terminationInfo{Reason: t.Reason, ExitCode: t.ExitCode, Signal: t.Signal, Message: t.Message, FinishedAt: t.FinishedAt.Time}
+	if info.Signal == 0 && t.ExitCode > 128 {
+		info.Signal = t.ExitCode - 128
+	}
+	return info
+}
+
+// containerStateString devuelve el estado como "Running", "Waiting (CrashLoopBackOff)"
+// o "Terminated (Error, exit 1)".
+func containerStateString(s corev1.ContainerState) string {
+	switch {
+	case s.Running != nil:
+		return "Running"
+	case s.Waiting != nil:
+		return fmt.Sprintf("Waiting (%s)", s.Waiting.Reason)
+	case s.Terminated != nil:
+		return fmt.Sprintf("Terminated (%s, exit %d)", s.Terminated.Reason, s.Terminated.ExitCode)
+	}
+	return "Unknown"
+}
+
+// probeSummary describe una probe en una línea, ej. "http GET :8080/healthz delay=10s
+// timeout=1s period=10s failure=3". Devuelve "" si la probe no está definida.
+func probeSummary(p *corev1.Probe) string {
+	if p == nil {
+		return ""
+	}
+	var action string
+	switch {
+	case p.HTTPGet != nil:
+		if p.HTTPGet.Scheme == corev1.URISchemeHTTPS {
+			action = fmt.Sprintf("https GET :%s%s", p.HTTPGet.Port.String(), p.HTTPGet.Path)
+		} else {
+			action = fmt.Sprintf("http GET :%s%s", p.HTTPGet.Port.String(), p.HTTPGet.Path)
+		}
+	case p.TCPSocket != nil:
+		action = fmt.Sprintf("tcp :%s", p.TCPSocket.Port.String())
+	case p.GRPC != nil:
+		action = fmt.Sprintf("grpc :%d", p.GRPC.Port)
+	case p.Exec != nil:
+		action = fmt.Sprintf("exec %s", strings.Join(p.Exec.Command, " "))
+	default:
+		action = "desconocida"
+	}
+	return fmt.Sprintf("%s delay=%ds timeout=%ds period=%ds failure=%d",
+		action, p.InitialDelaySeconds, probeTimeout(p), probePeriod(p), probeFailureThreshold(p))
+}
+
+// Valores por defecto de Kubernetes para los campos de una probe sin definir.
+func probeTimeout(p *corev1.Probe) int32 {
+	if p.TimeoutSeconds == 0 {
+		return 1
+	}
+	return p.TimeoutSeconds
+}
+
+func probePeriod(p *corev1.Probe) int32 {
+	if p.PeriodSeconds == 0 {
+		return 10
+	}
+	return p.PeriodSeconds
+}
+
+func probeFailureThreshold(p *corev1.Probe) int32 {
+	if p.FailureThreshold == 0 {
+		return 3
+	}
+	return p.FailureThreshold
+}
+
+func valueOrDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// logErrorPattern reconoce líneas de log que suelen explicar un fallo de arranque.
+var logErrorPattern = regexp.MustCompile(`(?i)(panic:|fatal|exception|error|traceback|out of memory|outofmemory|permission denied|connection refused|no such file|cannot|unable to|failed to)`)
+
+// lastLogErrorLine devuelve la última línea que parece un error, o "".
+func lastLogErrorLine(lines []string) string {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if logErrorPattern.MatchString(lines[i]) {
+			return strings.TrimSpace(lines[i])
+		}
+	}
+	return ""
+}
+
+// analyzePod aplica las reglas de diagnóstico y devuelve las causas probables,
+// de la más a la menos probable dentro de cada severidad.
+func analyzePod(pod corev1.Pod, events []clusterEvent, node *corev1.Node, logs []containerLogTail) []finding {
+	var findings []finding
+	object := fmt.Sprintf("Pod %s/%s", pod.Namespace, pod.Name)
+	add := func(severity, check, message, suggestion string) {
+		findings = append(findings, finding{Severity: severity, Check: check, Object: object, Message: message, Suggestion: suggestion})
+	}
+	errorLineFor := func(container string) string {
+		for _, previous := range []bool{true, false} {
+			for _, l := range logs {
+				if l.Container == container && l.Previous == previous {
+					if line := lastLogErrorLine(l.Lines); line != "" {
+						return line
+					}
+				}
+			}
+		}
+		return ""
+	}
+	specs := map[string]corev1.Container{}
+	for _, c := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		specs[c.Name] = c
+	}
+
+	if pod.Status.Reason == "Evicted" {
+		add(severityWarning, "pod.evicted", fmt.Sprintf("El pod fue desalojado por el kubelet: %s", pod.Status.Message),
+			"Revisa la presión de recursos del nodo (diagnose node) y los requests del pod; un pod desalojado no se reinicia, su controlador crea otro.")
+	}
+
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse {
+			add(severityCritical, "pod.unschedulable", fmt.Sprintf("El pod no se puede programar: %s", cond.Message),
+				"Ejecuta 'eks-review diagnose pending' para ver qué restricción descarta cada nodo.")
+		}
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range statuses {
+		kind := "contenedor"
+		for _, ic := range pod.Status.InitContainerStatuses {
+			if ic.Name == cs.Name {
+				kind = "init container"
+			}
+		}
+		term := cs.LastTerminationState.Terminated
+		if term == nil {
+			term = cs.State.Terminated
+		}
+
+		if w := cs.State.Waiting; w != nil {
+			switch w.Reason {
+			case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+				add(severityCritical, "container.image-pull", fmt.Sprintf("El %s '%s' no puede descargar su imagen (%s): %s", kind, cs.Name, w.Reason, w.Message),
+					"Comprueba el nombre y la etiqueta de la imagen, los imagePullSecrets y, para ECR, que el rol IAM del nodo tenga permisos de lectura.")
+			case "CreateContainerConfigError", "CreateContainerError":
+				add(severityCritical, "container.config", fmt.Sprintf("El %s '%s' no se puede crear (%s): %s", kind, cs.Name, w.Reason, w.Message),
+					"Verifica que existen los ConfigMaps, Secrets y claves referenciados en env/envFrom/volumes.")
+			}
+		}
+
+		if term != nil && (term.ExitCode != 0 || term.Reason == "OOMKilled") {
+			info := newTerminationInfo(term)
+			crashLooping := cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff"
+			severity := severityWarning
+			if crashLooping || pod.Status.Phase == corev1.PodFailed {
+				severity = severityCritical
+			}
+			errLine := errorLineFor(cs.Name)
+			switch {
+			case term.Reason == "OOMKilled":
+				limit := "sin límite de memoria"
+				if spec, ok := specs[cs.Name]; ok {
+					if l := containerResources(spec).MemLimit; l > 0 {
+						limit = "límite " + formatBytes(l)
+					}
+				}
+				add(severity, "container.oomkilled", fmt.Sprintf("El %s '%s' fue terminado por falta de memoria (OOMKilled, %s), %d %s.", kind, cs.Name, limit, cs.RestartCount, pluralize(int(cs.RestartCount), "reinicio", "reinicios")),
+					"Aumenta el límite de memoria o investiga una fuga de memoria; compara el uso real con 'eks-review monitor top pods --containers'.")
+			case info.ExitCode == 137 || info.Signal == 9:
+				add(severity, "container.sigkill", fmt.Sprintf("El %s '%s' recibió SIGKILL (exit 137) sin OOMKilled.", kind, cs.Name),
+					"Suele deberse a una liveness probe que falla o a un apagado que supera terminationGracePeriodSeconds; revisa los eventos Unhealthy.")
+			case info.ExitCode == 143 || info.Signal == 15:
+				add(severityWarning, "container.sigterm", fmt.Sprintf("El %s '%s' terminó por SIGTERM (exit 143).", kind, cs.Name),
+					"Normal durante despliegues o reinicios por liveness; si se repite, revisa las probes y los eventos del pod.")
+			case info.ExitCode == 126 || info.ExitCode == 127:
+				add(severity, "container.command", fmt.Sprintf("El %s '%s' no pudo ejecutar su comando (exit %d: comando no encontrado o no ejecutable).", kind, cs.Name, info.ExitCode),
+					"Revisa command/args y el ENTRYPOINT de la imagen.")
+			default:
+				msg := fmt.Sprintf("El %s '%s' terminó con error (%s, exit %d), %d %s.", kind, cs.Name, valueOrDefault(term.Reason, "Error"), info.ExitCode, cs.RestartCount, pluralize(int(cs.RestartCount), "reinicio", "reinicios"))
+				if errLine != "" {
+					msg += fmt.Sprintf(" Último error en el log: %q", truncateString(errLine, 160))
+				}
+				add(severity, "container.error", msg,
+					fmt.Sprintf("Revisa el log anterior: eks-review monitor logs --pod %s -n %s -c %s --previous", pod.Name, pod.Namespace, cs.Name))
+			}
+		} else if cs.RestartCount >= highRestartCount {
+			add(severityWarning, "container.restarts", fmt.Sprintf("El %s '%s' acumula %d reinicios.", kind, cs.Name, cs.RestartCount),
+				"Revisa los logs anteriores y los eventos para ver el motivo de los reinicios.")
+		}
+	}
+
+	var livenessFailures, readinessFailures, startupFailures int32
+	for _, e := range events {
+		switch {
+		case e.Reason == "Unhealthy" && strings.Contains(e.Message, "Liveness probe failed"):
+			livenessFailures += e.Count
+		case e.Reason == "Unhealthy" && strings.Contains(e.Message, "Readiness probe failed"):
+			readinessFailures += e.Count
+		case e.Reason == "Unhealthy" && strings.Contains(e.Message, "Startup probe failed"):
+			startupFailures += e.Count
+		case e.Reason == "FailedMount" || e.Reason == "FailedAttachVolume":
+			add(severityCritical, "pod.volume", fmt.Sprintf("No se pudo montar un volumen: %s", truncateString(e.Message, 200)),
+				"Comprueba que el PVC está Bound, que el volumen EBS está en la misma zona que el nodo y que los Secrets/ConfigMaps montados existen.")
+		case e.Reason == "FailedCreatePodSandBox":
+			add(severityCritical, "pod.sandbox", fmt.Sprintf("No se pudo crear el sandbox de red: %s", truncateString(e.Message, 200)),
+				"Suele indicar falta de IPs en la subred o un problema del CNI (aws-node); revisa 'eks-review diagnose node'.")
+		}
+	}
+	if livenessFailures > 0 {
+		add(severityWarning, "probe.liveness-failing", fmt.Sprintf("La liveness probe ha fallado %d veces; cada fallo repetido reinicia el contenedor.", livenessFailures),
+			"Aumenta initialDelaySeconds/timeoutSeconds o usa una startupProbe si la aplicación tarda en arrancar.")
+	}
+	if startupFailures > 0 {
+		add(severityWarning, "probe.startup-failing", fmt.Sprintf("La startup probe ha fallado %d veces.", startupFailures),
+			"Aumenta failureThreshold × periodSeconds de la startupProbe hasta cubrir el tiempo real de arranque.")
+	}
+	if readinessFailures > 0 {
+		add(severityWarning, "probe.readiness-failing", fmt.Sprintf("La readiness probe ha fallado %d veces; el pod no recibe tráfico mientras falla.", readinessFailures),
+			"Comprueba que el endpoint de readiness responde y que sus dependencias están disponibles.")
+	}
+
+	if node != nil {
+		if getNodeStatus(*node) != "Ready" {
+			add(severityCritical, "node.not-ready", fmt.Sprintf("El nodo '%s' donde corre el pod no está Ready.", node.Name),
+				fmt.Sprintf("Ejecuta 'eks-review diagnose node %s'.", node.Name))
+		}
+		if pressure := getNodePressure(*node); len(pressure) > 0 {
+			add(severityWarning, "node.pressure", fmt.Sprintf("El nodo '%s' tiene condiciones de presión: %s.", node.Name, strings.Join(pressure, ", ")),
+				"El kubelet puede desalojar pods mientras dure la presión; revisa el consumo del nodo.")
+		}
+	}
+
+	for _, c := range pod.Spec.Containers {
+		if c.ReadinessProbe == nil {
+			add(severityInfo, "probe.readiness-missing", fmt.Sprintf("El contenedor '%s' no tiene readinessProbe: recibe tráfico en cuanto arranca.", c.Name),
+				"Define una readinessProbe si el pod está detrás de un Service.")
+		}
+	}
+
+	sortFindings(findings)
+	return findings
+}
+
+// printPodDiagnosis imprime el informe de diagnose pod.
+func printPodDiagnosis(d podDiagnosis) {
+	fmt.Fprintf(os.Stdout, "\n--- Pod %s/%s ---\n", d.Namespace, d.Pod)
+	PrintBasicTable([]string{"CAMPO", "VALOR"}, [][]string{
+		{"Estado", d.Status},
+		{"Nodo", valueOrNone(d.Node)},
+		{"QoS", valueOrNone(d.QOSClass)},
+	})
+
+	fmt.Fprintln(os.Stdout, "--- Contenedores ---")
+	rows := make([][]string, 0, len(d.Containers))
+	for _, c := range d.Containers {
+		name := c.Name
+		if c.Init {
+			name += " (init)"
+		}
+		last := "<none>"
+		if t := c.LastTermination; t != nil {
+			last = fmt.Sprintf("%s, exit %d", valueOrDefault(t.Reason, "Error"), t.ExitCode)
+			if t.Signal > 0 {
+				last += fmt.Sprintf(", señal %d", t.Signal)
+			}
+			if !t.FinishedAt.IsZero() {
+				last += ", " + formatEventAge(t.FinishedAt)
+			}
+		}
+		rows = append(rows, []string{name, fmt.Sprintf("%t", c.Ready), c.State, fmt.Sprintf("%d", c.Restarts), last})
+	}
+	PrintBasicTable([]string{"NOMBRE", "LISTO", "ESTADO", "REINICIOS", "ÚLTIMA TERMINACIÓN"}, rows)
+
+	fmt.Fprintln(os.Stdout, "--- Probes ---")
+	probeRows := [][]string{}
+	for _, c := range d.Containers {
+		for _, p := range [][2]string{{"liveness", c.Liveness}, {"readiness", c.Readiness}, {"startup", c.Startup}} {
+			if p[1] != "" {
+				probeRows = append(probeRows, []string{c.Name, p[0], p[1]})
+			}
+		}
+	}
+	if len(probeRows) == 0 {
+		fmt.Fprintln(os.Stdout, "No hay probes configuradas.")
+	} else {
+		PrintBasicTable([]string{"CONTENEDOR", "TIPO", "CONFIGURACIÓN"}, probeRows)
+	}
+
+	fmt.Fprintf(os.Stdout, "--- Eventos (%d) ---\n", len(d.Events))
+	if len(d.Events) == 0 {
+		fmt.Fprintln(os.Stdout, "No hay eventos para este pod.")
+	} else {
+		evRows := [][]string{}
+		for _, e := range d.Events {
+			evRows = append(evRows, []string{formatEventAge(e.LastSeen), fmt.Sprintf("%d", e.Count), e.Type, e.Reason, truncateString(e.Message, 100)})
+		}
+		PrintBasicTable([]string{"ÚLTIMA VEZ", "CUENTA", "TIPO", "RAZÓN", "MENSAJE"}, evRows)
+	}
+
+	if len(d.NodeConditions) > 0 {
+		fmt.Fprintf(os.Stdout, "--- Condiciones del nodo %s ---\n", d.Node)
+		condRows := [][]string{}
+		for _, c := range d.NodeConditions {
+			condRows = append(condRows, []string{string(c.Type), string(c.Status), c.Reason})
+		}
+		PrintBasicTable([]string{"TIPO", "ESTADO", "RAZÓN"}, condRows)
+	}
+
+	for _, l := range d.Logs {
+		which := "actual"
+		if l.Previous {
+			which = "anterior"
+		}
+		fmt.Fprintf(os.Stdout, "--- Log %s de '%s' ---\n", which, l.Container)
+		if l.Error != "" {
+			fmt.Fprintf(os.Stdout, "No disponible: %s\n", l.Error)
+		} else if len(l.Lines) == 0 {
+			fmt.Fprintln(os.Stdout, "(vacío)")
+		}
+		for _, line := range l.Lines {
+			fmt.Fprintln(os.Stdout, line)
+		}
+	}
+
+	printFindings("Causas probables", d.Findings)
+}
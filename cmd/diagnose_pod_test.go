@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func testCrashingPod(reason string, exitCode int32) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "api-1"},
+		Spec: corev1.PodSpec{
+			NodeName: "node-a",
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+				},
+				ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+					HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromInt32(8080)},
+				}},
+			}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 7,
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					Reason: reason, ExitCode: exitCode,
+				}},
+			}},
+		},
+	}
+}
+
+func findingChecks(findings []finding) []string {
+	checks := make([]string, 0, len(findings))
+	for _, f := range findings {
+		checks = append(checks, f.Check)
+	}
+	return checks
+}
+
+func TestAnalyzePodTermination(t *testing.T) {
+	tests := []struct {
+		reason    string
+		exitCode  int32
+		wantCheck string
+	}{
+		{"OOMKilled", 137, "container.oomkilled"},
+		{"Error", 137, "container.sigkill"},
+		{"Error", 127, "container.command"},
+		{"Error", 1, "container.error"},
+	}
+	for _, tt := range tests {
+		findings := analyzePod(testCrashingPod(tt.reason, tt.exitCode), nil, nil, nil)
+		if len(findings) != 1 || findings[0].Check != tt.wantCheck {
+			t.Errorf("%s/%d: got checks %v, want [%s]", tt.reason, tt.exitCode, findingChecks(findings), tt.wantCheck)
+			continue
+		}
+		if findings[0].Severity != severityCritical {
+			t.Errorf("%s/%d: crash-looping container should be critical, got %s", tt.reason, tt.exitCode, findings[0].Severity)
+		}
+	}
+
+	oom := analyzePod(testCrashingPod("OOMKilled", 137), nil, nil, nil)
+	if !strings.Contains(oom[0].Message, "256Mi") {
+		t.Errorf("OOMKilled message should mention the memory limit, got %q", oom[0].Message)
+	}
+}
+
+func TestAnalyzePodUsesLogErrorLine(t *testing.T) {
+	logs := []containerLogTail{
+		{Container: "app", Previous: true, Lines: []string{"starting", "FATAL: could not connect to database", "bye"}},
+	}
+	findings := analyzePod(testCrashingPod("Error", 1), nil, nil, logs)
+	if len(findings) == 0 || !strings.Contains(findings[0].Message, "could not connect to database") {
+		t.Errorf("expected last error line in message, got %+v", findings)
+	}
+}
+
+func TestAnalyzePodRanksFindings(t *testing.T) {
+	pod := testCrashingPod("Error", 1)
+	pod.Spec.Containers[0].ReadinessProbe = nil
+	events := []clusterEvent{
+		{Type: "Warning", Kind: "Pod", Name: "api-1", Reason: "Unhealthy", Message: "Liveness probe failed: HTTP probe failed with statuscode: 500", Count: 4, LastSeen: time.Now()},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+		}},
+	}
+
+	findings := analyzePod(pod, events, node, nil)
+	want := []string{"container.error", "probe.liveness-failing", "node.pressure", "probe.readiness-missing"}
+	got := findingChecks(findings)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got checks %v, want %v", got, want)
+	}
+}
+
+func TestAnalyzePodImagePullAndUnschedulable(t *testing.T) {
+	pull := testWaitingPod("pull", "ImagePullBackOff", corev1.PodPending, time.Now())
+	pull.Spec.Containers = []corev1.Container{{Name: "app", ReadinessProbe: &corev1.Probe{}}}
+	if got := findingChecks(analyzePod(pull, nil, nil, nil)); len(got) != 1 || got[0] != "container.image-pull" {
+		t.Errorf("image pull: got checks %v", got)
+	}
+
+	pending := testWaitingPod("pending", "", corev1.PodPending, time.Now())
+	pending.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Message: "0/3 nodes are available"}}
+	if got := findingChecks(analyzePod(pending, nil, nil, nil)); len(got) != 1 || got[0] != "pod.unschedulable" {
+		t.Errorf("unschedulable: got checks %v", got)
+	}
+}
+
+func TestProbeSummary(t *testing.T) {
+	if got := probeSummary(nil); got != "" {
+		t.Errorf("nil probe: got %q", got)
+	}
+	p := &corev1.Probe{
+		ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("http")}},
+		InitialDelaySeconds: 10,
+	}
+	want := "http GET :http/healthz delay=10s timeout=1s period=10s failure=3"
+	if got := probeSummary(p); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+var diagnoseProbesAllNamespaces bool
+
+const (
+	// startupMarginPercent es el porcentaje del margen de la liveness a partir del cual el
+	// arranque observado se considera demasiado cercano.
+	startupMarginPercent = 80
+	// livenessKillRestarts es el número de reinicios a partir del cual se revisa si la
+	// liveness mata el contenedor durante el arranque.
+	livenessKillRestarts = 3
+	// startupReadyFactor acota el arranque observado a este múltiplo del margen de la
+	// liveness: un contenedor que tarda más en estar listo ya habría sido reiniciado, así
+	// que esa transición a listo es posterior al arranque (ej. la readiness fluctuó).
+	startupReadyFactor = 2
+)
+
+// heavyExecCommand detecta probes exec que lanzan intérpretes o clientes pesados en cada
+// ejecución.
+var heavyExecCommand = regexp.MustCompile(`(?:^|[\s/;|&(])(curl|wget|python[0-9.]*|java|node|ruby|php|perl|psql|mysql|mysqladmin|mongo|mongosh|redis-cli|kubectl|aws)(?:\s|$)`)
+
+// probeWorkload es un controlador con su plantilla de pod y los pods que gestiona.
+type probeWorkload struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Template  corev1.PodTemplateSpec
+	Pods      []corev1.Pod
+}
+
+// probeContainerReport resume las probes de un contenedor de un workload.
+type probeContainerReport struct {
+	Workload        string `json:"workload"`
+	Container       string `json:"container"`
+	Liveness        string `json:"liveness,omitempty"`
+	Readiness       string `json:"readiness,omitempty"`
+	Startup         string `json:"startup,omitempty"`
+	Restarts        int32  `json:"restarts"`
+	ObservedStartup string `json:"observedStartup,omitempty"`
+}
+
+// probeLintReport es el informe de diagnose probes.
+type probeLintReport struct {
+	Namespace  string                 `json:"namespace,omitempty"`
+	Workloads  int                    `json:"workloads"`
+	Containers []probeContainerReport `json:"containers"`
+	Findings   []finding              `json:"findings"`
+}
+
+var diagnoseProbesCmd = &cobra.Command{
+	Use:   "probes",
+	Short: "Revisa la configuración de las probes de los workloads.",
+	Long: `El comando diagnose probes analiza las liveness, readiness y startup probes de los
+Deployments, StatefulSets y DaemonSets y las contrasta con lo observado en sus pods:
+
+  - contenedores detrás de un Service sin readinessProbe,
+  - liveness idéntica a la readiness,
+  - arranques observados más largos que el margen que deja la liveness,
+  - contenedores reiniciados repetidamente por la liveness (eventos Unhealthy) sin
+    startupProbe y con initialDelaySeconds demasiado bajo,
+  - probes exec que lanzan comandos pesados (curl, python, java, clientes de bases de datos...).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clients, err := GetKubeClients()
+		if err != nil {
+			return fmt.Errorf("creando clientes de Kubernetes: %w", err)
+		}
+		namespace := GetEffectiveNamespace(diagnoseNamespace, diagnoseProbesAllNamespaces, "default", false)
+		outputLower := strings.ToLower(diagnoseOutputFormat)
+		info := os.Stdout
+		if isStructuredOutput(outputLower) {
+			info = os.Stderr
+		}
+
+		fmt.Fprintf(info, "Analizando probes en %s...\n", clusterScopeLabel(namespace))
+		workloads, services, events, err := collectProbeWorkloads(context.TODO(), clients.Core, namespace)
+		if err != nil {
+			return err
+		}
+		report := analyzeProbes(workloads, services, events)
+		report.Namespace = namespace
+
+		if isStructuredOutput(outputLower) {
+			doc := struct {
+				outputHeader
+				probeLintReport
+			}{newOutputHeader("ProbeLintReport"), report}
+			return writeStructuredOutput(os.Stdout, outputLower, doc)
+		}
+		printProbeLintReport(report)
+		return nil
+	},
+}
+
+func init() {
+	diagnoseCmd.AddCommand(diagnoseProbesCmd)
+	diagnoseProbesCmd.Flags().BoolVarP(&diagnoseProbesAllNamespaces, "all-namespaces", "A", false, "Analizar los workloads de todos los namespaces")
+}
+
+// collectProbeWorkloads lista los Deployments, StatefulSets y DaemonSets con sus pods,
+// además de los Services y los eventos del ámbito. Solo falla si no se pueden listar los pods.
+func collectProbeWorkloads(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]probeWorkload, []corev1.Service, []clusterEvent, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("listando pods: %w", err)
+	}
+	warn := func(resource string, err error) {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar %s: %s\n", resource, describeCollectError(err))
+	}
+	var workloads []probeWorkload
+	addWorkload := func(kind string, meta metav1.ObjectMeta, selector *metav1.LabelSelector, template corev1.PodTemplateSpec) {
+		w := probeWorkload{Kind: kind, Namespace: meta.Namespace, Name: meta.Name, Template: template}
+		if sel, err := metav1.LabelSelectorAsSelector(selector); err == nil && !sel.Empty() {
+			for _, p := range pods.Items {
+				if p.Namespace == meta.Namespace && sel.Matches(labels.Set(p.Labels)) {
+					w.Pods = append(w.Pods, p)
+				}
+			}
+		}
+		workloads = append(workloads, w)
+	}
+	if list, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("deployments", err)
+	} else {
+		for _, d := range list.Items {
+			addWorkload("Deployment", d.ObjectMeta, d.Spec.Selector, d.Spec.Template)
+		}
+	}
+	if list, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("statefulsets", err)
+	} else {
+		for _, s := range list.Items {
+			addWorkload("StatefulSet", s.ObjectMeta, s.Spec.Selector, s.Spec.Template)
+		}
+	}
+	if list, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("daemonsets", err)
+	} else {
+		for _, ds := range list.Items {
+			addWorkload("DaemonSet", ds.ObjectMeta, ds.Spec.Selector, ds.Spec.Template)
+		}
+	}
+	var services []corev1.Service
+	if list, err := clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("services", err)
+	} else {
+		services = list.Items
+	}
+	events, err := listClusterEvents(clientset, namespace)
+	if err != nil {
+		warn("eventos", err)
+	}
+	return workloads, services, events, nil
+}
+
+// livenessAllowance es el tiempo que tiene un contenedor para arrancar antes de que la
+// liveness pueda reiniciarlo: el de la startupProbe si existe (la liveness no empieza
+// hasta que termina) o el de la propia liveness.
+func livenessAllowance(c corev1.Container) time.Duration {
+	p := c.LivenessProbe
+	if c.StartupProbe != nil {
+		p = c.StartupProbe
+	}
+	if p == nil {
+		return 0
+	}
+	return time.Duration(p.InitialDelaySeconds+probePeriod(p)*probeFailureThreshold(p)) * time.Second
+}
+
+// observedStartup devuelve el mayor tiempo observado entre el arranque del contenedor y
+// el momento en que los contenedores del pod pasaron a estar listos. La condición solo
+// guarda la última transición, así que si limit no es cero se descartan los pods cuyo
+// tiempo lo supera: su readiness cambió después del arranque.
+func observedStartup(pods []corev1.Pod, container string, limit time.Duration) time.Duration {
+	var longest time.Duration
+	for _, p := range pods {
+		var readyAt time.Time
+		for _, c := range p.Status.Conditions {
+			if c.Type == corev1.ContainersReady && c.Status == corev1.ConditionTrue {
+				readyAt = c.LastTransitionTime.Time
+			}
+		}
+		if readyAt.IsZero() {
+			continue
+		}
+		for _, cs := range p.Status.ContainerStatuses {
+			if cs.Name != container || cs.State.Running == nil {
+				continue
+			}
+			if d := readyAt.Sub(cs.State.Running.StartedAt.Time); d > longest && (limit == 0 || d <= limit) {
+				longest = d
+			}
+		}
+	}
+	return longest
+}
+
+// livenessKillEvents suma los fallos de liveness y los reinicios provocados por ella en
+// los eventos de los pods, solo para el contenedor indicado. Los eventos Killing nombran
+// el contenedor ("Container app failed liveness probe"); los Unhealthy no, así que solo
+// se le atribuyen cuando es el único contenedor del pod con livenessProbe.
+func livenessKillEvents(pods []corev1.Pod, events []clusterEvent, container string, onlyLiveness bool) (failures, kills int32) {
+	killMessage := fmt.Sprintf("Container %s failed liveness probe", container)
+	for _, p := range pods {
+		for _, e := range podEvents(p, events) {
+			switch {
+			case e.Reason == "Unhealthy" && onlyLiveness && strings.Contains(e.Message, "Liveness probe failed"):
+				failures += e.Count
+			case e.Reason == "Killing" && strings.Contains(e.Message, killMessage):
+				kills += e.Count
+			}
+		}
+	}
+	return failures, kills
+}
+
+// analyzeProbes aplica las comprobaciones de diagnose probes a cada contenedor de cada workload.
+func analyzeProbes(workloads []probeWorkload, services []corev1.Service, events []clusterEvent) probeLintReport {
+	r := probeLintReport{Workloads: len(workloads), Containers: []probeContainerReport{}}
+	sort.SliceStable(workloads, func(i, j int) bool {
+		if workloads[i].Namespace != workloads[j].Namespace {
+			return workloads[i].Namespace < workloads[j].Namespace
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+	var findings []finding
+
+	for _, w := range workloads {
+		object := fmt.Sprintf("%s %s/%s", w.Kind, w.Namespace, w.Name)
+		add := func(severity, check, message, suggestion string) {
+			findings = append(findings, finding{Severity: severity, Check: check, Object: object, Message: message, Suggestion: suggestion})
+		}
+		var backingServices []string
+		for _, svc := range services {
+			if svc.Namespace == w.Namespace && len(svc.Spec.Selector) > 0 &&
+				labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(w.Template.Labels)) {
+				backingServices = append(backingServices, svc.Name)
+			}
+		}
+		containers := w.Template.Spec.Containers
+		withLiveness := 0
+		for _, c := range containers {
+			if c.LivenessProbe != nil {
+				withLiveness++
+			}
+		}
+
+		for _, c := range containers {
+			report := probeContainerReport{
+				Workload:  object,
+				Container: c.Name,
+				Liveness:  probeSummary(c.LivenessProbe),
+				Readiness: probeSummary(c.ReadinessProbe),
+				Startup:   probeSummary(c.StartupProbe),
+			}
+			for _, p := range w.Pods {
+				for _, cs := range p.Status.ContainerStatuses {
+					if cs.Name == c.Name && cs.RestartCount > report.Restarts {
+						report.Restarts = cs.RestartCount
+					}
+				}
+			}
+			startup := observedStartup(w.Pods, c.Name, livenessAllowance(c)*startupReadyFactor)
+			if startup > 0 {
+				report.ObservedStartup = startup.Truncate(time.Second).String()
+			}
+			r.Containers = append(r.Containers, report)
+
+			if c.ReadinessProbe == nil && len(backingServices) > 0 && (len(c.Ports) > 0 || len(containers) == 1) {
+				add(severityWarning, "probe.readiness-missing", fmt.Sprintf("El contenedor '%s' no tiene readinessProbe y recibe tráfico del Service %s en cuanto arranca.", c.Name, strings.Join(backingServices, ", ")),
+					"Define una readinessProbe que compruebe que la aplicación puede atender peticiones; sin ella, los despliegues y reinicios provocan errores 502/503.")
+			}
+			if c.LivenessProbe != nil && c.ReadinessProbe != nil && reflect.DeepEqual(c.LivenessProbe.ProbeHandler, c.ReadinessProbe.ProbeHandler) {
+				add(severityWarning, "probe.liveness-equals-readiness", fmt.Sprintf("La liveness del contenedor '%s' usa la misma comprobación que la readiness (%s).", c.Name, report.Liveness),
+					"Si la readiness depende de servicios externos, un fallo de estos reinicia todos los pods a la vez. Haz que la liveness compruebe solo que el proceso responde (ej. /livez) o dale un failureThreshold mayor.")
+			}
+			if allowance := livenessAllowance(c); c.LivenessProbe != nil && startup > 0 && startup*100 >= allowance*startupMarginPercent {
+				add(severityWarning, "probe.startup-too-short", fmt.Sprintf("El contenedor '%s' tardó hasta %s en estar listo y la liveness permite %s antes de reiniciarlo.", c.Name, report.ObservedStartup, allowance),
+					fmt.Sprintf("Añade una startupProbe con failureThreshold x periodSeconds holgadamente mayor que %s; un arranque algo más lento (nodo cargado, caché fría) acabará en reinicios.", report.ObservedStartup))
+			}
+			failures, kills := livenessKillEvents(w.Pods, events, c.Name, withLiveness == 1)
+			if c.LivenessProbe != nil && c.StartupProbe == nil && report.Restarts >= livenessKillRestarts && (failures > 0 || kills > 0) {
+				msg := fmt.Sprintf("El contenedor '%s' acumula %d reinicios (%d fallos de liveness y %d reinicios por liveness en los eventos) e initialDelaySeconds=%d, sin startupProbe.",
+					c.Name, report.Restarts, failures, kills, c.LivenessProbe.InitialDelaySeconds)
+				suggestion := "Añade una startupProbe para cubrir el arranque en lugar de subir initialDelaySeconds, y revisa que la liveness no dependa de servicios externos."
+				if startup > 0 {
+					suggestion = fmt.Sprintf("El arranque observado es de %s: añade una startupProbe que lo cubra con margen en lugar de subir initialDelaySeconds.", report.ObservedStartup)
+				}
+				add(severityWarning, "probe.initial-delay-low", msg, suggestion)
+			}
+			for _, probe := range []struct {
+				kind string
+				p    *corev1.Probe
+			}{{"liveness", c.LivenessProbe}, {"readiness", c.ReadinessProbe}, {"startup", c.StartupProbe}} {
+				if probe.p == nil || probe.p.Exec == nil {
+					continue
+				}
+				command := strings.Join(probe.p.Exec.Command, " ")
+				if m := heavyExecCommand.FindStringSubmatch(command); m != nil {
+					add(severityWarning, "probe.exec-heavy", fmt.Sprintf("La %s probe del contenedor '%s' ejecuta '%s' (timeout %ds) cada %ds: %s arranca un proceso pesado en cada comprobación.",
+						probe.kind, c.Name, truncateString(command, 80), probeTimeout(probe.p), probePeriod(probe.p), m[1]),
+						"Usa una probe httpGet, tcpSocket o grpc nativa; si necesitas exec, usa un binario ligero y sube timeoutSeconds (las exec que superan el timeout cuentan como fallo).")
+				}
+			}
+		}
+	}
+
+	sortFindings(findings)
+	r.Findings = findings
+	if r.Findings == nil {
+		r.Findings = []finding{}
+	}
+	return r
+}
+
+// printProbeLintReport imprime el informe de diagnose probes.
+func printProbeLintReport(r probeLintReport) {
+	fmt.Fprintf(os.Stdout, "\n--- Probes (%d workloads) ---\n", r.Workloads)
+	rows := make([][]string, 0, len(r.Containers))
+	for _, c := range r.Containers {
+		rows = append(rows, []string{c.Workload, c.Container, valueOrNone(c.Liveness), valueOrNone(c.Readiness), valueOrNone(c.Startup),
+			fmt.Sprintf("%d", c.Restarts), valueOrNone(c.ObservedStartup)})
+	}
+	PrintBasicTable([]string{"WORKLOAD", "CONTENEDOR", "LIVENESS", "READINESS", "STARTUP", "REINICIOS", "ARRANQUE"}, rows)
+	printFindings("Hallazgos", r.Findings)
+}
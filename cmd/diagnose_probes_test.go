@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func testHTTPProbe(path string, delay int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: path, Port: intstr.FromInt32(8080)}},
+		InitialDelaySeconds: delay,
+	}
+}
+
+func testProbeWorkload(c corev1.Container, pods ...corev1.Pod) probeWorkload {
+	return probeWorkload{
+		Kind: "Deployment", Namespace: "prod", Name: "api",
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{c}},
+		},
+		Pods: pods,
+	}
+}
+
+// testStartedPod devuelve un pod cuyo contenedor "app" tardó startup en estar listo.
+func testStartedPod(name string, startup time.Duration, restarts int32) corev1.Pod {
+	started := time.Now().Add(-time.Hour)
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: name},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(started.Add(startup))}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: "app", RestartCount: restarts,
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(started)}},
+			}},
+		},
+	}
+}
+
+func TestAnalyzeProbesHealthy(t *testing.T) {
+	c := corev1.Container{Name: "app", Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+		LivenessProbe: testHTTPProbe("/livez", 0), ReadinessProbe: testHTTPProbe("/readyz", 0)}
+	services := []corev1.Service{{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "api"}, Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "api"}}}}
+	r := analyzeProbes([]probeWorkload{testProbeWorkload(c, testStartedPod("api-1", 5*time.Second, 0))}, services, nil)
+	if len(r.Findings) != 0 {
+		t.Fatalf("expected no findings, got %v", findingChecks(r.Findings))
+	}
+	if r.Containers[0].ObservedStartup != "5s" || !strings.HasPrefix(r.Containers[0].Liveness, "http GET :8080/livez") {
+		t.Errorf("unexpected container report: %+v", r.Containers[0])
+	}
+}
+
+func TestAnalyzeProbesProblems(t *testing.T) {
+	services := []corev1.Service{{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "api"}, Spec: corev1.ServiceSpec{Selector: map[string]string{"app": "api"}}}}
+
+	// Sin readiness y con la liveness matando el contenedor durante un arranque de 40s.
+	c := corev1.Container{Name: "app", Ports: []corev1.ContainerPort{{ContainerPort: 8080}}, LivenessProbe: testHTTPProbe("/healthz", 5)}
+	events := []clusterEvent{
+		{Kind: "Pod", Namespace: "prod", Name: "api-1", Reason: "Unhealthy", Message: "Liveness probe failed: HTTP probe failed with statuscode: 503", Count: 12},
+		{Kind: "Pod", Namespace: "prod", Name: "api-1", Reason: "Killing", Message: "Container app failed liveness probe, will be restarted", Count: 4},
+	}
+	r := analyzeProbes([]probeWorkload{testProbeWorkload(c, testStartedPod("api-1", 40*time.Second, 4))}, services, events)
+	if got := strings.Join(findingChecks(r.Findings), ","); got != "probe.readiness-missing,probe.startup-too-short,probe.initial-delay-low" {
+		t.Errorf("got checks %s", got)
+	}
+
+	// Liveness igual a la readiness y exec pesado.
+	c = corev1.Container{Name: "app", LivenessProbe: testHTTPProbe("/health", 10), ReadinessProbe: testHTTPProbe("/health", 0),
+		StartupProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"sh", "-c", "curl -sf localhost:8080/health"}}}}}
+	r = analyzeProbes([]probeWorkload{testProbeWorkload(c)}, nil, nil)
+	if got := strings.Join(findingChecks(r.Findings), ","); got != "probe.liveness-equals-readiness,probe.exec-heavy" {
+		t.Errorf("got checks %s", got)
+	}
+}
+
+func TestAnalyzeProbesLivenessKillsScopedToContainer(t *testing.T) {
+	app := corev1.Container{Name: "app", LivenessProbe: testHTTPProbe("/livez", 5)}
+	sidecar := corev1.Container{Name: "proxy", LivenessProbe: testHTTPProbe("/ready", 5)}
+	w := testProbeWorkload(app)
+	w.Template.Spec.Containers = append(w.Template.Spec.Containers, sidecar)
+	pod := testStartedPod("api-1", 5*time.Second, 4)
+	pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{Name: "proxy", RestartCount: 6,
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}})
+	w.Pods = []corev1.Pod{pod}
+	events := []clusterEvent{
+		{Kind: "Pod", Namespace: "prod", Name: "api-1", Reason: "Unhealthy", Message: "Liveness probe failed: HTTP probe failed with statuscode: 503", Count: 12},
+		{Kind: "Pod", Namespace: "prod", Name: "api-1", Reason: "Killing", Message: "Container app failed liveness probe, will be restarted", Count: 4},
+	}
+	r := analyzeProbes([]probeWorkload{w}, nil, events)
+	if len(r.Findings) != 1 || r.Findings[0].Check != "probe.initial-delay-low" || !strings.Contains(r.Findings[0].Message, "'app'") {
+		t.Errorf("expected only the app container to be flagged, got %+v", r.Findings)
+	}
+}
+
+func TestLivenessAllowance(t *testing.T) {
+	c := corev1.Container{LivenessProbe: &corev1.Probe{InitialDelaySeconds: 10, PeriodSeconds: 5, FailureThreshold: 3}}
+	if got := livenessAllowance(c); got != 25*time.Second {
+		t.Errorf("liveness only: got %s, want 25s", got)
+	}
+	c.StartupProbe = &corev1.Probe{PeriodSeconds: 10, FailureThreshold: 30}
+	if got := livenessAllowance(c); got != 300*time.Second {
+		t.Errorf("with startup probe: got %s, want 5m0s", got)
+	}
+}
+
+func TestAnalyzeProbesIgnoresReadinessFlap(t *testing.T) {
+	c := corev1.Container{Name: "app", LivenessProbe: testHTTPProbe("/livez", 5), ReadinessProbe: testHTTPProbe("/readyz", 0)}
+	// Arrancó en 5s, pero la readiness volvió a pasar a true 50 minutos después.
+	r := analyzeProbes([]probeWorkload{testProbeWorkload(c, testStartedPod("api-1", 5*time.Second, 0), testStartedPod("api-2", 50*time.Minute, 0))}, nil, nil)
+	if len(r.Findings) != 0 {
+		t.Fatalf("expected no findings, got %v", findingChecks(r.Findings))
+	}
+	if r.Containers[0].ObservedStartup != "5s" {
+		t.Errorf("observed startup: got %q, want 5s", r.Containers[0].ObservedStartup)
+	}
+}
@@ -0,0 +1,456 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// loadBalancerGracePeriod es el tiempo que se concede a un LoadBalancer nuevo para
+// obtener dirección antes de considerarlo un problema.
+const loadBalancerGracePeriod = 5 * time.Minute
+
+// serviceEndpoint es una dirección de un EndpointSlice del Service.
+type serviceEndpoint struct {
+	Address string `json:"address"`
+	Pod     string `json:"pod,omitempty"`
+	Node    string `json:"node,omitempty"`
+	Ready   bool   `json:"ready"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// servicePodSummary es un pod seleccionado por el Service.
+type servicePodSummary struct {
+	Name   string `json:"name"`
+	Ready  bool   `json:"ready"`
+	Status string `json:"status"`
+	IP     string `json:"ip,omitempty"`
+	Node   string `json:"node,omitempty"`
+}
+
+// serviceDiagnosis es el informe de diagnose service.
+type serviceDiagnosis struct {
+	Namespace         string              `json:"namespace"`
+	Service           string              `json:"service"`
+	Type              string              `json:"type"`
+	ClusterIP         string              `json:"clusterIP"`
+	ExternalIP        string              `json:"externalIP"`
+	Selector          map[string]string   `json:"selector"`
+	Ports             []string            `json:"ports"`
+	Pods              []servicePodSummary `json:"pods"`
+	Endpoints         []serviceEndpoint   `json:"endpoints"`
+	ReadyEndpoints    int                 `json:"readyEndpoints"`
+	NotReadyEndpoints int                 `json:"notReadyEndpoints"`
+	Findings          []finding           `json:"findings"`
+}
+
+var diagnoseServiceCmd = &cobra.Command{
+	Use:     "service <nombre-del-service>",
+	Aliases: []string{"svc"},
+	Short:   "Comprueba selector, endpoints y puertos de un Service.",
+	Long: `El comando diagnose service comprueba que el selector del Service selecciona
+pods, que cada targetPort (numérico o con nombre) existe en los contenedores de esos
+pods, cuántos endpoints están listos y por qué no lo están los demás (readiness
+probes que fallan, pods que no están Running) y, para los Services LoadBalancer, si
+la dirección externa sigue pendiente.
+
+En lugar de la salida en bruto devuelve hallazgos concretos con el siguiente paso
+sugerido.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clients, err := GetKubeClients()
+		if err != nil {
+			return fmt.Errorf("creando clientes de Kubernetes: %w", err)
+		}
+		namespace := GetEffectiveNamespace(diagnoseNamespace, false, "default", false)
+		outputLower := strings.ToLower(diagnoseOutputFormat)
+		info := os.Stdout
+		if isStructuredOutput(outputLower) {
+			info = os.Stderr
+		}
+
+		fmt.Fprintf(info, "Diagnosticando service '%s' en namespace '%s'...\n", args[0], namespace)
+		diag, err := collectServiceDiagnosis(context.TODO(), clients.Core, namespace, args[0], time.Now())
+		if err != nil {
+			return err
+		}
+
+		if isStructuredOutput(outputLower) {
+			doc := struct {
+				outputHeader
+				serviceDiagnosis
+			}{newOutputHeader("ServiceDiagnosis"), diag}
+			return writeStructuredOutput(os.Stdout, outputLower, doc)
+		}
+		printServiceDiagnosis(diag)
+		return nil
+	},
+}
+
+func init() {
+	diagnoseCmd.AddCommand(diagnoseServiceCmd)
+}
+
+// collectServiceDiagnosis recupera el Service, los pods del namespace, sus EndpointSlices
+// y los eventos, y los analiza.
+func collectServiceDiagnosis(ctx context.Context, clientset kubernetes.Interface, namespace, name string, now time.Time) (serviceDiagnosis, error) {
+	svc, err := clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return serviceDiagnosis{}, fmt.Errorf("obteniendo service '%s' en namespace '%s': %w", name, namespace, err)
+	}
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return serviceDiagnosis{}, fmt.Errorf("listando pods en namespace '%s': %w", namespace, err)
+	}
+	slices, err := listServiceEndpointSlices(ctx, clientset, namespace, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar endpointslices: %s\n", describeCollectError(err))
+	}
+	events, err := listClusterEvents(clientset, namespace)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar eventos: %s\n", describeCollectError(err))
+	}
+	return analyzeService(*svc, pods.Items, slices, events, now), nil
+}
+
+// listServiceEndpointSlices devuelve los EndpointSlices que pertenecen al Service. La
+// lista nunca es nil si no hay error, para distinguir "sin endpoints" de "desconocido".
+func listServiceEndpointSlices(ctx context.Context, clientset kubernetes.Interface, namespace, service string) ([]discoveryv1.EndpointSlice, error) {
+	list, err := clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + service,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]discoveryv1.EndpointSlice{}, list.Items...), nil
+}
+
+// endpointReady interpreta la condición ready; si no se informa, el endpoint se considera listo.
+func endpointReady(e discoveryv1.Endpoint) bool {
+	return e.Conditions.Ready == nil || *e.Conditions.Ready
+}
+
+// countEndpoints devuelve los endpoints listos y no listos de los EndpointSlices.
+func countEndpoints(slices []discoveryv1.EndpointSlice) (ready, notReady int) {
+	for _, s := range slices {
+		for _, e := range s.Endpoints {
+			if endpointReady(e) {
+				ready++
+			} else {
+				notReady++
+			}
+		}
+	}
+	return ready, notReady
+}
+
+// podNotReadyReason explica por qué un pod no está listo, usando el último evento de
+// readiness fallida cuando existe.
+func podNotReadyReason(pod corev1.Pod, events []clusterEvent) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return podDisplayStatus(pod)
+	}
+	var latest clusterEvent
+	for _, e := range events {
+		if e.Kind == "Pod" && e.Namespace == pod.Namespace && e.Name == pod.Name && e.Reason == "Unhealthy" && strings.Contains(e.Message, "Readiness probe failed") && !e.LastSeen.Before(latest.LastSeen) {
+			latest = e
+		}
+	}
+	if latest.Message != "" {
+		return truncateString(latest.Message, 120)
+	}
+	var notReady []string
+	for _, cs := range pod.Status.ContainerStatuses {
+		if !cs.Ready {
+			notReady = append(notReady, cs.Name)
+		}
+	}
+	if len(notReady) > 0 {
+		return fmt.Sprintf("contenedores no listos: %s", strings.Join(notReady, ", "))
+	}
+	return "no listo"
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// containerPortExists comprueba si algún contenedor del pod expone el targetPort. Para
+// puertos numéricos devuelve también si el pod declara algún puerto, ya que declarar
+// containerPort es opcional y solo entonces se puede afirmar que falta.
+func containerPortExists(pod corev1.Pod, target intstr.IntOrString) (found, declaresPorts bool) {
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			declaresPorts = true
+			if target.Type == intstr.String && p.Name == target.StrVal {
+				return true, true
+			}
+			if target.Type == intstr.Int && p.ContainerPort == target.IntVal {
+				return true, true
+			}
+		}
+	}
+	return false, declaresPorts
+}
+
+// declaredPorts lista los puertos declarados por los contenedores del pod, ej. "http:8080".
+func declaredPorts(pod corev1.Pod) []string {
+	var ports []string
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name != "" {
+				ports = append(ports, fmt.Sprintf("%s:%d", p.Name, p.ContainerPort))
+			} else {
+				ports = append(ports, fmt.Sprintf("%d", p.ContainerPort))
+			}
+		}
+	}
+	return ports
+}
+
+// selectorNearMiss busca pods que cumplen todas las etiquetas del selector menos una,
+// la causa más habitual de un Service sin endpoints (ej. app=api frente a app=api-v2).
+func selectorNearMiss(selector map[string]string, pods []corev1.Pod) string {
+	for _, p := range pods {
+		var mismatch []string
+		for k, v := range selector {
+			if actual, ok := p.Labels[k]; !ok {
+				mismatch = append(mismatch, fmt.Sprintf("no tiene la etiqueta %s", k))
+			} else if actual != v {
+				mismatch = append(mismatch, fmt.Sprintf("tiene %s=%s en lugar de %s", k, actual, v))
+			}
+		}
+		if len(mismatch) == 1 {
+			return fmt.Sprintf("el pod '%s' %s", p.Name, mismatch[0])
+		}
+	}
+	return ""
+}
+
+// analyzeService aplica las comprobaciones de diagnose service. slices es nil si no se
+// pudieron listar los EndpointSlices.
+func analyzeService(svc corev1.Service, nsPods []corev1.Pod, slices []discoveryv1.EndpointSlice, events []clusterEvent, now time.Time) serviceDiagnosis {
+	d := serviceDiagnosis{
+		Namespace:  svc.Namespace,
+		Service:    svc.Name,
+		Type:       string(svc.Spec.Type),
+		ClusterIP:  svc.Spec.ClusterIP,
+		ExternalIP: serviceExternalIP(svc),
+		Selector:   svc.Spec.Selector,
+		Ports:      servicePorts(svc),
+		Pods:       []servicePodSummary{},
+		Endpoints:  []serviceEndpoint{},
+	}
+	object := fmt.Sprintf("Service %s/%s", svc.Namespace, svc.Name)
+	var findings []finding
+	add := func(severity, check, message, suggestion string) {
+		findings = append(findings, finding{Severity: severity, Check: check, Object: object, Message: message, Suggestion: suggestion})
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		add(severityInfo, "service.external-name", fmt.Sprintf("Service ExternalName hacia '%s': no usa selector ni endpoints.", svc.Spec.ExternalName),
+			"Si no resuelve, comprueba el DNS externo con 'eks-review diagnose dns'.")
+		d.Findings = findings
+		return d
+	}
+
+	var selected []corev1.Pod
+	if len(svc.Spec.Selector) > 0 {
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		for _, p := range nsPods {
+			if selector.Matches(labels.Set(p.Labels)) {
+				selected = append(selected, p)
+			}
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Name < selected[j].Name })
+	podsByName := map[string]corev1.Pod{}
+	for _, p := range selected {
+		podsByName[p.Name] = p
+		d.Pods = append(d.Pods, servicePodSummary{Name: p.Name, Ready: isPodReady(p), Status: podDisplayStatus(p), IP: p.Status.PodIP, Node: p.Spec.NodeName})
+	}
+
+	for _, s := range slices {
+		for _, e := range s.Endpoints {
+			ep := serviceEndpoint{Ready: endpointReady(e)}
+			if len(e.Addresses) > 0 {
+				ep.Address = e.Addresses[0]
+			}
+			if e.NodeName != nil {
+				ep.Node = *e.NodeName
+			}
+			if e.TargetRef != nil && e.TargetRef.Kind == "Pod" {
+				ep.Pod = e.TargetRef.Name
+				if !ep.Ready {
+					if p, ok := podsByName[ep.Pod]; ok {
+						ep.Reason = podNotReadyReason(p, events)
+					} else if e.Conditions.Terminating != nil && *e.Conditions.Terminating {
+						ep.Reason = "Terminating"
+					}
+				}
+			}
+			d.Endpoints = append(d.Endpoints, ep)
+		}
+	}
+	d.ReadyEndpoints, d.NotReadyEndpoints = countEndpoints(slices)
+
+	switch {
+	case len(svc.Spec.Selector) == 0:
+		if len(d.Endpoints) == 0 {
+			add(severityWarning, "service.no-selector", "El Service no tiene selector ni endpoints: sus endpoints deben gestionarse manualmente y no hay ninguno.",
+				"Añade un selector o crea el EndpointSlice manual (ej. para servicios externos al clúster).")
+		} else {
+			add(severityInfo, "service.no-selector", "El Service no tiene selector: sus endpoints se gestionan manualmente.", "")
+		}
+	case len(selected) == 0:
+		msg := fmt.Sprintf("El selector %s no selecciona ningún pod del namespace.", labels.SelectorFromSet(svc.Spec.Selector).String())
+		if hint := selectorNearMiss(svc.Spec.Selector, nsPods); hint != "" {
+			msg += fmt.Sprintf(" Coincidencia parcial: %s.", hint)
+		}
+		add(severityCritical, "service.no-pods", msg,
+			"Corrige el selector del Service o las etiquetas del template del Deployment/StatefulSet.")
+	}
+
+	// Puertos: un targetPort con nombre que no existe en un pod deja ese pod fuera de
+	// los endpoints; uno numérico puede funcionar sin declararse, por eso solo avisa.
+	for _, port := range svc.Spec.Ports {
+		target := port.TargetPort
+		if target.Type == intstr.Int && target.IntVal == 0 {
+			target = intstr.FromInt32(port.Port)
+		}
+		var missing []string
+		var declared []string
+		for _, p := range selected {
+			found, declares := containerPortExists(p, target)
+			if found {
+				continue
+			}
+			if target.Type == intstr.String || declares {
+				missing = append(missing, p.Name)
+				if len(declared) == 0 {
+					declared = declaredPorts(p)
+				}
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		portName := fmt.Sprintf("%d", port.Port)
+		if port.Name != "" {
+			portName = fmt.Sprintf("%s (%d)", port.Name, port.Port)
+		}
+		msg := fmt.Sprintf("El targetPort %s del puerto %s no existe en %d de %d pods seleccionados (ej. %s); declaran: %s.",
+			target.String(), portName, len(missing), len(selected), missing[0], valueOrNone(strings.Join(declared, ", ")))
+		if target.Type == intstr.String {
+			add(severityCritical, "service.target-port", msg, "Usa el nombre de un containerPort existente o el número de puerto en targetPort.")
+		} else {
+			add(severityWarning, "service.target-port", msg, "Comprueba en qué puerto escucha la aplicación y ajusta targetPort o containerPort.")
+		}
+	}
+
+	// Sin EndpointSlices (nil) no se puede saber si hay endpoints listos.
+	if len(selected) > 0 && len(svc.Spec.Selector) > 0 && slices != nil {
+		switch {
+		case d.ReadyEndpoints == 0:
+			add(severityCritical, "service.no-ready-endpoints", fmt.Sprintf("El Service no tiene endpoints listos (%d no listos, %d pods seleccionados): no recibe tráfico.", d.NotReadyEndpoints, len(selected)),
+				notReadySuggestion(d.Endpoints, svc.Namespace))
+		case d.NotReadyEndpoints > 0:
+			add(severityWarning, "service.not-ready-endpoints", fmt.Sprintf("%d de %d endpoints no están listos.", d.NotReadyEndpoints, d.ReadyEndpoints+d.NotReadyEndpoints),
+				notReadySuggestion(d.Endpoints, svc.Namespace))
+		}
+		if svc.Spec.PublishNotReadyAddresses {
+			add(severityInfo, "service.publish-not-ready", "publishNotReadyAddresses=true: el Service enruta también a pods que no están listos.", "")
+		}
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer && d.ExternalIP == "<pending>" {
+		age := now.Sub(svc.CreationTimestamp.Time)
+		msg := fmt.Sprintf("La dirección externa del LoadBalancer sigue pendiente tras %s.", age.Truncate(time.Second))
+		for _, e := range events {
+			if e.Kind == "Service" && e.Namespace == svc.Namespace && e.Name == svc.Name && e.Type == "Warning" {
+				msg += fmt.Sprintf(" Último aviso: %s: %s", e.Reason, truncateString(e.Message, 160))
+				break
+			}
+		}
+		suggestion := "Comprueba que el AWS Load Balancer Controller está en ejecución, que las subredes tienen las etiquetas kubernetes.io/role/elb (o internal-elb) y revisa los eventos del Service."
+		if age < loadBalancerGracePeriod {
+			add(severityInfo, "service.lb-pending", msg, suggestion)
+		} else {
+			add(severityCritical, "service.lb-pending", msg, suggestion)
+		}
+	}
+
+	sortFindings(findings)
+	d.Findings = findings
+	if d.Findings == nil {
+		d.Findings = []finding{}
+	}
+	return d
+}
+
+// notReadySuggestion propone revisar el primer pod no listo con diagnose pod.
+func notReadySuggestion(endpoints []serviceEndpoint, namespace string) string {
+	for _, e := range endpoints {
+		if !e.Ready && e.Pod != "" {
+			return fmt.Sprintf("Revisa la readiness probe y los logs: eks-review diagnose pod %s -n %s", e.Pod, namespace)
+		}
+	}
+	return "Revisa la readiness probe de los pods seleccionados con 'eks-review diagnose pod'."
+}
+
+// printServiceDiagnosis imprime el informe de diagnose service.
+func printServiceDiagnosis(d serviceDiagnosis) {
+	fmt.Fprintf(os.Stdout, "\n--- Service %s/%s ---\n", d.Namespace, d.Service)
+	var selector []string
+	for k, v := range d.Selector {
+		selector = append(selector, k+"="+v)
+	}
+	sort.Strings(selector)
+	PrintBasicTable([]string{"CAMPO", "VALOR"}, [][]string{
+		{"Tipo", d.Type},
+		{"ClusterIP", valueOrNone(d.ClusterIP)},
+		{"IP externa", d.ExternalIP},
+		{"Selector", valueOrNone(strings.Join(selector, ","))},
+		{"Puertos", valueOrNone(strings.Join(d.Ports, ","))},
+		{"Endpoints", fmt.Sprintf("%d listos, %d no listos", d.ReadyEndpoints, d.NotReadyEndpoints)},
+	})
+
+	if len(d.Pods) > 0 {
+		fmt.Fprintf(os.Stdout, "--- Pods seleccionados (%d) ---\n", len(d.Pods))
+		rows := make([][]string, 0, len(d.Pods))
+		for _, p := range d.Pods {
+			rows = append(rows, []string{p.Name, fmt.Sprintf("%t", p.Ready), p.Status, valueOrNone(p.IP), valueOrNone(p.Node)})
+		}
+		PrintBasicTable([]string{"POD", "LISTO", "ESTADO", "IP", "NODO"}, rows)
+	}
+
+	if len(d.Endpoints) > 0 {
+		fmt.Fprintln(os.Stdout, "--- Endpoints ---")
+		rows := make([][]string, 0, len(d.Endpoints))
+		for _, e := range d.Endpoints {
+			rows = append(rows, []string{e.Address, valueOrNone(e.Pod), fmt.Sprintf("%t", e.Ready), valueOrNone(e.Reason)})
+		}
+		PrintBasicTable([]string{"DIRECCIÓN", "POD", "LISTO", "MOTIVO"}, rows)
+	}
+
+	printFindings("Hallazgos", d.Findings)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func testServicePod(name string, labels map[string]string, ready bool, ports ...corev1.ContainerPort) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: name, Labels: labels},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Ports: ports}}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: ready}},
+		},
+	}
+}
+
+func testEndpointSlice(endpoints ...discoveryv1.Endpoint) []discoveryv1.EndpointSlice {
+	return []discoveryv1.EndpointSlice{{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "api-abc"}, Endpoints: endpoints}}
+}
+
+func testEndpoint(ip, pod string, ready bool) discoveryv1.Endpoint {
+	return discoveryv1.Endpoint{
+		Addresses:  []string{ip},
+		Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+		TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: pod},
+	}
+}
+
+func testService(targetPort intstr.IntOrString) corev1.Service {
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "api"},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: map[string]string{"app": "api"},
+			Ports:    []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: targetPort, Protocol: corev1.ProtocolTCP}},
+		},
+	}
+}
+
+func TestAnalyzeServiceSelectorNearMiss(t *testing.T) {
+	pods := []corev1.Pod{testServicePod("api-v2-1", map[string]string{"app": "api-v2"}, true)}
+	d := analyzeService(testService(intstr.FromInt32(8080)), pods, testEndpointSlice(), nil, time.Now())
+	if got := strings.Join(findingChecks(d.Findings), ","); got != "service.no-pods" {
+		t.Fatalf("got checks %s, want service.no-pods", got)
+	}
+	if !strings.Contains(d.Findings[0].Message, "app=api-v2") {
+		t.Errorf("expected near-miss hint in message, got %q", d.Findings[0].Message)
+	}
+}
+
+func TestAnalyzeServiceTargetPortAndReadiness(t *testing.T) {
+	labels := map[string]string{"app": "api"}
+	pods := []corev1.Pod{
+		testServicePod("api-1", labels, true, corev1.ContainerPort{Name: "web", ContainerPort: 8080}),
+		testServicePod("api-2", labels, false, corev1.ContainerPort{Name: "web", ContainerPort: 8080}),
+	}
+	events := []clusterEvent{{Kind: "Pod", Namespace: "prod", Name: "api-2", Reason: "Unhealthy", Message: "Readiness probe failed: connection refused", LastSeen: time.Now()}}
+	slices := testEndpointSlice(testEndpoint("10.0.0.1", "api-1", true), testEndpoint("10.0.0.2", "api-2", false))
+
+	d := analyzeService(testService(intstr.FromString("http")), pods, slices, events, time.Now())
+	if got := strings.Join(findingChecks(d.Findings), ","); got != "service.target-port,service.not-ready-endpoints" {
+		t.Errorf("got checks %s", got)
+	}
+	if d.Findings[0].Severity != severityCritical {
+		t.Errorf("missing named targetPort should be critical, got %s", d.Findings[0].Severity)
+	}
+	if d.ReadyEndpoints != 1 || d.NotReadyEndpoints != 1 {
+		t.Errorf("got %d ready / %d not ready endpoints, want 1/1", d.ReadyEndpoints, d.NotReadyEndpoints)
+	}
+	if d.Endpoints[1].Reason != "Readiness probe failed: connection refused" {
+		t.Errorf("not-ready reason: got %q", d.Endpoints[1].Reason)
+	}
+
+	d = analyzeService(testService(intstr.FromInt32(8080)), pods, slices, events, time.Now())
+	if got := strings.Join(findingChecks(d.Findings), ","); got != "service.not-ready-endpoints" {
+		t.Errorf("numeric targetPort present: got checks %s", got)
+	}
+}
+
+func TestAnalyzeServiceNoReadyEndpointsAndLoadBalancer(t *testing.T) {
+	now := time.Now()
+	pods := []corev1.Pod{testServicePod("api-1", map[string]string{"app": "api"}, false)}
+	svc := testService(intstr.FromInt32(8080))
+	svc.Spec.Type = corev1.ServiceTypeLoadBalancer
+	svc.CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+	events := []clusterEvent{
+		{Kind: "Service", Namespace: "staging", Name: "api", Type: "Warning", Reason: "SyncLoadBalancerFailed", Message: "quota exceeded"},
+		{Kind: "Service", Namespace: "prod", Name: "api", Type: "Warning", Reason: "SyncLoadBalancerFailed", Message: "could not find any suitable subnets"},
+	}
+
+	d := analyzeService(svc, pods, testEndpointSlice(testEndpoint("10.0.0.1", "api-1", false)), events, now)
+	if got := strings.Join(findingChecks(d.Findings), ","); got != "service.no-ready-endpoints,service.lb-pending" {
+		t.Errorf("got checks %s", got)
+	}
+	for _, f := range d.Findings {
+		if f.Check == "service.lb-pending" && !strings.Contains(f.Message, "suitable subnets") {
+			t.Errorf("lb-pending should quote the last warning event, got %q", f.Message)
+		}
+	}
+
+	// Sin EndpointSlices no se puede afirmar que falten endpoints listos.
+	d = analyzeService(testService(intstr.FromInt32(8080)), pods, nil, nil, now)
+	if got := strings.Join(findingChecks(d.Findings), ","); got != "" {
+		t.Errorf("unknown endpoints: got checks %s", got)
+	}
+}
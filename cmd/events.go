@@ -2,24 +2,66 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
 )
 
 var eventType string
 var eventsNamespace string
+var eventsSince time.Duration
+var eventsReason string
+var eventsKind string
+var eventsFor string
+var eventsWatch bool
+var eventsOutput string
+var eventsGroupBy string
+
+// clusterEvent es una vista normalizada de un evento, independiente de si proviene
+// de events.k8s.io/v1 o de core/v1.
+type clusterEvent struct {
+	Namespace string    `json:"namespace"`
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Message   string    `json:"message"`
+	Count     int32     `json:"count"`
+	FirstSeen time.Time `json:"firstSeen,omitempty"`
+	LastSeen  time.Time `json:"lastSeen,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	Host      string    `json:"host,omitempty"`
+}
+
+// eventFilter agrupa los filtros aplicables a la lista de eventos.
+type eventFilter struct {
+	Type    string
+	Reasons []string
+	Kind    string
+	ForKind string
+	ForName string
+	Since   time.Duration
+}
 
 var eventsCmd = &cobra.Command{
 	Use:   "events",
 	Short: "Muestra eventos recientes del clúster.",
 	Long: `El comando events recupera y muestra eventos recientes de Kubernetes,
-útiles para la resolución de problemas. Puedes filtrar por tipo (Warning, Normal) y namespace.`,
+útiles para la resolución de problemas. Usa la API events.k8s.io/v1 (con
+core/v1 como alternativa), agrupa repeticiones en la columna CUENTA y ordena
+del más reciente al más antiguo. Puedes filtrar por tipo, razón, kind,
+objeto, antigüedad y namespace.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		clients, err := GetKubeClients()
 		if err != nil {
@@ -27,68 +69,150 @@ var eventsCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		fmt.Fprintln(os.Stdout, "Recuperando eventos de Kubernetes...")
+		filter, err := newEventFilter()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		output := strings.ToLower(eventsOutput)
+		switch output {
+		case "", "ndjson", "json", "yaml":
+		default:
+			fmt.Fprintf(os.Stderr, "Error: formato de salida no soportado '%s' (usa json, yaml o ndjson).\n", eventsOutput)
+			os.Exit(1)
+		}
+		if eventsWatch && isStructuredOutput(output) {
+			fmt.Fprintf(os.Stderr, "Error: --watch solo admite salida de texto o ndjson.\n")
+			os.Exit(1)
+		}
+		// Con salida estructurada los mensajes informativos van a stderr para no mezclarse con los datos.
+		info := os.Stdout
+		if output != "" || eventsWatch {
+			info = os.Stderr
+		}
+
+		fmt.Fprintln(info, "Recuperando eventos de Kubernetes...")
 
 		namespaceToList := GetEffectiveNamespace(eventsNamespace, false, "default", true)
 
 		if eventsNamespace == "" && namespaceToList == "default" && !strings.EqualFold(eventsNamespace, "all") {
-			fmt.Fprintf(os.Stdout, "No se especificó namespace para eventos. Usando namespace '%s'. Use -n <namespace> o -n all.\n", namespaceToList)
+			fmt.Fprintf(info, "No se especificó namespace para eventos. Usando namespace '%s'. Use -n <namespace> o -n all.\n", namespaceToList)
 		} else if strings.EqualFold(eventsNamespace, "all") {
-			fmt.Fprintln(os.Stdout, "Recuperando eventos de todos los namespaces.")
+			fmt.Fprintln(info, "Recuperando eventos de todos los namespaces.")
 		} else if namespaceToList != "" { // Añadido para ser explícito
-			fmt.Fprintf(os.Stdout, "Recuperando eventos del namespace '%s'.\n", namespaceToList)
+			fmt.Fprintf(info, "Recuperando eventos del namespace '%s'.\n", namespaceToList)
+		}
+
+		groupBy := strings.ToLower(eventsGroupBy)
+		switch groupBy {
+		case "", eventGroupReason, eventGroupObject, eventGroupNamespace, eventGroupNode:
+		default:
+			fmt.Fprintf(os.Stderr, "Error: --group-by debe ser reason, object, namespace o node.\n")
+			os.Exit(1)
+		}
+		if groupBy != "" && eventsWatch {
+			fmt.Fprintf(os.Stderr, "Error: --group-by no es compatible con --watch.\n")
+			os.Exit(1)
+		}
+		if groupBy != "" && filter.Type == "" {
+			// El resumen agrupado está pensado para detectar problemas: por defecto solo Warnings.
+			filter.Type = corev1.EventTypeWarning
+		}
+
+		if eventsWatch {
+			if err := runEventsWatch(clients.Core, namespaceToList, filter, output); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
 		}
 
-		listOptions := metav1.ListOptions{}
-		events, err := clients.Core.CoreV1().Events(namespaceToList).List(context.TODO(), listOptions)
+		events, err := listClusterEvents(clients.Core, namespaceToList)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error listando eventos: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Fprintln(os.Stdout, "\n--- Eventos ---")
-		if len(events.Items) == 0 {
-			fmt.Fprintln(os.Stdout, "No se encontraron eventos.")
-		} else {
-			headers := []string{"ÚLTIMA VEZ", "TIPO", "RAZÓN", "OBJETO", "MENSAJE", "NAMESPACE"}
-			var rows [][]string
+		if Verbose {
+			fmt.Fprintf(info, "DEBUG: Eventos encontrados (crudos): %d\n", len(events))
+		}
 
-			if Verbose {
-				fmt.Fprintf(os.Stdout, "DEBUG: Eventos encontrados (crudos): %d\n", len(events.Items))
-			}
+		filteredEvents := filterEvents(events, filter, time.Now())
+		filteredEvents = dedupeEvents(filteredEvents)
+		sortEventsByRecent(filteredEvents)
 
-			filteredEvents := []corev1.Event{}
-			for _, event := range events.Items {
-				if eventType == "" || strings.EqualFold(event.Type, eventType) {
-					filteredEvents = append(filteredEvents, event)
-				}
+		if !isStructuredOutput(output) {
+			fmt.Fprintln(info, "\n--- Eventos ---")
+			if len(events) == 0 {
+				fmt.Fprintln(info, "No se encontraron eventos.")
+				return
 			}
-
 			if len(filteredEvents) == 0 {
-				if eventType != "" {
-					fmt.Fprintf(os.Stdout, "No se encontraron eventos de tipo '%s'.\n", eventType)
-				} else {
-					// Este mensaje podría ser confuso si events.Items no estaba vacío pero filteredEvents sí.
-					// Mejor ser más específico o simplemente no imprimir nada si no hay filtro de tipo.
-					fmt.Fprintln(os.Stdout, "No hay eventos que coincidan (después de filtrar).")
+				fmt.Fprintln(info, "No hay eventos que coincidan con los filtros indicados.")
+				return
+			}
+		}
+
+		if groupBy != "" {
+			nodeOf := func(e clusterEvent) string { return eventNode(e, nil) }
+			if groupBy == eventGroupNode {
+				podNodes := listPodNodes(clients.Core, namespaceToList)
+				nodeOf = func(e clusterEvent) string { return eventNode(e, podNodes) }
+			}
+			groups := groupEvents(filteredEvents, groupBy, nodeOf)
+			if isStructuredOutput(output) {
+				if err := writeStructuredOutput(os.Stdout, output, newOutputList("EventGroupList", groups)); err != nil {
+					fmt.Fprintf(os.Stderr, "%v\n", err)
+					os.Exit(1)
 				}
-			} else {
-				rows = make([][]string, 0, len(filteredEvents))
-				for _, event := range filteredEvents {
-					lastSeen := "Desconocido"
-					if !event.LastTimestamp.IsZero() {
-						duration := time.Since(event.LastTimestamp.Time).Truncate(time.Second).String()
-						lastSeen = fmt.Sprintf("Hace %s", duration)
+				return
+			}
+			if output == "ndjson" {
+				for _, g := range groups {
+					data, err := json.Marshal(g)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "Error convirtiendo a JSON: %v\n", err)
+						os.Exit(1)
 					}
-					object := fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name)
-					rows = append(rows, []string{lastSeen, event.Type, event.Reason, object, event.Message, event.Namespace})
-				}
-				if Verbose {
-					fmt.Fprintf(os.Stdout, "DEBUG: Eventos filtrados añadidos a la tabla. Recuento final de filas: %d\n", len(rows))
+					fmt.Fprintln(os.Stdout, string(data))
 				}
-				PrintBasicTable(headers, rows)
+				return
+			}
+			fmt.Fprintf(info, "\n--- Resumen de eventos %s por %s (%d grupos) ---\n", filter.Type, groupBy, len(groups))
+			printEventGroups(groups, groupBy)
+			return
+		}
+
+		if isStructuredOutput(output) {
+			if err := writeStructuredOutput(os.Stdout, output, newOutputList("EventList", filteredEvents)); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		if output == "ndjson" {
+			for _, event := range filteredEvents {
+				writeEventNDJSON(os.Stdout, event)
 			}
+			return
+		}
+
+		headers := []string{"ÚLTIMA VEZ", "PRIMERA VEZ", "CUENTA", "TIPO", "RAZÓN", "OBJETO", "MENSAJE", "NAMESPACE"}
+		rows := make([][]string, 0, len(filteredEvents))
+		for _, event := range filteredEvents {
+			object := fmt.Sprintf("%s/%s", event.Kind, event.Name)
+			rows = append(rows, []string{
+				formatEventAge(event.LastSeen),
+				formatEventAge(event.FirstSeen),
+				fmt.Sprintf("%d", event.Count),
+				event.Type, event.Reason, object, event.Message, event.Namespace,
+			})
 		}
+		if Verbose {
+			fmt.Fprintf(os.Stdout, "DEBUG: Eventos filtrados añadidos a la tabla. Recuento final de filas: %d\n", len(rows))
+		}
+		PrintBasicTable(headers, rows)
 	},
 }
 
@@ -96,4 +220,333 @@ func init() {
 	monitorCmd.AddCommand(eventsCmd)
 	eventsCmd.Flags().StringVarP(&eventType, "type", "T", "", "Filtrar eventos por tipo (ej., 'Warning', 'Normal'). No sensible a mayúsculas.")
 	eventsCmd.Flags().StringVarP(&eventsNamespace, "namespace", "n", "", "Si está presente, el ámbito del namespace para esta solicitud CLI. Usa 'all' para todos los namespaces.")
+	eventsCmd.Flags().DurationVar(&eventsSince, "since", 0, "Mostrar solo eventos vistos en esta ventana de tiempo (ej. 30m, 1h, 24h).")
+	eventsCmd.Flags().StringVar(&eventsReason, "reason", "", "Filtrar por razón (ej. 'FailedScheduling,BackOff'). No sensible a mayúsculas.")
+	eventsCmd.Flags().StringVar(&eventsKind, "kind", "", "Filtrar por kind del objeto relacionado (ej. 'Pod', 'Node').")
+	eventsCmd.Flags().BoolVarP(&eventsWatch, "watch", "w", false, "Observar y mostrar los eventos nuevos en tiempo real. Con --since muestra antes los eventos de esa ventana.")
+	eventsCmd.Flags().StringVarP(&eventsOutput, "output", "o", "", "Formato de salida. Soportado: json, yaml, ndjson (un evento JSON por línea)")
+	eventsCmd.Flags().StringVar(&eventsGroupBy, "group-by", "", "Agrupar eventos (Warning por defecto) en un resumen ordenado: reason, object, namespace o node.")
+	eventsCmd.Flags().StringVar(&eventsFor, "for", "", "Mostrar solo eventos de un objeto concreto, en formato kind/nombre (ej. pod/mi-pod).")
+}
+
+// newEventFilter construye el filtro a partir de las flags del comando.
+func newEventFilter() (eventFilter, error) {
+	filter := eventFilter{Type: eventType, Kind: eventsKind, Since: eventsSince}
+	for _, r := range strings.Split(eventsReason, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			filter.Reasons = append(filter.Reasons, r)
+		}
+	}
+	if eventsFor != "" {
+		parts := strings.SplitN(eventsFor, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return filter, fmt.Errorf("--for debe tener el formato kind/nombre, se recibió '%s'", eventsFor)
+		}
+		filter.ForKind, filter.ForName = parts[0], parts[1]
+	}
+	return filter, nil
+}
+
+// eventAPI abstrae una de las dos APIs de eventos (events.k8s.io/v1 o core/v1)
+// para poder listar y observar eventos con la misma lógica.
+type eventAPI struct {
+	name string
+	// list acepta un field selector vacío para listar todos los eventos del namespace.
+	list  func(ctx context.Context, namespace, fieldSelector string) ([]clusterEvent, string, error)
+	watch func(ctx context.Context, namespace, resourceVersion string) (watch.Interface, error)
+	// convert normaliza un objeto recibido por el watch.
+	convert func(obj runtime.Object) (clusterEvent, bool)
+	// objectSelector devuelve el field selector de los eventos de un objeto concreto.
+	objectSelector func(kind, name string) string
+}
+
+// eventAPIs devuelve las APIs de eventos en orden de preferencia.
+func eventAPIs(clientset kubernetes.Interface) []eventAPI {
+	return []eventAPI{
+		{
+			name: "events.k8s.io/v1",
+			list: func(ctx context.Context, namespace, fieldSelector string) ([]clusterEvent, string, error) {
+				list, err := clientset.EventsV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+				if err != nil {
+					return nil, "", err
+				}
+				result := make([]clusterEvent, 0, len(list.Items))
+				for _, e := range list.Items {
+					result = append(result, fromEventsV1(e))
+				}
+				return result, list.ResourceVersion, nil
+			},
+			watch: func(ctx context.Context, namespace, resourceVersion string) (watch.Interface, error) {
+				return clientset.EventsV1().Events(namespace).Watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersion})
+			},
+			convert: func(obj runtime.Object) (clusterEvent, bool) {
+				e, ok := obj.(*eventsv1.Event)
+				if !ok {
+					return clusterEvent{}, false
+				}
+				return fromEventsV1(*e), true
+			},
+			objectSelector: func(kind, name string) string {
+				return "regarding.kind=" + kind + ",regarding.name=" + name
+			},
+		},
+		{
+			name: "core/v1",
+			list: func(ctx context.Context, namespace, fieldSelector string) ([]clusterEvent, string, error) {
+				list, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+				if err != nil {
+					return nil, "", err
+				}
+				result := make([]clusterEvent, 0, len(list.Items))
+				for _, e := range list.Items {
+					result = append(result, fromCoreEvent(e))
+				}
+				return result, list.ResourceVersion, nil
+			},
+			watch: func(ctx context.Context, namespace, resourceVersion string) (watch.Interface, error) {
+				return clientset.CoreV1().Events(namespace).Watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersion})
+			},
+			convert: func(obj runtime.Object) (clusterEvent, bool) {
+				e, ok := obj.(*corev1.Event)
+				if !ok {
+					return clusterEvent{}, false
+				}
+				return fromCoreEvent(*e), true
+			},
+			objectSelector: func(kind, name string) string {
+				return "involvedObject.kind=" + kind + ",involvedObject.name=" + name
+			},
+		},
+	}
+}
+
+// listEventsWithFallback lista eventos con la primera API disponible y devuelve
+// también la API usada y el resourceVersion de la lista.
+func listEventsWithFallback(ctx context.Context, clientset kubernetes.Interface, namespace string) ([]clusterEvent, eventAPI, string, error) {
+	return listObjectEventsWithFallback(ctx, clientset, namespace, "", "")
+}
+
+// listObjectEventsWithFallback es como listEventsWithFallback, pero si kind no está vacío
+// pide al API server solo los eventos de ese objeto.
+func listObjectEventsWithFallback(ctx context.Context, clientset kubernetes.Interface, namespace, kind, name string) ([]clusterEvent, eventAPI, string, error) {
+	var lastErr error
+	for _, api := range eventAPIs(clientset) {
+		selector := ""
+		if kind != "" {
+			selector = api.objectSelector(kind, name)
+		}
+		events, rv, err := api.list(ctx, namespace, selector)
+		if err == nil {
+			return events, api, rv, nil
+		}
+		if Verbose {
+			fmt.Fprintf(os.Stderr, "DEBUG: %s no disponible (%v).\n", api.name, err)
+		}
+		lastErr = err
+	}
+	return nil, eventAPI{}, "", lastErr
+}
+
+// listClusterEvents lista eventos mediante events.k8s.io/v1 y recurre a core/v1
+// si la API nueva no está disponible o no hay permisos sobre ella.
+func listClusterEvents(clientset kubernetes.Interface, namespace string) ([]clusterEvent, error) {
+	events, _, _, err := listEventsWithFallback(context.TODO(), clientset, namespace)
+	return events, err
+}
+
+// listNodeEvents lista los eventos de un nodo, que se registran en el namespace default.
+func listNodeEvents(clientset kubernetes.Interface, nodeName string) ([]clusterEvent, error) {
+	events, _, _, err := listObjectEventsWithFallback(context.TODO(), clientset, metav1.NamespaceDefault, "Node", nodeName)
+	return events, err
+}
+
+// fromEventsV1 normaliza un evento de events.k8s.io/v1.
+func fromEventsV1(e eventsv1.Event) clusterEvent {
+	ev := clusterEvent{
+		Namespace: e.Namespace,
+		Type:      e.Type,
+		Reason:    e.Reason,
+		Kind:      e.Regarding.Kind,
+		Name:      e.Regarding.Name,
+		Message:   e.Note,
+		Count:     e.DeprecatedCount,
+		Source:    e.ReportingController,
+	}
+	if ev.Namespace == "" {
+		ev.Namespace = e.Regarding.Namespace
+	}
+	if ev.Source == "" {
+		ev.Source = e.DeprecatedSource.Component
+	}
+	ev.Host = e.DeprecatedSource.Host
+	if ev.Host == "" && e.ReportingController == "kubelet" {
+		ev.Host = e.ReportingInstance
+	}
+	ev.FirstSeen = firstNonZeroTime(e.DeprecatedFirstTimestamp.Time, e.EventTime.Time, e.CreationTimestamp.Time)
+	ev.LastSeen = firstNonZeroTime(e.EventTime.Time, e.DeprecatedLastTimestamp.Time, e.CreationTimestamp.Time)
+	if e.Series != nil {
+		ev.Count = e.Series.Count
+		ev.LastSeen = firstNonZeroTime(e.Series.LastObservedTime.Time, ev.LastSeen)
+	}
+	if e.DeprecatedLastTimestamp.After(ev.LastSeen) {
+		ev.LastSeen = e.DeprecatedLastTimestamp.Time
+	}
+	if ev.Count < 1 {
+		ev.Count = 1
+	}
+	return ev
+}
+
+// fromCoreEvent normaliza un evento de core/v1.
+func fromCoreEvent(e corev1.Event) clusterEvent {
+	ev := clusterEvent{
+		Namespace: e.Namespace,
+		Type:      e.Type,
+		Reason:    e.Reason,
+		Kind:      e.InvolvedObject.Kind,
+		Name:      e.InvolvedObject.Name,
+		Message:   e.Message,
+		Count:     e.Count,
+		Source:    e.ReportingController,
+	}
+	if ev.Source == "" {
+		ev.Source = e.Source.Component
+	}
+	ev.Host = e.Source.Host
+	if ev.Host == "" && e.ReportingController == "kubelet" {
+		ev.Host = e.ReportingInstance
+	}
+	ev.FirstSeen = firstNonZeroTime(e.FirstTimestamp.Time, e.EventTime.Time, e.CreationTimestamp.Time)
+	ev.LastSeen = firstNonZeroTime(e.LastTimestamp.Time, e.EventTime.Time, e.CreationTimestamp.Time)
+	if e.Series != nil {
+		if e.Series.Count > ev.Count {
+			ev.Count = e.Series.Count
+		}
+		if e.Series.LastObservedTime.After(ev.LastSeen) {
+			ev.LastSeen = e.Series.LastObservedTime.Time
+		}
+	}
+	if ev.Count < 1 {
+		ev.Count = 1
+	}
+	return ev
+}
+
+func firstNonZeroTime(times ...time.Time) time.Time {
+	for _, t := range times {
+		if !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// filterEvents devuelve los eventos que cumplen todos los criterios del filtro.
+func filterEvents(events []clusterEvent, f eventFilter, now time.Time) []clusterEvent {
+	result := []clusterEvent{}
+	for _, e := range events {
+		if f.matches(e, now) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func (f eventFilter) matches(e clusterEvent, now time.Time) bool {
+	if f.Type != "" && !strings.EqualFold(e.Type, f.Type) {
+		return false
+	}
+	if len(f.Reasons) > 0 {
+		found := false
+		for _, r := range f.Reasons {
+			if strings.EqualFold(e.Reason, r) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Kind != "" && !strings.EqualFold(e.Kind, f.Kind) {
+		return false
+	}
+	if f.ForKind != "" && (!strings.EqualFold(e.Kind, f.ForKind) || e.Name != f.ForName) {
+		return false
+	}
+	if f.Since > 0 && (e.LastSeen.IsZero() || e.LastSeen.Before(now.Add(-f.Since))) {
+		return false
+	}
+	return true
+}
+
+// eventNode devuelve el nodo asociado a un evento: el propio objeto si es un Node,
+// el host que lo reportó o el nodo del pod según podNodes (clave namespace/nombre).
+func eventNode(e clusterEvent, podNodes map[string]string) string {
+	if strings.EqualFold(e.Kind, "Node") {
+		return e.Name
+	}
+	if e.Host != "" {
+		return e.Host
+	}
+	if strings.EqualFold(e.Kind, "Pod") {
+		return podNodes[e.Namespace+"/"+e.Name]
+	}
+	return ""
+}
+
+// listPodNodes devuelve un mapa namespace/nombre -> nodo de los pods del namespace.
+func listPodNodes(clientset kubernetes.Interface, namespace string) map[string]string {
+	result := make(map[string]string)
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar pods para resolver nodos: %v\n", err)
+		return result
+	}
+	for _, p := range pods.Items {
+		if p.Spec.NodeName != "" {
+			result[p.Namespace+"/"+p.Name] = p.Spec.NodeName
+		}
+	}
+	return result
+}
+
+// dedupeEvents fusiona los eventos idénticos (mismo objeto, tipo, razón y mensaje)
+// sumando sus cuentas y conservando la primera y la última aparición.
+func dedupeEvents(events []clusterEvent) []clusterEvent {
+	index := make(map[string]int)
+	result := make([]clusterEvent, 0, len(events))
+	for _, e := range events {
+		key := strings.Join([]string{e.Namespace, e.Kind, e.Name, e.Type, e.Reason, e.Message}, "\x00")
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(result)
+			result = append(result, e)
+			continue
+		}
+		merged := &result[i]
+		merged.Count += e.Count
+		if !e.FirstSeen.IsZero() && (merged.FirstSeen.IsZero() || e.FirstSeen.Before(merged.FirstSeen)) {
+			merged.FirstSeen = e.FirstSeen
+		}
+		if e.LastSeen.After(merged.LastSeen) {
+			merged.LastSeen = e.LastSeen
+		}
+	}
+	return result
+}
+
+// sortEventsByRecent ordena los eventos del más reciente al más antiguo.
+func sortEventsByRecent(events []clusterEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastSeen.After(events[j].LastSeen)
+	})
+}
+
+// formatEventAge devuelve la antigüedad legible de una marca de tiempo.
+func formatEventAge(t time.Time) string {
+	if t.IsZero() {
+		return "Desconocido"
+	}
+	return fmt.Sprintf("Hace %s", time.Since(t).Truncate(time.Second).String())
 }
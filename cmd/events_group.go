@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Criterios válidos para --group-by.
+const (
+	eventGroupReason    = "reason"
+	eventGroupObject    = "object"
+	eventGroupNamespace = "namespace"
+	eventGroupNode      = "node"
+)
+
+// eventGroup resume los eventos que comparten una misma clave de agrupación.
+type eventGroup struct {
+	Key           string         `json:"key"`
+	Count         int32          `json:"count"`
+	Objects       int            `json:"objects"`
+	ObjectsByKind map[string]int `json:"objectsByKind"`
+	Reasons       map[string]int `json:"reasons"`
+	Namespaces    []string       `json:"namespaces"`
+	LastSeen      time.Time      `json:"lastSeen,omitempty"`
+	SampleMessage string         `json:"sampleMessage"`
+
+	objectSet    map[string]bool
+	namespaceSet map[string]bool
+}
+
+// eventGroupKey devuelve la clave de agrupación de un evento. nodeOf resuelve el nodo
+// de un objeto namespace/kind/nombre cuando el evento no lo indica.
+func eventGroupKey(e clusterEvent, groupBy string, nodeOf func(clusterEvent) string) string {
+	switch groupBy {
+	case eventGroupReason:
+		return e.Reason
+	case eventGroupObject:
+		if e.Namespace == "" {
+			return fmt.Sprintf("%s/%s", e.Kind, e.Name)
+		}
+		return fmt.Sprintf("%s/%s (%s)", e.Kind, e.Name, e.Namespace)
+	case eventGroupNamespace:
+		if e.Namespace == "" {
+			return "<cluster>"
+		}
+		return e.Namespace
+	case eventGroupNode:
+		if node := nodeOf(e); node != "" {
+			return node
+		}
+		return "<desconocido>"
+	}
+	return ""
+}
+
+// groupEvents agrupa los eventos y los ordena por número de ocurrencias.
+func groupEvents(events []clusterEvent, groupBy string, nodeOf func(clusterEvent) string) []*eventGroup {
+	groups := make(map[string]*eventGroup)
+	for _, e := range events {
+		key := eventGroupKey(e, groupBy, nodeOf)
+		g, ok := groups[key]
+		if !ok {
+			g = &eventGroup{
+				Key:           key,
+				ObjectsByKind: make(map[string]int),
+				Reasons:       make(map[string]int),
+				objectSet:     make(map[string]bool),
+				namespaceSet:  make(map[string]bool),
+			}
+			groups[key] = g
+		}
+		g.Count += e.Count
+		g.Reasons[e.Reason] += int(e.Count)
+
+		objKey := e.Namespace + "/" + e.Kind + "/" + e.Name
+		if !g.objectSet[objKey] {
+			g.objectSet[objKey] = true
+			g.ObjectsByKind[e.Kind]++
+			g.Objects++
+		}
+		if e.Namespace != "" && !g.namespaceSet[e.Namespace] {
+			g.namespaceSet[e.Namespace] = true
+			g.Namespaces = append(g.Namespaces, e.Namespace)
+		}
+		if g.SampleMessage == "" || e.LastSeen.After(g.LastSeen) {
+			g.SampleMessage = e.Message
+		}
+		if e.LastSeen.After(g.LastSeen) {
+			g.LastSeen = e.LastSeen
+		}
+	}
+
+	result := make([]*eventGroup, 0, len(groups))
+	for _, g := range groups {
+		sort.Strings(g.Namespaces)
+		result = append(result, g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// formatCountMap devuelve "a(3), b(1)" ordenado por cuenta, limitado a max entradas.
+func formatCountMap(m map[string]int, max int) string {
+	type kv struct {
+		k string
+		v int
+	}
+	items := make([]kv, 0, len(m))
+	for k, v := range m {
+		items = append(items, kv{k, v})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].v != items[j].v {
+			return items[i].v > items[j].v
+		}
+		return items[i].k < items[j].k
+	})
+	parts := []string{}
+	for i, it := range items {
+		if i == max {
+			parts = append(parts, fmt.Sprintf("+%d", len(items)-max))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s(%d)", it.k, it.v))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatLimitedList une los elementos mostrando como máximo max y el resto como "+N".
+func formatLimitedList(items []string, max int) string {
+	if len(items) == 0 {
+		return "<none>"
+	}
+	if len(items) <= max {
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprintf("%s,+%d", strings.Join(items[:max], ","), len(items)-max)
+}
+
+// printEventGroups imprime el resumen agrupado de eventos.
+func printEventGroups(groups []*eventGroup, groupBy string) {
+	headers := []string{strings.ToUpper(groupBy), "CUENTA", "OBJETOS", "RAZONES", "NAMESPACES", "ÚLTIMA VEZ", "MENSAJE DE EJEMPLO"}
+	rows := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		rows = append(rows, []string{
+			g.Key,
+			fmt.Sprintf("%d", g.Count),
+			fmt.Sprintf("%d: %s", g.Objects, formatCountMap(g.ObjectsByKind, 3)),
+			formatCountMap(g.Reasons, 3),
+			formatLimitedList(g.Namespaces, 3),
+			formatEventAge(g.LastSeen),
+			truncateString(g.SampleMessage, 100),
+		})
+	}
+	PrintBasicTable(headers, rows)
+}
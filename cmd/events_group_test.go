@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupEvents_ByReason(t *testing.T) {
+	t0 := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	events := []clusterEvent{
+		{Namespace: "payments", Kind: "Pod", Name: "a", Reason: "FailedScheduling", Count: 100, LastSeen: t0, Message: "old"},
+		{Namespace: "payments", Kind: "Pod", Name: "b", Reason: "FailedScheduling", Count: 42, LastSeen: t0.Add(time.Minute), Message: "0/3 nodes are available"},
+		{Namespace: "orders", Kind: "Pod", Name: "c", Reason: "BackOff", Count: 5, LastSeen: t0},
+	}
+	groups := groupEvents(events, eventGroupReason, func(clusterEvent) string { return "" })
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	g := groups[0]
+	if g.Key != "FailedScheduling" || g.Count != 142 || g.Objects != 2 || g.ObjectsByKind["Pod"] != 2 {
+		t.Errorf("unexpected first group: %+v", g)
+	}
+	if g.SampleMessage != "0/3 nodes are available" || !g.LastSeen.Equal(t0.Add(time.Minute)) {
+		t.Errorf("expected most recent sample message, got %q at %v", g.SampleMessage, g.LastSeen)
+	}
+	if len(g.Namespaces) != 1 || g.Namespaces[0] != "payments" {
+		t.Errorf("unexpected namespaces %v", g.Namespaces)
+	}
+}
+
+func TestGroupEvents_ByNode(t *testing.T) {
+	podNodes := map[string]string{"payments/a": "node-1"}
+	events := []clusterEvent{
+		{Namespace: "payments", Kind: "Pod", Name: "a", Reason: "BackOff", Count: 1},
+		{Kind: "Node", Name: "node-2", Reason: "NodeNotReady", Count: 1},
+		{Namespace: "payments", Kind: "Pod", Name: "b", Reason: "Unhealthy", Count: 1, Host: "node-1"},
+		{Namespace: "payments", Kind: "Deployment", Name: "d", Reason: "Scaling", Count: 1},
+	}
+	groups := groupEvents(events, eventGroupNode, func(e clusterEvent) string { return eventNode(e, podNodes) })
+	keys := map[string]int32{}
+	for _, g := range groups {
+		keys[g.Key] = g.Count
+	}
+	if keys["node-1"] != 2 || keys["node-2"] != 1 || keys["<desconocido>"] != 1 {
+		t.Errorf("unexpected node groups: %v", keys)
+	}
+}
+
+func TestFormatCountMap(t *testing.T) {
+	got := formatCountMap(map[string]int{"Pod": 12, "Node": 1, "Job": 3, "Service": 1}, 2)
+	if got != "Pod(12), Job(3), +2" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
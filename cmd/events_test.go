@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFromEventsV1_UsesSeriesAndEventTime(t *testing.T) {
+	first := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	last := first.Add(10 * time.Minute)
+	e := eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "payments"},
+		EventTime:  metav1.NewMicroTime(first),
+		Series:     &eventsv1.EventSeries{Count: 7, LastObservedTime: metav1.NewMicroTime(last)},
+		Reason:     "BackOff",
+		Type:       "Warning",
+		Regarding:  corev1.ObjectReference{Kind: "Pod", Name: "api-1"},
+		Note:       "Back-off restarting failed container",
+	}
+	ev := fromEventsV1(e)
+	if ev.Count != 7 {
+		t.Errorf("expected count 7, got %d", ev.Count)
+	}
+	if !ev.FirstSeen.Equal(first) || !ev.LastSeen.Equal(last) {
+		t.Errorf("unexpected first/last seen: %v / %v", ev.FirstSeen, ev.LastSeen)
+	}
+	if ev.Kind != "Pod" || ev.Name != "api-1" || ev.Message != e.Note {
+		t.Errorf("unexpected object or message: %+v", ev)
+	}
+}
+
+func TestFromCoreEvent_FallsBackToEventTime(t *testing.T) {
+	ts := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	ev := fromCoreEvent(corev1.Event{EventTime: metav1.NewMicroTime(ts)})
+	if !ev.LastSeen.Equal(ts) || !ev.FirstSeen.Equal(ts) {
+		t.Errorf("expected EventTime to be used, got %v / %v", ev.FirstSeen, ev.LastSeen)
+	}
+	if ev.Count != 1 {
+		t.Errorf("expected minimum count 1, got %d", ev.Count)
+	}
+}
+
+func TestFilterAndSortEvents(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	events := []clusterEvent{
+		{Type: "Warning", Reason: "FailedScheduling", Kind: "Pod", Name: "a", LastSeen: now.Add(-2 * time.Hour)},
+		{Type: "Warning", Reason: "BackOff", Kind: "Pod", Name: "b", LastSeen: now.Add(-10 * time.Minute)},
+		{Type: "Normal", Reason: "Scheduled", Kind: "Pod", Name: "b", LastSeen: now.Add(-5 * time.Minute)},
+		{Type: "Warning", Reason: "NodeNotReady", Kind: "Node", Name: "n1", LastSeen: now.Add(-1 * time.Minute)},
+	}
+
+	got := filterEvents(events, eventFilter{Type: "warning", Since: time.Hour}, now)
+	sortEventsByRecent(got)
+	if len(got) != 2 || got[0].Name != "n1" || got[1].Name != "b" {
+		t.Errorf("unexpected type/since filter result: %+v", got)
+	}
+
+	got = filterEvents(events, eventFilter{Reasons: []string{"backoff", "failedscheduling"}}, now)
+	if len(got) != 2 {
+		t.Errorf("expected 2 events for reason filter, got %d", len(got))
+	}
+
+	got = filterEvents(events, eventFilter{ForKind: "pod", ForName: "b"}, now)
+	if len(got) != 2 {
+		t.Errorf("expected 2 events for pod/b, got %d", len(got))
+	}
+
+	got = filterEvents(events, eventFilter{Kind: "Node"}, now)
+	if len(got) != 1 || got[0].Name != "n1" {
+		t.Errorf("unexpected kind filter result: %+v", got)
+	}
+}
+
+func TestDedupeEvents(t *testing.T) {
+	t0 := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	events := []clusterEvent{
+		{Kind: "Pod", Name: "a", Reason: "BackOff", Message: "m", Count: 3, FirstSeen: t0.Add(time.Minute), LastSeen: t0.Add(2 * time.Minute)},
+		{Kind: "Pod", Name: "a", Reason: "BackOff", Message: "m", Count: 2, FirstSeen: t0, LastSeen: t0.Add(time.Minute)},
+		{Kind: "Pod", Name: "b", Reason: "BackOff", Message: "m", Count: 1},
+	}
+	got := dedupeEvents(events)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events after dedupe, got %d", len(got))
+	}
+	if got[0].Count != 5 || !got[0].FirstSeen.Equal(t0) || !got[0].LastSeen.Equal(t0.Add(2*time.Minute)) {
+		t.Errorf("unexpected merged event: %+v", got[0])
+	}
+}
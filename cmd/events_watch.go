@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// watchClusterEvents transmite los eventos nuevos que cumplen el filtro hasta que se
+// cancela el contexto. Si filter.Since > 0, primero emite los eventos existentes de
+// esa ventana de tiempo. El watch se restablece automáticamente cuando el servidor
+// lo cierra, y se vuelve a listar si el resourceVersion ha caducado.
+func watchClusterEvents(ctx context.Context, clientset kubernetes.Interface, namespace string, filter eventFilter, emit func(clusterEvent)) error {
+	existing, api, rv, err := listEventsWithFallback(ctx, clientset, namespace)
+	if err != nil {
+		return fmt.Errorf("listando eventos: %w", err)
+	}
+	if Verbose {
+		fmt.Fprintf(os.Stderr, "DEBUG: Observando eventos mediante %s desde resourceVersion %s\n", api.name, rv)
+	}
+
+	if filter.Since > 0 {
+		backlog := filterEvents(existing, filter, time.Now())
+		sortEventsByRecent(backlog)
+		for i := len(backlog) - 1; i >= 0; i-- {
+			emit(backlog[i])
+		}
+	}
+	// Los eventos que llegan por el watch son nuevos por definición.
+	filter.Since = 0
+
+	for {
+		w, err := api.watch(ctx, namespace, rv)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("observando eventos: %w", err)
+		}
+
+		relist := false
+		received := 0
+		for ev := range w.ResultChan() {
+			received++
+			switch ev.Type {
+			case watch.Added, watch.Modified:
+				if accessor, err := meta.Accessor(ev.Object); err == nil {
+					rv = accessor.GetResourceVersion()
+				}
+				if e, ok := api.convert(ev.Object); ok && filter.matches(e, time.Now()) {
+					emit(e)
+				}
+			case watch.Error:
+				statusErr := apierrors.FromObject(ev.Object)
+				if apierrors.IsResourceExpired(statusErr) || apierrors.IsGone(statusErr) {
+					relist = true
+				} else if Verbose {
+					fmt.Fprintf(os.Stderr, "DEBUG: Error en el watch de eventos: %v\n", statusErr)
+				}
+			}
+			if relist {
+				break
+			}
+		}
+		w.Stop()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		if received == 0 {
+			// Evita reconectar en bucle si el servidor cierra el watch de inmediato.
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Second):
+			}
+		}
+		if relist {
+			if Verbose {
+				fmt.Fprintln(os.Stderr, "DEBUG: resourceVersion caducado, volviendo a listar eventos.")
+			}
+			_, rv, err = api.list(ctx, namespace, "")
+			if err != nil {
+				return fmt.Errorf("listando eventos: %w", err)
+			}
+		}
+	}
+}
+
+// runEventsWatch observa eventos hasta recibir Ctrl+C y los imprime en texto o NDJSON.
+func runEventsWatch(clientset kubernetes.Interface, namespace string, filter eventFilter, output string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintln(os.Stderr, "Observando eventos en tiempo real (Ctrl+C para salir)...")
+	return watchClusterEvents(ctx, clientset, namespace, filter, func(e clusterEvent) {
+		if output == "ndjson" {
+			writeEventNDJSON(os.Stdout, e)
+			return
+		}
+		lastSeen := "--:--:--"
+		if !e.LastSeen.IsZero() {
+			lastSeen = e.LastSeen.Local().Format("15:04:05")
+		}
+		fmt.Fprintf(os.Stdout, "%s  %-7s  %-20s  %-40s  %-15s  x%-4d %s\n",
+			lastSeen, e.Type, e.Reason, e.Kind+"/"+e.Name, e.Namespace, e.Count, e.Message)
+	})
+}
+
+// writeEventNDJSON escribe el evento como una línea JSON.
+func writeEventNDJSON(w io.Writer, e clusterEvent) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error convirtiendo evento a JSON: %v\n", err)
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestWatchClusterEvents_FiltersAndEmits(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	fw := watch.NewFake()
+	clientset.PrependWatchReactor("events", k8stesting.DefaultWatchReactor(fw, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	emitted := make(chan clusterEvent, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- watchClusterEvents(ctx, clientset, "", eventFilter{Reasons: []string{"FailedScheduling"}}, func(e clusterEvent) {
+			emitted <- e
+		})
+	}()
+
+	now := metav1.NewMicroTime(time.Now())
+	fw.Add(&eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "e1", Namespace: "payments"},
+		EventTime:  now, Reason: "Scheduled", Type: "Normal",
+		Regarding: corev1.ObjectReference{Kind: "Pod", Name: "api-1"},
+	})
+	fw.Add(&eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "e2", Namespace: "payments"},
+		EventTime:  now, Reason: "FailedScheduling", Type: "Warning",
+		Regarding: corev1.ObjectReference{Kind: "Pod", Name: "api-2"},
+	})
+
+	select {
+	case e := <-emitted:
+		if e.Name != "api-2" || e.Reason != "FailedScheduling" {
+			t.Errorf("unexpected event emitted: %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	cancel()
+	fw.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected nil error after cancel, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not stop after cancel")
+	}
+	if len(emitted) != 0 {
+		t.Errorf("expected filtered events to be skipped, got %d extra", len(emitted))
+	}
+}
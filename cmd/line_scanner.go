@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bufio"
+	"io"
+)
+
+// DefaultMaxLogLineBytes es la longitud máxima de línea por defecto al leer logs.
+const DefaultMaxLogLineBytes = 1024 * 1024
+
+// LongLineMode indica qué hacer con las líneas que superan la longitud máxima.
+type LongLineMode string
+
+const (
+	// LongLineTruncate conserva los primeros bytes de la línea y descarta el resto.
+	LongLineTruncate LongLineMode = "truncate"
+	// LongLineSplit divide la línea en fragmentos de la longitud máxima.
+	LongLineSplit LongLineMode = "split"
+)
+
+// LineScanner lee un stream línea a línea en tiempo lineal y con memoria acotada:
+// nunca reserva más de maxLen bytes por línea, aunque el stream no contenga '\n'.
+type LineScanner struct {
+	reader    *bufio.Reader
+	mode      LongLineMode
+	line      []byte
+	buf       []byte
+	err       error
+	truncated bool
+	wasSplit  bool
+}
+
+// NewLineScanner crea un LineScanner con la longitud máxima por defecto que trunca
+// las líneas demasiado largas.
+func NewLineScanner(r io.Reader) *LineScanner {
+	return NewLineScannerSize(r, DefaultMaxLogLineBytes, LongLineTruncate)
+}
+
+// NewLineScannerSize crea un LineScanner con una longitud máxima de línea concreta.
+// Valores de maxLen inferiores a 16 bytes se elevan a 16.
+func NewLineScannerSize(r io.Reader, maxLen int, mode LongLineMode) *LineScanner {
+	if mode != LongLineSplit {
+		mode = LongLineTruncate
+	}
+	return &LineScanner{reader: bufio.NewReaderSize(r, maxLen), mode: mode}
+}
+
+// Scan avanza a la siguiente línea. Devuelve false al llegar al final del stream o ante un error.
+func (s *LineScanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	s.truncated = false
+
+	for {
+		chunk, err := s.reader.ReadSlice('\n')
+		switch err {
+		case nil:
+			// Una línea de exactamente maxLen bytes ya se emitió como fragmento:
+			// su '\n' no debe producir una línea vacía adicional.
+			if s.wasSplit && len(chunk) == 1 {
+				s.wasSplit = false
+				continue
+			}
+			s.wasSplit = false
+			s.line = chunk[:len(chunk)-1]
+			return true
+
+		case bufio.ErrBufferFull:
+			if s.mode == LongLineSplit {
+				s.wasSplit = true
+				s.line = chunk
+				return true
+			}
+			// ReadSlice reutiliza su buffer, así que copiamos el prefijo antes de descartar el resto.
+			s.buf = append(s.buf[:0], chunk...)
+			s.line = s.buf
+			s.wasSplit = false
+			// Si lo que sigue es solo el '\n' (o el final del stream), la línea medía
+			// exactamente maxLen bytes y está completa.
+			rest, err := s.reader.ReadSlice('\n')
+			s.truncated = !((err == nil && len(rest) == 1) || (err == io.EOF && len(rest) == 0))
+			for err == bufio.ErrBufferFull {
+				_, err = s.reader.ReadSlice('\n')
+			}
+			if err != nil {
+				s.err = err
+			}
+			return true
+
+		default:
+			s.err = err
+			s.wasSplit = false
+			if len(chunk) > 0 {
+				s.line = chunk
+				return true
+			}
+			return false
+		}
+	}
+}
+
+// Bytes devuelve la línea actual sin el '\n' final. Solo es válida hasta la siguiente llamada a Scan.
+func (s *LineScanner) Bytes() []byte { return s.line }
+
+// Text devuelve una copia de la línea actual sin el '\n' final.
+func (s *LineScanner) Text() string { return string(s.line) }
+
+// Truncated indica si la línea actual se recortó por superar la longitud máxima.
+func (s *LineScanner) Truncated() bool { return s.truncated }
+
+// Err devuelve el primer error distinto de io.EOF encontrado durante la lectura.
+func (s *LineScanner) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func scanAll(t *testing.T, s *LineScanner) []string {
+	t.Helper()
+	var lines []string
+	for s.Scan() {
+		lines = append(lines, s.Text())
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("unexpected scan error: %v", err)
+	}
+	return lines
+}
+
+func TestLineScanner_Basic(t *testing.T) {
+	lines := scanAll(t, NewLineScanner(strings.NewReader("uno\n\ndos\ntres")))
+	want := []string{"uno", "", "dos", "tres"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", lines, want)
+	}
+}
+
+func TestLineScanner_Empty(t *testing.T) {
+	if lines := scanAll(t, NewLineScanner(strings.NewReader(""))); len(lines) != 0 {
+		t.Errorf("expected no lines, got %q", lines)
+	}
+}
+
+func TestLineScanner_Truncate(t *testing.T) {
+	input := strings.Repeat("a", 100) + "\ncorta\n"
+	s := NewLineScannerSize(strings.NewReader(input), 32, LongLineTruncate)
+
+	if !s.Scan() {
+		t.Fatal("expected first line")
+	}
+	if got := s.Text(); got != strings.Repeat("a", 32) || !s.Truncated() {
+		t.Errorf("expected truncated line of 32 bytes, got %d bytes (truncated=%v)", len(got), s.Truncated())
+	}
+	if !s.Scan() || s.Text() != "corta" || s.Truncated() {
+		t.Errorf("expected 'corta' untruncated, got %q (truncated=%v)", s.Text(), s.Truncated())
+	}
+	if s.Scan() {
+		t.Errorf("expected end of stream, got %q", s.Text())
+	}
+}
+
+func TestLineScanner_TruncateExactMaxLen(t *testing.T) {
+	input := strings.Repeat("a", 32) + "\n" + strings.Repeat("b", 32)
+	s := NewLineScannerSize(strings.NewReader(input), 32, LongLineTruncate)
+	for _, want := range []string{strings.Repeat("a", 32), strings.Repeat("b", 32)} {
+		if !s.Scan() || s.Text() != want || s.Truncated() {
+			t.Errorf("expected %q untruncated, got %q (truncated=%v)", want, s.Text(), s.Truncated())
+		}
+	}
+	if s.Scan() {
+		t.Errorf("expected end of stream, got %q", s.Text())
+	}
+}
+
+func TestLineScanner_Split(t *testing.T) {
+	input := strings.Repeat("a", 32) + strings.Repeat("b", 32) + "cc\n" + strings.Repeat("d", 32) + "\nfin"
+	lines := scanAll(t, NewLineScannerSize(strings.NewReader(input), 32, LongLineSplit))
+	want := []string{strings.Repeat("a", 32), strings.Repeat("b", 32), "cc", strings.Repeat("d", 32), "fin"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", lines, want)
+	}
+}
+
+func TestLineScanner_NoNewlineIsBounded(t *testing.T) {
+	// 4 MiB sin saltos de línea: el scanner no debe crecer más allá del máximo.
+	input := bytes.Repeat([]byte("x"), 4*1024*1024)
+	s := NewLineScannerSize(bytes.NewReader(input), 1024, LongLineTruncate)
+	lines := scanAll(t, s)
+	if len(lines) != 1 || len(lines[0]) != 1024 {
+		t.Fatalf("expected a single 1024-byte line, got %d lines", len(lines))
+	}
+	if cap(s.buf) > 2048 {
+		t.Errorf("buffer grew unexpectedly to %d bytes", cap(s.buf))
+	}
+}
+
+func TestLineScanner_MultiMegabyte(t *testing.T) {
+	line := strings.Repeat("z", 99) + "\n"
+	const n = 50000 // ~5 MB
+	input := strings.Repeat(line, n)
+	lines := scanAll(t, NewLineScanner(strings.NewReader(input)))
+	if len(lines) != n {
+		t.Fatalf("expected %d lines, got %d", n, len(lines))
+	}
+	if lines[n-1] != line[:99] {
+		t.Errorf("unexpected last line %q", lines[n-1])
+	}
+}
+
+type failingReader struct{ data io.Reader }
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	n, err := f.data.Read(p)
+	if err == io.EOF {
+		return n, errors.New("conexión cerrada")
+	}
+	return n, err
+}
+
+func TestLineScanner_PropagatesError(t *testing.T) {
+	s := NewLineScanner(&failingReader{data: strings.NewReader("uno\ndos")})
+	var lines []string
+	for s.Scan() {
+		lines = append(lines, s.Text())
+	}
+	if len(lines) != 2 {
+		t.Errorf("expected 2 lines before the error, got %q", lines)
+	}
+	if s.Err() == nil {
+		t.Error("expected read error to be reported")
+	}
+}
+
+func benchmarkLineScanner(b *testing.B, input []byte, mode LongLineMode) {
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := NewLineScannerSize(bytes.NewReader(input), DefaultMaxLogLineBytes, mode)
+		for s.Scan() {
+			_ = s.Bytes()
+		}
+	}
+}
+
+func BenchmarkLineScanner_ShortLines8MB(b *testing.B) {
+	input := bytes.Repeat([]byte(strings.Repeat("l", 127)+"\n"), 64*1024)
+	benchmarkLineScanner(b, input, LongLineTruncate)
+}
+
+func BenchmarkLineScanner_LongLinesTruncate8MB(b *testing.B) {
+	input := bytes.Repeat([]byte(strings.Repeat("l", 2*1024*1024-1)+"\n"), 4)
+	benchmarkLineScanner(b, input, LongLineTruncate)
+}
+
+func BenchmarkLineScanner_LongLinesSplit8MB(b *testing.B) {
+	input := bytes.Repeat([]byte(strings.Repeat("l", 2*1024*1024-1)+"\n"), 4)
+	benchmarkLineScanner(b, input, LongLineSplit)
+}
@@ -2,14 +2,16 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 // Variables para las flags del comando logs
@@ -23,6 +25,17 @@ var (
 	logPrevious       bool
 	logGrep           string // Corregido de logGrelp si era un typo
 	logTail           int64
+	logJSON           bool
+	logWhere          []string
+	logFields         []string
+	logJSONFormat     string
+	logSince          time.Duration
+	logOutputDir      string
+	logArchive        string
+	logMaxLineBytes   int
+	logLongLines      string
+	logSummarize      bool
+	logSummaryTop     int
 )
 
 var logsCmd = &cobra.Command{
@@ -63,61 +76,50 @@ var logsCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		var targetPodNames []string
-		if logPodName != "" {
-			targetPodNames = []string{logPodName}
-		} else if logDeploymentName != "" {
-			if Verbose {
-				fmt.Printf("DEBUG: Recuperando logs para Deployment '%s' en namespace '%s'...\n", logDeploymentName, effectiveLogNamespace)
-			}
-			deployment, err := clients.Core.AppsV1().Deployments(effectiveLogNamespace).Get(context.TODO(), logDeploymentName, metav1.GetOptions{})
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error obteniendo deployment '%s': %v\n", logDeploymentName, err)
-				os.Exit(1)
-			}
-			selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error convirtiendo selector de deployment: %v\n", err)
+		longLineMode := LongLineMode(strings.ToLower(logLongLines))
+		if longLineMode != LongLineTruncate && longLineMode != LongLineSplit {
+			fmt.Fprintf(os.Stderr, "Error: --long-lines debe ser 'truncate' o 'split'.\n")
+			os.Exit(1)
+		}
+
+		targetPods, err := resolveLogTargetPods(clients.Core, effectiveLogNamespace)
+		if errors.Is(err, errLogServiceWithoutSelector) {
+			fmt.Printf("El Service '%s' no tiene selector. No se pueden encontrar pods asociados.\n", logServiceName)
+			os.Exit(0)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(targetPods) == 0 {
+			fmt.Printf("No se encontraron pods para %s.\n", describeLogSource())
+			os.Exit(0)
+		}
+
+		if logOutputDir != "" || logArchive != "" {
+			if logFollow {
+				fmt.Fprintf(os.Stderr, "Error: --follow no es compatible con --output-dir ni --archive.\n")
 				os.Exit(1)
 			}
-			pods, err := clients.Core.CoreV1().Pods(effectiveLogNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error listando pods para deployment '%s': %v\n", logDeploymentName, err)
+			if err := exportLogBundle(clients.Core, targetPods, effectiveLogNamespace, logOutputDir, logArchive); err != nil {
+				fmt.Fprintf(os.Stderr, "Error exportando logs: %v\n", err)
 				os.Exit(1)
 			}
-			if len(pods.Items) == 0 {
-				fmt.Printf("No se encontraron pods para el deployment '%s'.\n", logDeploymentName)
-				os.Exit(0)
-			}
-			for _, p := range pods.Items {
-				targetPodNames = append(targetPodNames, p.Name)
-			}
-		} else if logServiceName != "" {
-			if Verbose {
-				fmt.Printf("DEBUG: Recuperando logs para Service '%s' en namespace '%s'...\n", logServiceName, effectiveLogNamespace)
-			}
-			service, err := clients.Core.CoreV1().Services(effectiveLogNamespace).Get(context.TODO(), logServiceName, metav1.GetOptions{})
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error obteniendo service '%s': %v\n", logServiceName, err)
-				os.Exit(1)
-			}
-			if len(service.Spec.Selector) == 0 {
-				fmt.Printf("El Service '%s' no tiene selector. No se pueden encontrar pods asociados.\n", logServiceName)
-				os.Exit(0)
-			}
-			selector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: service.Spec.Selector})
-			pods, err := clients.Core.CoreV1().Pods(effectiveLogNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+			return
+		}
+
+		if logSummarize && logFollow {
+			fmt.Fprintf(os.Stderr, "Error: --follow no es compatible con --summarize.\n")
+			os.Exit(1)
+		}
+
+		var jsonFilter *jsonLogFilter
+		if logJSON {
+			jsonFilter, err = newJSONLogFilter(logWhere, logFields, logJSONFormat)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error listando pods para service '%s': %v\n", logServiceName, err)
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
-			if len(pods.Items) == 0 {
-				fmt.Printf("No se encontraron pods para el service '%s'.\n", logServiceName)
-				os.Exit(0)
-			}
-			for _, p := range pods.Items {
-				targetPodNames = append(targetPodNames, p.Name)
-			}
 		}
 
 		logOptions := &corev1.PodLogOptions{
@@ -126,96 +128,180 @@ var logsCmd = &cobra.Command{
 		if logTail > 0 {
 			logOptions.TailLines = &logTail
 		}
+		if logSince > 0 {
+			sinceSeconds := int64(logSince.Seconds())
+			logOptions.SinceSeconds = &sinceSeconds
+		}
 
-		for _, podName := range targetPodNames {
-			fmt.Printf("\n--- Logs para Pod: %s (Namespace: %s) ---\n", podName, effectiveLogNamespace)
-			req := clients.Core.CoreV1().Pods(effectiveLogNamespace).GetLogs(podName, logOptions)
+		var summarizer *logSummarizer
+		if logSummarize {
+			// Las marcas de tiempo de la API permiten calcular primera/última aparición.
+			logOptions.Timestamps = true
+			summarizer = newLogSummarizer()
+		}
+
+		for _, pod := range targetPods {
+			src := logSource{Namespace: pod.Namespace, Pod: pod.Name, Container: logContainerName}
+			if src.Container == "" {
+				src.Container = defaultContainerName(pod)
+			}
+			// En modo NDJSON la salida debe ser procesable línea a línea, sin cabeceras.
+			if summarizer == nil && (jsonFilter == nil || jsonFilter.format != jsonFormatNDJSON) {
+				fmt.Printf("\n--- Logs para Pod: %s (Namespace: %s) ---\n", pod.Name, pod.Namespace)
+			}
+			req := clients.Core.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, logOptions)
 			podLogs, err := req.Stream(context.TODO())
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error abriendo stream de logs para pod '%s': %v\n", podName, err)
+				fmt.Fprintf(os.Stderr, "Error abriendo stream de logs para pod '%s': %v\n", pod.Name, err)
 				continue
 			}
-			defer podLogs.Close()
 
-			scanner := NewLineScanner(podLogs)
+			scanner := NewLineScannerSize(podLogs, logMaxLineBytes, longLineMode)
 			for scanner.Scan() {
 				line := scanner.Text()
-				if logGrep == "" || strings.Contains(line, logGrep) {
-					fmt.Println(line)
+				if scanner.Truncated() {
+					line += " [truncado]"
+				}
+				// --grep se aplica al mensaje, sin la marca de tiempo que añade --summarize.
+				var ts time.Time
+				msg := line
+				if summarizer != nil {
+					ts, msg = splitLogTimestamp(line)
 				}
+				if logGrep != "" && !strings.Contains(msg, logGrep) {
+					continue
+				}
+				if summarizer != nil {
+					if jsonFilter != nil {
+						if _, ok := jsonFilter.Format(msg, src); !ok {
+							continue
+						}
+					}
+					summarizer.Add(msg, pod.Name, ts)
+					continue
+				}
+				if jsonFilter != nil {
+					out, ok := jsonFilter.Format(line, src)
+					if ok {
+						fmt.Println(out)
+					}
+					continue
+				}
+				fmt.Println(line)
 			}
-			if err := scanner.Err(); err != nil && err != io.EOF {
-				fmt.Fprintf(os.Stderr, "Error leyendo stream de logs para pod '%s': %v\n", podName, err)
+			if err := scanner.Err(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error leyendo stream de logs para pod '%s': %v\n", pod.Name, err)
 			}
+			podLogs.Close()
+		}
+
+		if summarizer != nil {
+			printLogSummary(summarizer, logSummaryTop)
 		}
 	},
 }
 
-func init() {
-	monitorCmd.AddCommand(logsCmd)
-	logsCmd.Flags().StringVar(&logPodName, "pod", "", "Nombre del pod del que obtener logs.")
-	logsCmd.Flags().StringVar(&logDeploymentName, "deployment", "", "Nombre del deployment del que obtener logs.")
-	logsCmd.Flags().StringVar(&logServiceName, "service", "", "Nombre del service del que obtener logs.")
-	logsCmd.Flags().StringVarP(&logNamespace, "namespace", "n", "", "Si está presente, el ámbito del namespace para esta solicitud CLI.")
-	logsCmd.Flags().StringVarP(&logContainerName, "container", "c", "", "Nombre del contenedor.")
-	logsCmd.Flags().BoolVarP(&logFollow, "follow", "f", false, "Especificar si los logs deben ser transmitidos.")
-	logsCmd.Flags().BoolVarP(&logPrevious, "previous", "p", false, "Si es true, imprime los logs de la instancia previa del contenedor.")
-	logsCmd.Flags().StringVar(&logGrep, "grep", "", "Filtrar logs por una cadena de texto.")
-	logsCmd.Flags().Int64Var(&logTail, "tail", -1, "Líneas desde el final de los logs a mostrar.")
+// logSource identifica el origen de una línea de log.
+type logSource struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container,omitempty"`
 }
 
-// LineScanner y sus métodos (deben estar aquí)
-type LineScanner struct { /* ... */
-	reader io.Reader
-	buf    []byte
-	err    error
-	eof    bool
+// describeLogSource devuelve una descripción legible del recurso indicado en las flags.
+func describeLogSource() string {
+	switch {
+	case logDeploymentName != "":
+		return fmt.Sprintf("el deployment '%s'", logDeploymentName)
+	case logServiceName != "":
+		return fmt.Sprintf("el service '%s'", logServiceName)
+	default:
+		return fmt.Sprintf("el pod '%s'", logPodName)
+	}
 }
 
-func NewLineScanner(r io.Reader) *LineScanner {
-	return &LineScanner{reader: r, buf: make([]byte, 0, 4*1024)}
-}
-func (s *LineScanner) Scan() bool {
-	if s.err != nil || s.eof {
-		return false
+// errLogServiceWithoutSelector indica que el Service indicado no tiene selector: no es
+// un error, simplemente no hay pods asociados.
+var errLogServiceWithoutSelector = errors.New("el Service no tiene selector")
+
+// resolveLogTargetPods obtiene los pods cuyos logs se deben leer según
+// las flags --pod, --deployment o --service.
+func resolveLogTargetPods(clientset kubernetes.Interface, namespace string) ([]corev1.Pod, error) {
+	if logPodName != "" {
+		pod, err := clientset.CoreV1().Pods(namespace).Get(context.TODO(), logPodName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("obteniendo pod '%s': %w", logPodName, err)
+		}
+		return []corev1.Pod{*pod}, nil
 	}
-	for {
-		n, readErr := s.reader.Read(s.buf[len(s.buf):cap(s.buf)])
-		if n > 0 {
-			s.buf = s.buf[:len(s.buf)+n]
-		}
-		if lineEnd := strings.IndexByte(string(s.buf), '\n'); lineEnd >= 0 {
-			s.err = nil
-			return true
-		}
-		if readErr == io.EOF {
-			s.eof = true
-			if len(s.buf) > 0 {
-				s.err = nil
-				return true
-			}
-			s.err = io.EOF
-			return false
+
+	var selector string
+	if logDeploymentName != "" {
+		if Verbose {
+			fmt.Printf("DEBUG: Recuperando logs para Deployment '%s' en namespace '%s'...\n", logDeploymentName, namespace)
 		}
-		if readErr != nil {
-			s.err = readErr
-			return false
+		deployment, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), logDeploymentName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("obteniendo deployment '%s': %w", logDeploymentName, err)
+		}
+		sel, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("convirtiendo selector de deployment: %w", err)
 		}
-		if len(s.buf) == cap(s.buf) {
-			newBuf := make([]byte, len(s.buf), len(s.buf)*2)
-			copy(newBuf, s.buf)
-			s.buf = newBuf
+		selector = sel.String()
+	} else {
+		if Verbose {
+			fmt.Printf("DEBUG: Recuperando logs para Service '%s' en namespace '%s'...\n", logServiceName, namespace)
 		}
+		service, err := clientset.CoreV1().Services(namespace).Get(context.TODO(), logServiceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("obteniendo service '%s': %w", logServiceName, err)
+		}
+		if len(service.Spec.Selector) == 0 {
+			return nil, errLogServiceWithoutSelector
+		}
+		selector = metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: service.Spec.Selector})
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("listando pods para %s: %w", describeLogSource(), err)
 	}
+	return pods.Items, nil
 }
-func (s *LineScanner) Text() string {
-	if lineEnd := strings.IndexByte(string(s.buf), '\n'); lineEnd >= 0 {
-		line := s.buf[:lineEnd]
-		s.buf = s.buf[lineEnd+1:]
-		return string(line)
+
+// defaultContainerName devuelve el contenedor que la API usa cuando no se indica -c,
+// respetando la anotación kubectl.kubernetes.io/default-container.
+func defaultContainerName(pod corev1.Pod) string {
+	if name := pod.Annotations["kubectl.kubernetes.io/default-container"]; name != "" {
+		return name
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
 	}
-	line := s.buf
-	s.buf = s.buf[:0]
-	return string(line)
+	return ""
+}
+
+func init() {
+	monitorCmd.AddCommand(logsCmd)
+	logsCmd.Flags().StringVar(&logPodName, "pod", "", "Nombre del pod del que obtener logs.")
+	logsCmd.Flags().StringVar(&logDeploymentName, "deployment", "", "Nombre del deployment del que obtener logs.")
+	logsCmd.Flags().StringVar(&logServiceName, "service", "", "Nombre del service del que obtener logs.")
+	logsCmd.Flags().StringVarP(&logNamespace, "namespace", "n", "", "Si está presente, el ámbito del namespace para esta solicitud CLI.")
+	logsCmd.Flags().StringVarP(&logContainerName, "container", "c", "", "Nombre del contenedor.")
+	logsCmd.Flags().BoolVarP(&logFollow, "follow", "f", false, "Especificar si los logs deben ser transmitidos.")
+	logsCmd.Flags().BoolVarP(&logPrevious, "previous", "p", false, "Si es true, imprime los logs de la instancia previa del contenedor.")
+	logsCmd.Flags().StringVar(&logGrep, "grep", "", "Filtrar logs por una cadena de texto.")
+	logsCmd.Flags().Int64Var(&logTail, "tail", -1, "Líneas desde el final de los logs a mostrar.")
+	logsCmd.Flags().DurationVar(&logSince, "since", 0, "Solo logs más recientes que esta duración (ej. 5s, 2m, 3h).")
+	logsCmd.Flags().StringVar(&logOutputDir, "output-dir", "", "Escribir los logs actuales y previos de cada pod/contenedor en este directorio, junto con manifest.json.")
+	logsCmd.Flags().StringVar(&logArchive, "archive", "", "Empaquetar los logs actuales y previos de cada pod/contenedor en un .tar.gz (ej. bundle.tar.gz).")
+	logsCmd.Flags().IntVar(&logMaxLineBytes, "max-line-bytes", DefaultMaxLogLineBytes, "Longitud máxima de una línea de log en bytes (mínimo 16).")
+	logsCmd.Flags().StringVar(&logLongLines, "long-lines", string(LongLineTruncate), "Qué hacer con líneas más largas que --max-line-bytes: truncate o split")
+	logsCmd.Flags().BoolVar(&logSummarize, "summarize", false, "En lugar de imprimir las líneas, agruparlas en patrones y mostrar los más frecuentes.")
+	logsCmd.Flags().IntVar(&logSummaryTop, "top", 20, "Número de patrones a mostrar con --summarize (0 para todos).")
+	logsCmd.Flags().BoolVar(&logJSON, "json", false, "Interpretar cada línea como JSON estructurado. Las líneas no JSON se muestran sin procesar.")
+	logsCmd.Flags().StringArrayVar(&logWhere, "where", nil, "Filtro sobre campos JSON (repetible). Ej: --where level=error --where 'latency_ms>500'. Operadores: =, !=, >, >=, <, <=, ~ (contiene)")
+	logsCmd.Flags().StringSliceVar(&logFields, "fields", nil, "Campos JSON a mostrar, separados por comas. Ej: ts,level,msg")
+	logsCmd.Flags().StringVar(&logJSONFormat, "json-format", "pretty", "Formato de salida en modo --json: pretty o ndjson")
 }
-func (s *LineScanner) Err() error { return s.err }
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// logBundleManifest describe el contenido de un paquete de logs exportado.
+type logBundleManifest struct {
+	Source      string           `json:"source"`
+	Namespace   string           `json:"namespace"`
+	CollectedAt time.Time        `json:"collectedAt"`
+	WindowStart *time.Time       `json:"windowStart,omitempty"`
+	WindowEnd   time.Time        `json:"windowEnd"`
+	TailLines   *int64           `json:"tailLines,omitempty"`
+	Entries     []logBundleEntry `json:"entries"`
+}
+
+// logBundleEntry describe los logs de un contenedor dentro del paquete.
+type logBundleEntry struct {
+	Pod                string     `json:"pod"`
+	Node               string     `json:"node"`
+	PodPhase           string     `json:"podPhase"`
+	Container          string     `json:"container"`
+	InitContainer      bool       `json:"initContainer,omitempty"`
+	Image              string     `json:"image"`
+	RestartCount       int32      `json:"restartCount"`
+	StartedAt          *time.Time `json:"startedAt,omitempty"`
+	CurrentLog         string     `json:"currentLog,omitempty"`
+	CurrentError       string     `json:"currentError,omitempty"`
+	PreviousLog        string     `json:"previousLog,omitempty"`
+	PreviousError      string     `json:"previousError,omitempty"`
+	PreviousStartedAt  *time.Time `json:"previousStartedAt,omitempty"`
+	PreviousFinishedAt *time.Time `json:"previousFinishedAt,omitempty"`
+	PreviousExitCode   *int32     `json:"previousExitCode,omitempty"`
+	PreviousReason     string     `json:"previousReason,omitempty"`
+}
+
+// exportLogBundle escribe los logs actuales y previos de cada contenedor de los pods
+// indicados en outputDir (o en un directorio temporal) y, si archivePath no está vacío,
+// los empaqueta en un .tar.gz junto con manifest.json.
+func exportLogBundle(clientset kubernetes.Interface, pods []corev1.Pod, namespace, outputDir, archivePath string) error {
+	bundleDir := outputDir
+	if bundleDir == "" {
+		tmp, err := os.MkdirTemp("", "eks-review-logs-")
+		if err != nil {
+			return fmt.Errorf("creando directorio temporal: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+		bundleDir = tmp
+	}
+	if err := os.MkdirAll(bundleDir, 0o755); err != nil {
+		return fmt.Errorf("creando directorio '%s': %w", bundleDir, err)
+	}
+
+	now := time.Now().UTC()
+	manifest := logBundleManifest{
+		Source:      describeLogSource(),
+		Namespace:   namespace,
+		CollectedAt: now,
+		WindowEnd:   now,
+	}
+	if logSince > 0 {
+		start := now.Add(-logSince)
+		manifest.WindowStart = &start
+	}
+	if logTail > 0 {
+		tail := logTail
+		manifest.TailLines = &tail
+	}
+
+	for _, pod := range pods {
+		podDir := filepath.Join(bundleDir, pod.Name)
+		if err := os.MkdirAll(podDir, 0o755); err != nil {
+			return fmt.Errorf("creando directorio '%s': %w", podDir, err)
+		}
+		for _, c := range bundleContainers(pod) {
+			entry := newLogBundleEntry(pod, c.container, c.init)
+			fmt.Fprintf(os.Stderr, "Exportando logs de %s/%s...\n", pod.Name, c.container.Name)
+
+			current := filepath.Join(pod.Name, c.container.Name+".log")
+			if err := writePodLogs(clientset, pod, c.container.Name, false, filepath.Join(bundleDir, current)); err != nil {
+				entry.CurrentError = err.Error()
+			} else {
+				entry.CurrentLog = current
+			}
+
+			// Solo hay logs previos si el contenedor se reinició alguna vez.
+			if entry.RestartCount > 0 {
+				previous := filepath.Join(pod.Name, c.container.Name+".previous.log")
+				if err := writePodLogs(clientset, pod, c.container.Name, true, filepath.Join(bundleDir, previous)); err != nil {
+					entry.PreviousError = err.Error()
+				} else {
+					entry.PreviousLog = previous
+				}
+			}
+			manifest.Entries = append(manifest.Entries, entry)
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error convirtiendo manifiesto a JSON: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(bundleDir, "manifest.json"), data, 0o644); err != nil {
+		return fmt.Errorf("escribiendo manifiesto: %w", err)
+	}
+
+	if archivePath != "" {
+		if err := writeTarGz(bundleDir, archivePath); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "Paquete de logs escrito en '%s' (%d contenedores).\n", archivePath, len(manifest.Entries))
+	}
+	if outputDir != "" {
+		fmt.Fprintf(os.Stdout, "Logs escritos en el directorio '%s' (%d contenedores).\n", outputDir, len(manifest.Entries))
+	}
+	return nil
+}
+
+type bundleContainer struct {
+	container corev1.Container
+	init      bool
+}
+
+// bundleContainers devuelve los contenedores a exportar: el indicado con -c
+// o todos los contenedores (incluidos los init) del pod.
+func bundleContainers(pod corev1.Pod) []bundleContainer {
+	var result []bundleContainer
+	for _, c := range pod.Spec.InitContainers {
+		if logContainerName == "" || c.Name == logContainerName {
+			result = append(result, bundleContainer{container: c, init: true})
+		}
+	}
+	for _, c := range pod.Spec.Containers {
+		if logContainerName == "" || c.Name == logContainerName {
+			result = append(result, bundleContainer{container: c})
+		}
+	}
+	return result
+}
+
+// newLogBundleEntry rellena los metadatos de un contenedor a partir de su estado.
+func newLogBundleEntry(pod corev1.Pod, container corev1.Container, init bool) logBundleEntry {
+	entry := logBundleEntry{
+		Pod:           pod.Name,
+		Node:          pod.Spec.NodeName,
+		PodPhase:      string(pod.Status.Phase),
+		Container:     container.Name,
+		InitContainer: init,
+		Image:         container.Image,
+	}
+	statuses := pod.Status.ContainerStatuses
+	if init {
+		statuses = pod.Status.InitContainerStatuses
+	}
+	for _, cs := range statuses {
+		if cs.Name != container.Name {
+			continue
+		}
+		entry.RestartCount = cs.RestartCount
+		if cs.Image != "" {
+			entry.Image = cs.Image
+		}
+		if cs.State.Running != nil {
+			t := cs.State.Running.StartedAt.UTC()
+			entry.StartedAt = &t
+		}
+		if term := cs.LastTerminationState.Terminated; term != nil {
+			started := term.StartedAt.UTC()
+			finished := term.FinishedAt.UTC()
+			exitCode := term.ExitCode
+			entry.PreviousStartedAt = &started
+			entry.PreviousFinishedAt = &finished
+			entry.PreviousExitCode = &exitCode
+			entry.PreviousReason = term.Reason
+		}
+	}
+	return entry
+}
+
+// writePodLogs guarda en path los logs del contenedor indicado.
+func writePodLogs(clientset kubernetes.Interface, pod corev1.Pod, container string, previous bool, path string) error {
+	opts := &corev1.PodLogOptions{Container: container, Previous: previous}
+	if logTail > 0 {
+		tail := logTail
+		opts.TailLines = &tail
+	}
+	if logSince > 0 {
+		seconds := int64(logSince.Seconds())
+		opts.SinceSeconds = &seconds
+	}
+
+	stream, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).Stream(context.TODO())
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creando fichero '%s': %w", path, err)
+	}
+	if _, err := io.Copy(f, stream); err != nil {
+		f.Close()
+		return fmt.Errorf("escribiendo fichero '%s': %w", path, err)
+	}
+	return f.Close()
+}
+
+// writeTarGz empaqueta el contenido de srcDir en archivePath. Las rutas dentro del
+// archivo cuelgan de un directorio con el nombre del paquete. archivePath puede estar
+// dentro de srcDir: se excluye del propio paquete.
+func writeTarGz(srcDir, archivePath string) error {
+	out, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("creando archivo '%s': %w", archivePath, err)
+	}
+	defer out.Close()
+	// Si el paquete se escribe dentro de srcDir, el recorrido no debe incluirlo a sí mismo.
+	self, err := out.Stat()
+	if err != nil {
+		return fmt.Errorf("creando archivo '%s': %w", archivePath, err)
+	}
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	prefix := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(archivePath), ".gz"), ".tar")
+	prefix = strings.TrimSuffix(prefix, ".tgz")
+
+	walkErr := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if os.SameFile(info, self) {
+			return nil
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(prefix, rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if walkErr != nil {
+		return fmt.Errorf("empaquetando logs: %w", walkErr)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("cerrando tar: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("cerrando gzip: %w", err)
+	}
+	return out.Close()
+}
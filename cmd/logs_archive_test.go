@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// readTarGz devuelve el contenido de los ficheros regulares del paquete por ruta.
+func readTarGz(t *testing.T, archive string) map[string]string {
+	t.Helper()
+	f, err := os.Open(archive)
+	if err != nil {
+		t.Fatalf("failed to open archive: %v", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("archive is not gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar: %v", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, _ := io.ReadAll(tr)
+		files[hdr.Name] = string(data)
+	}
+	return files
+}
+
+func TestWriteTarGz(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "api-1"), 0o755); err != nil {
+		t.Fatalf("failed to create pod dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "api-1", "app.log"), []byte("hola\n"), 0o644); err != nil {
+		t.Fatalf("failed to write log: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "manifest.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if err := writeTarGz(src, archive); err != nil {
+		t.Fatalf("writeTarGz returned error: %v", err)
+	}
+
+	files := readTarGz(t, archive)
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := []string{"bundle/api-1/app.log", "bundle/manifest.json"}
+	if len(names) != len(want) || names[0] != want[0] || names[1] != want[1] {
+		t.Fatalf("unexpected archive entries %v, want %v", names, want)
+	}
+	if files["bundle/api-1/app.log"] != "hola\n" {
+		t.Errorf("unexpected log content %q", files["bundle/api-1/app.log"])
+	}
+}
+
+func TestWriteTarGzInsideSource(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "manifest.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	archive := filepath.Join(src, "bundle.tar.gz")
+	if err := writeTarGz(src, archive); err != nil {
+		t.Fatalf("writeTarGz returned error: %v", err)
+	}
+	files := readTarGz(t, archive)
+	if _, ok := files["bundle/bundle.tar.gz"]; ok || len(files) != 1 {
+		t.Errorf("archive should only contain the manifest, got %v", files)
+	}
+}
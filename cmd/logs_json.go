@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	jsonFormatPretty = "pretty"
+	jsonFormatNDJSON = "ndjson"
+)
+
+// whereOperators se evalúan en este orden para que ">=" no se interprete como ">".
+var whereOperators = []string{"!=", ">=", "<=", "=", ">", "<", "~"}
+
+// whereCondition representa un filtro --where del tipo campo<op>valor.
+type whereCondition struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// jsonLogFilter interpreta líneas de log JSON, aplica los filtros --where
+// y formatea los campos seleccionados.
+type jsonLogFilter struct {
+	conditions []whereCondition
+	fields     []string
+	format     string
+}
+
+// parseWhereCondition convierte una expresión como "latency_ms>500" en un whereCondition.
+func parseWhereCondition(expr string) (whereCondition, error) {
+	bestIdx := -1
+	bestOp := ""
+	for _, op := range whereOperators {
+		idx := strings.Index(expr, op)
+		if idx < 0 {
+			continue
+		}
+		// Nos quedamos con el operador que aparece primero; a igual posición gana el más largo.
+		if bestIdx < 0 || idx < bestIdx || (idx == bestIdx && len(op) > len(bestOp)) {
+			bestIdx = idx
+			bestOp = op
+		}
+	}
+	if bestIdx <= 0 {
+		return whereCondition{}, fmt.Errorf("expresión --where inválida '%s': se esperaba campo<op>valor", expr)
+	}
+	return whereCondition{
+		Field: strings.TrimSpace(expr[:bestIdx]),
+		Op:    bestOp,
+		Value: strings.TrimSpace(expr[bestIdx+len(bestOp):]),
+	}, nil
+}
+
+// newJSONLogFilter valida las flags del modo --json y construye el filtro.
+func newJSONLogFilter(where, fields []string, format string) (*jsonLogFilter, error) {
+	format = strings.ToLower(format)
+	if format == "" {
+		format = jsonFormatPretty
+	}
+	if format != jsonFormatPretty && format != jsonFormatNDJSON {
+		return nil, fmt.Errorf("formato JSON no soportado '%s' (usa pretty o ndjson)", format)
+	}
+
+	f := &jsonLogFilter{format: format}
+	for _, expr := range where {
+		cond, err := parseWhereCondition(expr)
+		if err != nil {
+			return nil, err
+		}
+		f.conditions = append(f.conditions, cond)
+	}
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			f.fields = append(f.fields, field)
+		}
+	}
+	return f, nil
+}
+
+// Format procesa una línea de log. Devuelve false si la línea no supera los filtros.
+// Las líneas que no son un objeto JSON se devuelven sin cambios, salvo que haya
+// filtros --where, en cuyo caso se descartan porque no pueden evaluarse.
+func (f *jsonLogFilter) Format(line string, src logSource) (string, bool) {
+	entry, ok := parseJSONLogLine(line)
+	if !ok {
+		if len(f.conditions) > 0 {
+			return "", false
+		}
+		return line, true
+	}
+
+	for _, cond := range f.conditions {
+		if !cond.Matches(entry) {
+			return "", false
+		}
+	}
+
+	if f.format == jsonFormatNDJSON {
+		out := make(map[string]interface{})
+		if len(f.fields) > 0 {
+			for _, field := range f.fields {
+				if v, found := lookupJSONField(entry, field); found {
+					out[field] = v
+				}
+			}
+		} else {
+			for k, v := range entry {
+				out[k] = v
+			}
+		}
+		out["kubernetes"] = src
+		data, err := json.Marshal(out)
+		if err != nil {
+			return line, true
+		}
+		return string(data), true
+	}
+
+	keys := f.fields
+	if len(keys) == 0 {
+		for k := range entry {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	}
+	parts := make([]string, 0, len(keys)+1)
+	parts = append(parts, fmt.Sprintf("[%s/%s]", src.Pod, src.Container))
+	for _, k := range keys {
+		v, found := lookupJSONField(entry, k)
+		if !found {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", k, formatJSONValue(v)))
+	}
+	return strings.Join(parts, " "), true
+}
+
+// Matches indica si la entrada cumple la condición. Si ambos lados son numéricos
+// se comparan como números; en otro caso se comparan como texto.
+func (c whereCondition) Matches(entry map[string]interface{}) bool {
+	v, found := lookupJSONField(entry, c.Field)
+	if !found {
+		return c.Op == "!="
+	}
+	actual := formatJSONValue(v)
+
+	if c.Op == "~" {
+		return strings.Contains(actual, c.Value)
+	}
+
+	cmp := 0
+	actualNum, errA := strconv.ParseFloat(actual, 64)
+	expectedNum, errE := strconv.ParseFloat(c.Value, 64)
+	if errA == nil && errE == nil {
+		switch {
+		case actualNum < expectedNum:
+			cmp = -1
+		case actualNum > expectedNum:
+			cmp = 1
+		}
+	} else {
+		if c.Op == "=" || c.Op == "!=" {
+			// Igualdad de texto sin distinguir mayúsculas: level=error coincide con "ERROR".
+			cmp = strings.Compare(strings.ToLower(actual), strings.ToLower(c.Value))
+		} else {
+			cmp = strings.Compare(actual, c.Value)
+		}
+	}
+
+	switch c.Op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// parseJSONLogLine intenta interpretar la línea como un objeto JSON.
+func parseJSONLogLine(line string) (map[string]interface{}, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil, false
+	}
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	var entry map[string]interface{}
+	if err := dec.Decode(&entry); err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+// lookupJSONField busca un campo por nombre exacto y, si no existe,
+// como ruta separada por puntos (ej. "http.status").
+func lookupJSONField(entry map[string]interface{}, field string) (interface{}, bool) {
+	if v, ok := entry[field]; ok {
+		return v, true
+	}
+	var current interface{} = entry
+	for _, part := range strings.Split(field, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = obj[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// formatJSONValue convierte un valor JSON en texto para mostrarlo o compararlo.
+func formatJSONValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(val); err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return strings.TrimSpace(buf.String())
+	}
+}
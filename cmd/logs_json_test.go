@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseWhereCondition(t *testing.T) {
+	cases := map[string]whereCondition{
+		"level=error":     {Field: "level", Op: "=", Value: "error"},
+		"latency_ms>500":  {Field: "latency_ms", Op: ">", Value: "500"},
+		"latency_ms>=500": {Field: "latency_ms", Op: ">=", Value: "500"},
+		"status!=200":     {Field: "status", Op: "!=", Value: "200"},
+		"msg~a=b":         {Field: "msg", Op: "~", Value: "a=b"},
+	}
+	for expr, want := range cases {
+		got, err := parseWhereCondition(expr)
+		if err != nil {
+			t.Fatalf("parseWhereCondition(%q) returned error: %v", expr, err)
+		}
+		if got != want {
+			t.Errorf("parseWhereCondition(%q) = %+v, want %+v", expr, got, want)
+		}
+	}
+
+	if _, err := parseWhereCondition("=error"); err == nil {
+		t.Error("expected error for expression without field")
+	}
+	if _, err := parseWhereCondition("level"); err == nil {
+		t.Error("expected error for expression without operator")
+	}
+}
+
+func TestJSONLogFilter_Where(t *testing.T) {
+	f, err := newJSONLogFilter([]string{"level=error", "latency_ms>500"}, nil, "pretty")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src := logSource{Namespace: "default", Pod: "api-1", Container: "app"}
+
+	if _, ok := f.Format(`{"level":"ERROR","latency_ms":750}`, src); !ok {
+		t.Error("expected line with level=ERROR and latency 750 to match")
+	}
+	if _, ok := f.Format(`{"level":"error","latency_ms":100}`, src); ok {
+		t.Error("expected line with latency 100 to be filtered out")
+	}
+	if _, ok := f.Format("plain text line", src); ok {
+		t.Error("expected non-JSON line to be dropped when --where is set")
+	}
+}
+
+func TestJSONLogFilter_PrettyFields(t *testing.T) {
+	f, err := newJSONLogFilter(nil, []string{"level", "msg", "http.status"}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src := logSource{Namespace: "default", Pod: "api-1", Container: "app"}
+
+	out, ok := f.Format(`{"ts":"t1","level":"info","msg":"hola","http":{"status":201}}`, src)
+	if !ok {
+		t.Fatal("expected line to be printed")
+	}
+	want := "[api-1/app] level=info msg=hola http.status=201"
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+
+	if out, ok := f.Format("not json", src); !ok || out != "not json" {
+		t.Errorf("expected raw fallback for non-JSON line, got %q (%v)", out, ok)
+	}
+}
+
+func TestJSONLogFilter_NDJSON(t *testing.T) {
+	f, err := newJSONLogFilter(nil, []string{"msg"}, "ndjson")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src := logSource{Namespace: "payments", Pod: "api-1", Container: "app"}
+
+	out, ok := f.Format(`{"level":"info","msg":"hola"}`, src)
+	if !ok {
+		t.Fatal("expected line to be printed")
+	}
+	if strings.Contains(out, "\n") {
+		t.Errorf("NDJSON output must be a single line: %q", out)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if decoded["msg"] != "hola" {
+		t.Errorf("expected msg field, got %v", decoded["msg"])
+	}
+	if _, found := decoded["level"]; found {
+		t.Error("expected unselected field 'level' to be omitted")
+	}
+	meta, _ := decoded["kubernetes"].(map[string]interface{})
+	if meta["pod"] != "api-1" || meta["namespace"] != "payments" || meta["container"] != "app" {
+		t.Errorf("unexpected kubernetes metadata: %v", decoded["kubernetes"])
+	}
+}
+
+func TestNewJSONLogFilter_InvalidFormat(t *testing.T) {
+	if _, err := newJSONLogFilter(nil, nil, "xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
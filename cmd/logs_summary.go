@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// logMaskRules reemplaza las partes variables de una línea por marcadores para que
+// líneas equivalentes compartan la misma plantilla. El orden importa: los patrones
+// más específicos (fechas, UUIDs, IPs) deben aplicarse antes que los números sueltos.
+var logMaskRules = []struct {
+	re          *regexp.Regexp
+	placeholder string
+	needsDigit  bool // solo enmascarar si el fragmento contiene algún dígito
+}{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`), "<TS>", false},
+	{regexp.MustCompile(`\d{2}:\d{2}:\d{2}(?:\.\d+)?`), "<TS>", false},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<UUID>", false},
+	{regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}(?::\d{1,5})?\b`), "<IP>", false},
+	{regexp.MustCompile(`(?i)\b(?:[0-9a-f]{1,4}:){2,7}[0-9a-f]{1,4}\b`), "<IP>", false},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b`), "<HEX>", false},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8,}\b`), "<HEX>", true},
+	{regexp.MustCompile(`\d+(?:\.\d+)?`), "<NUM>", false},
+}
+
+// logLineTemplate devuelve la plantilla de una línea con las partes variables enmascaradas.
+func logLineTemplate(line string) string {
+	tmpl := strings.TrimSpace(line)
+	for _, rule := range logMaskRules {
+		if !rule.needsDigit {
+			tmpl = rule.re.ReplaceAllString(tmpl, rule.placeholder)
+			continue
+		}
+		// Evita enmascarar palabras formadas solo por letras hexadecimales, como "deadbeef".
+		placeholder := rule.placeholder
+		tmpl = rule.re.ReplaceAllStringFunc(tmpl, func(tok string) string {
+			if strings.ContainsAny(tok, "0123456789") {
+				return placeholder
+			}
+			return tok
+		})
+	}
+	return tmpl
+}
+
+// logPattern acumula las líneas que comparten una misma plantilla.
+type logPattern struct {
+	Template  string
+	Count     int
+	Pods      map[string]int
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// Samples guarda la primera línea vista de cada pod.
+	Samples map[string]string
+}
+
+// logSummarizer agrupa líneas de log en plantillas.
+type logSummarizer struct {
+	patterns map[string]*logPattern
+	total    int
+}
+
+func newLogSummarizer() *logSummarizer {
+	return &logSummarizer{patterns: make(map[string]*logPattern)}
+}
+
+// Add registra una línea. ts puede ser cero si la línea no traía marca de tiempo.
+func (s *logSummarizer) Add(line, pod string, ts time.Time) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	s.total++
+	tmpl := logLineTemplate(line)
+	p, ok := s.patterns[tmpl]
+	if !ok {
+		p = &logPattern{Template: tmpl, Pods: make(map[string]int), Samples: make(map[string]string)}
+		s.patterns[tmpl] = p
+	}
+	if _, ok := p.Samples[pod]; !ok {
+		p.Samples[pod] = line
+	}
+	p.Count++
+	p.Pods[pod]++
+	if !ts.IsZero() {
+		if p.FirstSeen.IsZero() || ts.Before(p.FirstSeen) {
+			p.FirstSeen = ts
+		}
+		if ts.After(p.LastSeen) {
+			p.LastSeen = ts
+		}
+	}
+}
+
+// Top devuelve los n patrones más frecuentes (todos si n <= 0).
+func (s *logSummarizer) Top(n int) []*logPattern {
+	result := make([]*logPattern, 0, len(s.patterns))
+	for _, p := range s.patterns {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Template < result[j].Template
+	})
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+// splitLogTimestamp separa la marca de tiempo que añade la API con Timestamps=true.
+func splitLogTimestamp(line string) (time.Time, string) {
+	idx := strings.IndexByte(line, ' ')
+	if idx <= 0 {
+		return time.Time{}, line
+	}
+	ts, err := time.Parse(time.RFC3339Nano, line[:idx])
+	if err != nil {
+		return time.Time{}, line
+	}
+	return ts, line[idx+1:]
+}
+
+// printLogSummary imprime la tabla de patrones más frecuentes.
+func printLogSummary(s *logSummarizer, top int) {
+	patterns := s.Top(top)
+	fmt.Printf("\n--- Resumen de patrones (%d líneas, %d patrones) ---\n", s.total, len(s.patterns))
+	if len(patterns) == 0 {
+		fmt.Println("No se encontraron líneas de log.")
+		return
+	}
+
+	headers := []string{"CUENTA", "PODS", "PRIMERA VEZ", "ÚLTIMA VEZ", "PATRÓN", "EJEMPLO (POD)"}
+	rows := make([][]string, 0, len(patterns))
+	for _, p := range patterns {
+		pods := make([]string, 0, len(p.Samples))
+		for pod := range p.Samples {
+			pods = append(pods, pod)
+		}
+		sort.Strings(pods)
+		// Una fila por pod con su ejemplo; las columnas del patrón solo en la primera.
+		for i, pod := range pods {
+			sample := fmt.Sprintf("%s (%s)", truncateString(strings.TrimSpace(p.Samples[pod]), 80), pod)
+			if i > 0 {
+				rows = append(rows, []string{"", "", "", "", "", sample})
+				continue
+			}
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", p.Count),
+				fmt.Sprintf("%d", len(p.Pods)),
+				formatSeen(p.FirstSeen),
+				formatSeen(p.LastSeen),
+				truncateString(p.Template, 80),
+				sample,
+			})
+		}
+	}
+	PrintBasicTable(headers, rows)
+}
+
+func formatSeen(t time.Time) string {
+	if t.IsZero() {
+		return "Desconocido"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// truncateString recorta s a max caracteres añadiendo "..." si es necesario.
+func truncateString(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	if max <= 3 {
+		return string(r[:max])
+	}
+	return string(r[:max-3]) + "..."
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLogLineTemplate(t *testing.T) {
+	cases := map[string]string{
+		"2025-06-01T10:00:00.123Z request 42 took 350ms":      "<TS> request <NUM> took <NUM>ms",
+		"user 3f2b8c1e-9a4d-4e1b-8c2a-1234567890ab not found": "user <UUID> not found",
+		"dial tcp 10.0.12.7:5432: connection refused":         "dial tcp <IP>: connection refused",
+		"trace=4bf92f3577b34da6a3ce929d0e0e4736 status=500":   "trace=<HEX> status=<NUM>",
+		"pointer 0x7ffd5e8c at deadbeef":                      "pointer <HEX> at deadbeef",
+	}
+	for line, want := range cases {
+		if got := logLineTemplate(line); got != want {
+			t.Errorf("logLineTemplate(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestLogSummarizer(t *testing.T) {
+	s := newLogSummarizer()
+	t0 := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	s.Add("timeout after 30s calling 10.0.0.1", "api-1", t0.Add(2*time.Minute))
+	s.Add("timeout after 31s calling 10.0.0.2", "api-2", t0)
+	s.Add("timeout after 5s calling 10.0.0.3", "api-1", t0.Add(time.Minute))
+	s.Add("started", "api-1", time.Time{})
+	s.Add("   ", "api-1", t0)
+
+	top := s.Top(0)
+	if len(top) != 2 {
+		t.Fatalf("expected 2 patterns, got %d", len(top))
+	}
+	p := top[0]
+	if p.Count != 3 || len(p.Pods) != 2 {
+		t.Errorf("expected count 3 across 2 pods, got %d across %d", p.Count, len(p.Pods))
+	}
+	if !p.FirstSeen.Equal(t0) || !p.LastSeen.Equal(t0.Add(2*time.Minute)) {
+		t.Errorf("unexpected first/last seen: %v / %v", p.FirstSeen, p.LastSeen)
+	}
+	if p.Samples["api-1"] != "timeout after 30s calling 10.0.0.1" || p.Samples["api-2"] != "timeout after 31s calling 10.0.0.2" {
+		t.Errorf("unexpected samples per pod: %v", p.Samples)
+	}
+	if got := s.Top(1); len(got) != 1 {
+		t.Errorf("expected Top(1) to return 1 pattern, got %d", len(got))
+	}
+}
+
+func TestSplitLogTimestamp(t *testing.T) {
+	ts, msg := splitLogTimestamp("2025-06-01T10:00:00.123456789Z hola mundo")
+	if ts.IsZero() || msg != "hola mundo" {
+		t.Errorf("unexpected split: %v %q", ts, msg)
+	}
+	ts, msg = splitLogTimestamp("sin marca de tiempo")
+	if !ts.IsZero() || msg != "sin marca de tiempo" {
+		t.Errorf("expected line unchanged, got %v %q", ts, msg)
+	}
+}
@@ -12,12 +12,50 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+var nodesOutputFormat string
+var nodesGroupBy string
+
+// nodeSummary reúne el estado, la capacidad y el uso de un nodo.
+type nodeSummary struct {
+	Name              string      `json:"name"`
+	Status            string      `json:"status"`
+	Roles             string      `json:"roles"`
+	KubeletVersion    string      `json:"kubeletVersion"`
+	InternalIP        string      `json:"internalIP,omitempty"`
+	Age               string      `json:"age"`
+	Pods              int         `json:"pods"`
+	PodsAllocatable   int64       `json:"podsAllocatable"`
+	CPUAllocatable    int64       `json:"cpuAllocatableMillis"`
+	CPURequested      int64       `json:"cpuRequestedMillis"`
+	CPUUsage          *int64      `json:"cpuUsageMillis,omitempty"`
+	MemoryAllocatable int64       `json:"memoryAllocatableBytes"`
+	MemoryRequested   int64       `json:"memoryRequestedBytes"`
+	MemoryUsage       *int64      `json:"memoryUsageBytes,omitempty"`
+	Taints            []string    `json:"taints,omitempty"`
+	Pressure          []string    `json:"pressure,omitempty"`
+	Unschedulable     bool        `json:"unschedulable,omitempty"`
+	EKS               eksNodeInfo `json:"eks"`
+}
+
 var nodesCmd = &cobra.Command{
-	Use:   "nodes",
+	Use:   "nodes [nombre-del-nodo]",
 	Short: "Muestra información detallada sobre los nodos del clúster.",
 	Long: `El comando nodes recupera y muestra información detallada sobre
-los nodos del clúster de Kubernetes, incluyendo su estado, roles y uso de recursos.
+los nodos del clúster de Kubernetes, incluyendo su estado, roles, pods
+programados frente a los permitidos, CPU/memoria solicitadas frente a las
+asignables, taints y condiciones de presión (MemoryPressure, DiskPressure,
+PIDPressure). También muestra los metadatos de EKS y Karpenter: nodegroup o
+nodepool, tipo de instancia, zona y tipo de capacidad (ON_DEMAND/SPOT); con
+-o wide añade AMI, imagen del sistema operativo y kernel.
+
+Con --group-by nodegroup|zone|instance-type muestra un resumen por grupo.
+
+Si se indica el nombre de un nodo, muestra su detalle: condiciones, taints,
+capacidad frente a asignable, todos sus pods con requests, limits y uso real,
+las imágenes en caché y los eventos recientes del nodo.
+
 El uso de recursos requiere un servidor de métricas instalado en el clúster.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		clients, err := GetKubeClients()
 		if err != nil {
@@ -25,7 +63,40 @@ El uso de recursos requiere un servidor de métricas instalado en el clúster.`,
 			os.Exit(1)
 		}
 
-		fmt.Fprintln(os.Stdout, "Recuperando información de nodos de Kubernetes...")
+		outputLower := strings.ToLower(nodesOutputFormat)
+		groupBy := strings.ToLower(nodesGroupBy)
+		switch groupBy {
+		case "", nodeGroupByNodegroup, nodeGroupByZone, nodeGroupByInstanceType:
+		default:
+			fmt.Fprintf(os.Stderr, "Error: --group-by debe ser nodegroup, zone o instance-type.\n")
+			os.Exit(1)
+		}
+		// Con salida JSON/YAML los mensajes informativos van a stderr.
+		info := os.Stdout
+		if isStructuredOutput(outputLower) {
+			info = os.Stderr
+		}
+		switch outputLower {
+		case "", "wide", "json", "yaml":
+		default:
+			fmt.Fprintf(os.Stderr, "Error: formato de salida no soportado '%s' (usa wide, json o yaml).\n", nodesOutputFormat)
+			os.Exit(1)
+		}
+
+		if len(args) == 1 {
+			if groupBy != "" {
+				fmt.Fprintln(os.Stderr, "Error: --group-by no se puede usar al indicar un nodo.")
+				os.Exit(1)
+			}
+			fmt.Fprintf(info, "Recuperando detalle del nodo %s...\n", args[0])
+			if err := runNodeDetail(clients, args[0], outputLower); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		fmt.Fprintln(info, "Recuperando información de nodos de Kubernetes...")
 
 		nodes, err := clients.Core.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
@@ -33,58 +104,207 @@ El uso de recursos requiere un servidor de métricas instalado en el clúster.`,
 			os.Exit(1)
 		}
 
-		fmt.Fprintln(os.Stdout, "\n--- Nodos ---")
-		if len(nodes.Items) == 0 {
-			fmt.Fprintln(os.Stdout, "No se encontraron nodos.")
+		if len(nodes.Items) == 0 && !isStructuredOutput(outputLower) {
+			fmt.Fprintln(info, "\n--- Nodos ---")
+			fmt.Fprintln(info, "No se encontraron nodos.")
+			return
+		}
+		if Verbose {
+			fmt.Fprintf(info, "DEBUG: Nodos encontrados: %d\n", len(nodes.Items))
+		}
+
+		// Pods no terminados de todos los namespaces, para sumar requests por nodo.
+		pods, err := clients.Core.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{
+			FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+		})
+		var podItems []corev1.Pod
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar pods, no se mostrarán requests por nodo: %v\n", err)
 		} else {
-			headers := []string{"NOMBRE", "ESTADO", "ROLES", "VERSIÓN", "CPU_ALLOC", "CPU_USO", "MEM_ALLOC", "MEM_USO", "EDAD"}
-			rows := make([][]string, 0, len(nodes.Items))
-
-			if Verbose {
-				fmt.Fprintf(os.Stdout, "DEBUG: Nodos encontrados: %d\n", len(nodes.Items))
-			}
-
-			for _, node := range nodes.Items {
-				status := getNodeStatus(node) // Asegúrate que esta función exista
-				roles := getNodeRoles(node)   // Asegúrate que esta función exista
-				kubeletVersion := node.Status.NodeInfo.KubeletVersion
-				age := metav1.Now().Sub(node.CreationTimestamp.Time).Truncate(time.Second).String()
-
-				cpuAlloc := node.Status.Allocatable[corev1.ResourceCPU]
-				memAlloc := node.Status.Allocatable[corev1.ResourceMemory]
-				cpuAllocStr := cpuAlloc.String()
-				memAllocStr := memAlloc.String()
-
-				cpuUsage := "N/A"
-				memUsage := "N/A"
-
-				if clients.Metrics != nil {
-					nodeMetrics, errMetrics := clients.Metrics.MetricsV1beta1().NodeMetricses().Get(context.TODO(), node.Name, metav1.GetOptions{})
-					if errMetrics == nil {
-						cpuUsed := nodeMetrics.Usage[corev1.ResourceCPU]
-						memUsed := nodeMetrics.Usage[corev1.ResourceMemory]
-
-						if cpuAlloc.MilliValue() > 0 {
-							cpuUsage = fmt.Sprintf("%.2f%%", float64(cpuUsed.MilliValue())*100.0/float64(cpuAlloc.MilliValue()))
-						}
-						if memAlloc.Value() > 0 {
-							memUsage = fmt.Sprintf("%.2f%%", float64(memUsed.Value())*100.0/float64(memAlloc.Value()))
-						}
-					} else {
-						if Verbose {
-							fmt.Fprintf(os.Stdout, "DEBUG: No se pudieron obtener métricas para el nodo %s: %v\n", node.Name, errMetrics)
-						}
-					}
+			podItems = pods.Items
+		}
+
+		// Una sola llamada para las métricas de todos los nodos.
+		usage := map[string]corev1.ResourceList{}
+		if clients.Metrics != nil {
+			nodeMetrics, errMetrics := clients.Metrics.MetricsV1beta1().NodeMetricses().List(context.TODO(), metav1.ListOptions{})
+			if errMetrics == nil {
+				for _, m := range nodeMetrics.Items {
+					usage[m.Name] = m.Usage
 				}
-				rows = append(rows, []string{node.Name, status, roles, kubeletVersion, cpuAllocStr, cpuUsage, memAllocStr, memUsage, age})
+			} else if Verbose {
+				fmt.Fprintf(info, "DEBUG: No se pudieron obtener métricas de nodos: %v\n", errMetrics)
 			}
-			PrintBasicTable(headers, rows)
 		}
+
+		summaries := buildNodeSummaries(nodes.Items, podItems, usage)
+
+		var groups []*nodeGroupSummary
+		if groupBy != "" {
+			groups = groupNodeSummaries(summaries, groupBy)
+		}
+
+		if isStructuredOutput(outputLower) {
+			doc := newOutputList("NodeList", summaries)
+			if groupBy != "" {
+				doc = newOutputList("NodeGroupList", groups)
+			}
+			if err := writeStructuredOutput(os.Stdout, outputLower, doc); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if groupBy != "" {
+			fmt.Fprintf(os.Stdout, "\n--- Nodos por %s ---\n", groupBy)
+			printNodeGroupSummaries(groups, groupBy)
+			return
+		}
+
+		fmt.Fprintln(os.Stdout, "\n--- Nodos ---")
+		headers := []string{"NOMBRE", "ESTADO", "ROLES", "VERSIÓN", "NODEGROUP", "INSTANCIA", "ZONA", "CAPACIDAD", "PODS", "CPU_REQ", "CPU_USO", "MEM_REQ", "MEM_USO", "PRESIÓN", "EDAD"}
+		if outputLower == "wide" {
+			headers = append(headers, "IP-INTERNA", "CPU_ALLOC", "MEM_ALLOC", "TAINTS", "AMI", "OS-IMAGE", "KERNEL")
+		}
+		rows := make([][]string, 0, len(summaries))
+		for _, n := range summaries {
+			cpuUsage, memUsage := "N/A", "N/A"
+			if n.CPUUsage != nil {
+				cpuUsage = formatPercent(*n.CPUUsage, n.CPUAllocatable)
+			}
+			if n.MemoryUsage != nil {
+				memUsage = formatPercent(*n.MemoryUsage, n.MemoryAllocatable)
+			}
+			pressure := "-"
+			if len(n.Pressure) > 0 {
+				pressure = strings.Join(n.Pressure, ",")
+			}
+			row := []string{
+				n.Name, n.Status, n.Roles, n.KubeletVersion,
+				valueOrNone(n.EKS.groupName()), valueOrNone(n.EKS.InstanceType), valueOrNone(n.EKS.Zone), valueOrNone(n.EKS.CapacityType),
+				fmt.Sprintf("%d/%d", n.Pods, n.PodsAllocatable),
+				fmt.Sprintf("%s (%s)", formatMilliCPU(n.CPURequested), formatPercent(n.CPURequested, n.CPUAllocatable)),
+				cpuUsage,
+				fmt.Sprintf("%s (%s)", formatBytes(n.MemoryRequested), formatPercent(n.MemoryRequested, n.MemoryAllocatable)),
+				memUsage,
+				pressure,
+				n.Age,
+			}
+			if outputLower == "wide" {
+				taints := "<none>"
+				if len(n.Taints) > 0 {
+					taints = strings.Join(n.Taints, ",")
+				}
+				row = append(row, n.InternalIP, formatMilliCPU(n.CPUAllocatable), formatBytes(n.MemoryAllocatable), taints,
+					valueOrNone(n.EKS.AMI), n.EKS.OSImage, n.EKS.KernelVersion)
+			}
+			rows = append(rows, row)
+		}
+		PrintBasicTable(headers, rows)
 	},
 }
 
 func init() {
 	monitorCmd.AddCommand(nodesCmd)
+	nodesCmd.Flags().StringVarP(&nodesOutputFormat, "output", "o", "", "Formato de salida. Soportado: wide, json, yaml")
+	nodesCmd.Flags().StringVar(&nodesGroupBy, "group-by", "", "Resumir los nodos por nodegroup, zone o instance-type")
+}
+
+// buildNodeSummaries combina nodos, pods programados y métricas de uso (por nombre de nodo).
+func buildNodeSummaries(nodes []corev1.Node, pods []corev1.Pod, usage map[string]corev1.ResourceList) []nodeSummary {
+	type podTotals struct {
+		count    int
+		cpu, mem int64
+	}
+	perNode := map[string]*podTotals{}
+	for _, p := range pods {
+		if p.Spec.NodeName == "" || p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		t, ok := perNode[p.Spec.NodeName]
+		if !ok {
+			t = &podTotals{}
+			perNode[p.Spec.NodeName] = t
+		}
+		cpu, mem := podSchedulingRequests(p)
+		t.count++
+		t.cpu += cpu
+		t.mem += mem
+	}
+
+	result := make([]nodeSummary, 0, len(nodes))
+	for _, node := range nodes {
+		cpuAlloc := node.Status.Allocatable[corev1.ResourceCPU]
+		memAlloc := node.Status.Allocatable[corev1.ResourceMemory]
+		podsAlloc := node.Status.Allocatable[corev1.ResourcePods]
+
+		status := getNodeStatus(node)
+		if node.Spec.Unschedulable {
+			status += ",SchedulingDisabled"
+		}
+		s := nodeSummary{
+			Name:              node.Name,
+			Status:            status,
+			Roles:             getNodeRoles(node),
+			KubeletVersion:    node.Status.NodeInfo.KubeletVersion,
+			Age:               metav1.Now().Sub(node.CreationTimestamp.Time).Truncate(time.Second).String(),
+			PodsAllocatable:   podsAlloc.Value(),
+			CPUAllocatable:    cpuAlloc.MilliValue(),
+			MemoryAllocatable: memAlloc.Value(),
+			Taints:            formatTaints(node.Spec.Taints),
+			Pressure:          getNodePressure(node),
+			Unschedulable:     node.Spec.Unschedulable,
+			EKS:               getEKSNodeInfo(node),
+		}
+		for _, addr := range node.Status.Addresses {
+			if addr.Type == corev1.NodeInternalIP {
+				s.InternalIP = addr.Address
+				break
+			}
+		}
+		if t, ok := perNode[node.Name]; ok {
+			s.Pods = t.count
+			s.CPURequested = t.cpu
+			s.MemoryRequested = t.mem
+		}
+		if u, ok := usage[node.Name]; ok {
+			cpu := u[corev1.ResourceCPU]
+			mem := u[corev1.ResourceMemory]
+			cpuMilli, memBytes := cpu.MilliValue(), mem.Value()
+			s.CPUUsage = &cpuMilli
+			s.MemoryUsage = &memBytes
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// getNodePressure devuelve las condiciones de presión activas del nodo.
+func getNodePressure(node corev1.Node) []string {
+	var pressure []string
+	for _, c := range node.Status.Conditions {
+		switch c.Type {
+		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure:
+			if c.Status == corev1.ConditionTrue {
+				pressure = append(pressure, string(c.Type))
+			}
+		}
+	}
+	return pressure
+}
+
+// formatTaints devuelve los taints en formato key=value:Effect.
+func formatTaints(taints []corev1.Taint) []string {
+	var result []string
+	for _, t := range taints {
+		if t.Value != "" {
+			result = append(result, fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect))
+		} else {
+			result = append(result, fmt.Sprintf("%s:%s", t.Key, t.Effect))
+		}
+	}
+	return result
 }
 
 // Helper functions (asegúrate que estén aquí)
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// maxNodeDetailRows limita las filas de imágenes y eventos en la vista de detalle.
+const maxNodeDetailRows = 15
+
+// nodePodDetail describe un pod programado en el nodo con sus recursos y su uso.
+type nodePodDetail struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Phase     string `json:"phase"`
+	Restarts  int32  `json:"restarts"`
+	CPUUsage  *int64 `json:"cpuUsageMillis,omitempty"`
+	MemUsage  *int64 `json:"memoryUsageBytes,omitempty"`
+	resourceTotals
+}
+
+// nodeImage es una imagen en caché en el nodo.
+type nodeImage struct {
+	Name      string `json:"name"`
+	SizeBytes int64  `json:"sizeBytes"`
+}
+
+// nodeDetail reúne toda la información necesaria para decidir si un nodo se debe
+// acordonar o reemplazar.
+type nodeDetail struct {
+	nodeSummary
+	CPUCapacity    int64                  `json:"cpuCapacityMillis"`
+	MemoryCapacity int64                  `json:"memoryCapacityBytes"`
+	PodsCapacity   int64                  `json:"podsCapacity"`
+	CPULimits      int64                  `json:"cpuLimitsMillis"`
+	MemoryLimits   int64                  `json:"memoryLimitsBytes"`
+	Conditions     []corev1.NodeCondition `json:"conditions"`
+	PodDetails     []nodePodDetail        `json:"podDetails"`
+	Images         []nodeImage            `json:"images"`
+	Events         []clusterEvent         `json:"events"`
+}
+
+// runNodeDetail muestra la vista de detalle de un nodo.
+func runNodeDetail(clients *KubeClients, nodeName, outputLower string) error {
+	node, err := clients.Core.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("obteniendo nodo '%s': %w", nodeName, err)
+	}
+
+	pods, err := clients.Core.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return fmt.Errorf("listando pods del nodo '%s': %w", nodeName, err)
+	}
+
+	nodeUsage := map[string]corev1.ResourceList{}
+	var podMetrics []metricsv1beta1.PodMetrics
+	if clients.Metrics != nil {
+		if m, err := clients.Metrics.MetricsV1beta1().NodeMetricses().Get(context.TODO(), nodeName, metav1.GetOptions{}); err == nil {
+			nodeUsage[nodeName] = m.Usage
+		} else if Verbose {
+			fmt.Fprintf(os.Stderr, "DEBUG: No se pudieron obtener métricas del nodo %s: %v\n", nodeName, err)
+		}
+		// Solo los namespaces con pods en el nodo: en clusters grandes la lista completa es cara.
+		namespaces := map[string]bool{}
+		for _, p := range pods.Items {
+			if namespaces[p.Namespace] {
+				continue
+			}
+			namespaces[p.Namespace] = true
+			if list, err := clients.Metrics.MetricsV1beta1().PodMetricses(p.Namespace).List(context.TODO(), metav1.ListOptions{}); err == nil {
+				podMetrics = append(podMetrics, list.Items...)
+			} else if Verbose {
+				fmt.Fprintf(os.Stderr, "DEBUG: No se pudieron obtener métricas de pods en %s: %v\n", p.Namespace, err)
+			}
+		}
+	}
+
+	events, err := listNodeEvents(clients.Core, nodeName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar eventos: %v\n", err)
+	}
+
+	detail := buildNodeDetail(*node, pods.Items, nodeUsage, podMetrics, events)
+
+	if isStructuredOutput(outputLower) {
+		doc := struct {
+			outputHeader
+			nodeDetail
+		}{newOutputHeader("NodeDetail"), detail}
+		return writeStructuredOutput(os.Stdout, outputLower, doc)
+	}
+
+	printNodeDetail(detail)
+	return nil
+}
+
+// buildNodeDetail combina el nodo con sus pods, métricas y eventos.
+func buildNodeDetail(node corev1.Node, pods []corev1.Pod, nodeUsage map[string]corev1.ResourceList, podMetrics []metricsv1beta1.PodMetrics, events []clusterEvent) nodeDetail {
+	usageByPod := map[string]podUsage{}
+	for _, u := range buildPodUsages(pods, podMetrics, false) {
+		usageByPod[u.Namespace+"/"+u.Pod] = u
+	}
+	summaries := buildNodeSummaries([]corev1.Node{node}, pods, nodeUsage)
+	cpuCap := node.Status.Capacity[corev1.ResourceCPU]
+	memCap := node.Status.Capacity[corev1.ResourceMemory]
+	podsCap := node.Status.Capacity[corev1.ResourcePods]
+
+	d := nodeDetail{
+		nodeSummary:    summaries[0],
+		CPUCapacity:    cpuCap.MilliValue(),
+		MemoryCapacity: memCap.Value(),
+		PodsCapacity:   podsCap.Value(),
+		Conditions:     node.Status.Conditions,
+	}
+
+	for _, p := range pods {
+		pd := nodePodDetail{Namespace: p.Namespace, Name: p.Name, Phase: string(p.Status.Phase), resourceTotals: podResources(p)}
+		for _, cs := range p.Status.ContainerStatuses {
+			pd.Restarts += cs.RestartCount
+		}
+		if u, ok := usageByPod[p.Namespace+"/"+p.Name]; ok {
+			cpuMilli, memBytes := u.CPUUsage, u.MemUsage
+			pd.CPUUsage = &cpuMilli
+			pd.MemUsage = &memBytes
+		}
+		if p.Status.Phase != corev1.PodSucceeded && p.Status.Phase != corev1.PodFailed {
+			// Los límites del nodo suman los límites declarados, como kubectl describe node.
+			for _, c := range p.Spec.Containers {
+				r := containerResources(c)
+				d.CPULimits += r.CPULimit
+				d.MemoryLimits += r.MemLimit
+			}
+		}
+		d.PodDetails = append(d.PodDetails, pd)
+	}
+	sort.Slice(d.PodDetails, func(i, j int) bool {
+		if d.PodDetails[i].Namespace != d.PodDetails[j].Namespace {
+			return d.PodDetails[i].Namespace < d.PodDetails[j].Namespace
+		}
+		return d.PodDetails[i].Name < d.PodDetails[j].Name
+	})
+
+	for _, img := range node.Status.Images {
+		name := "<none>"
+		// El primer nombre suele ser el digest; preferimos uno con tag legible.
+		for _, n := range img.Names {
+			name = n
+			if !strings.Contains(n, "@sha256:") {
+				break
+			}
+		}
+		d.Images = append(d.Images, nodeImage{Name: name, SizeBytes: img.SizeBytes})
+	}
+	sort.Slice(d.Images, func(i, j int) bool { return d.Images[i].SizeBytes > d.Images[j].SizeBytes })
+
+	d.Events = filterEvents(events, eventFilter{ForKind: "Node", ForName: node.Name}, time.Now())
+	d.Events = dedupeEvents(d.Events)
+	sortEventsByRecent(d.Events)
+	return d
+}
+
+// printNodeDetail imprime la vista de detalle de un nodo en secciones.
+func printNodeDetail(d nodeDetail) {
+	fmt.Fprintf(os.Stdout, "\n--- Nodo: %s ---\n", d.Name)
+	PrintBasicTable([]string{"CAMPO", "VALOR"}, [][]string{
+		{"Estado", d.Status},
+		{"Roles", d.Roles},
+		{"Versión kubelet", d.KubeletVersion},
+		{"IP interna", valueOrNone(d.InternalIP)},
+		{"Nodegroup", valueOrNone(d.EKS.groupName())},
+		{"Tipo de instancia", valueOrNone(d.EKS.InstanceType)},
+		{"Zona", valueOrNone(d.EKS.Zone)},
+		{"Capacidad", valueOrNone(d.EKS.CapacityType)},
+		{"AMI", valueOrNone(d.EKS.AMI)},
+		{"Imagen SO / kernel", fmt.Sprintf("%s / %s", d.EKS.OSImage, d.EKS.KernelVersion)},
+		{"Taints", valueOrNone(strings.Join(d.Taints, ", "))},
+		{"Edad", d.Age},
+	})
+
+	fmt.Fprintln(os.Stdout, "--- Condiciones ---")
+	condRows := make([][]string, 0, len(d.Conditions))
+	for _, c := range d.Conditions {
+		condRows = append(condRows, []string{
+			string(c.Type), string(c.Status), c.Reason,
+			formatEventAge(c.LastHeartbeatTime.Time), formatEventAge(c.LastTransitionTime.Time),
+			truncateString(c.Message, 80),
+		})
+	}
+	PrintBasicTable([]string{"TIPO", "ESTADO", "RAZÓN", "ÚLTIMO HEARTBEAT", "ÚLTIMA TRANSICIÓN", "MENSAJE"}, condRows)
+
+	fmt.Fprintln(os.Stdout, "--- Recursos ---")
+	cpuUsage, memUsage := "N/A", "N/A"
+	if d.CPUUsage != nil {
+		cpuUsage = fmt.Sprintf("%s (%s)", formatMilliCPU(*d.CPUUsage), formatPercent(*d.CPUUsage, d.CPUAllocatable))
+	}
+	if d.MemoryUsage != nil {
+		memUsage = fmt.Sprintf("%s (%s)", formatBytes(*d.MemoryUsage), formatPercent(*d.MemoryUsage, d.MemoryAllocatable))
+	}
+	PrintBasicTable([]string{"RECURSO", "CAPACIDAD", "ASIGNABLE", "REQUESTS", "LIMITS", "USO"}, [][]string{
+		{"cpu", formatMilliCPU(d.CPUCapacity), formatMilliCPU(d.CPUAllocatable),
+			fmt.Sprintf("%s (%s)", formatMilliCPU(d.CPURequested), formatPercent(d.CPURequested, d.CPUAllocatable)),
+			fmt.Sprintf("%s (%s)", formatMilliCPU(d.CPULimits), formatPercent(d.CPULimits, d.CPUAllocatable)),
+			cpuUsage},
+		{"memory", formatBytes(d.MemoryCapacity), formatBytes(d.MemoryAllocatable),
+			fmt.Sprintf("%s (%s)", formatBytes(d.MemoryRequested), formatPercent(d.MemoryRequested, d.MemoryAllocatable)),
+			fmt.Sprintf("%s (%s)", formatBytes(d.MemoryLimits), formatPercent(d.MemoryLimits, d.MemoryAllocatable)),
+			memUsage},
+		{"pods", fmt.Sprintf("%d", d.PodsCapacity), fmt.Sprintf("%d", d.PodsAllocatable),
+			fmt.Sprintf("%d (%s)", d.Pods, formatPercent(int64(d.Pods), d.PodsAllocatable)), "-", "-"},
+	})
+
+	fmt.Fprintf(os.Stdout, "--- Pods (%d) ---\n", len(d.PodDetails))
+	if len(d.PodDetails) == 0 {
+		fmt.Fprintln(os.Stdout, "No hay pods en este nodo.")
+	} else {
+		podRows := make([][]string, 0, len(d.PodDetails))
+		for _, p := range d.PodDetails {
+			cpu, mem := "N/A", "N/A"
+			if p.CPUUsage != nil {
+				cpu = formatMilliCPU(*p.CPUUsage)
+			}
+			if p.MemUsage != nil {
+				mem = formatBytes(*p.MemUsage)
+			}
+			podRows = append(podRows, []string{
+				p.Namespace, p.Name, p.Phase, fmt.Sprintf("%d", p.Restarts),
+				formatMilliCPU(p.CPURequest), formatMilliCPU(p.CPULimit), cpu,
+				formatBytes(p.MemRequest), formatBytes(p.MemLimit), mem,
+			})
+		}
+		PrintBasicTable([]string{"NAMESPACE", "NAME", "ESTADO", "REINICIOS", "CPU REQ", "CPU LIM", "CPU USO", "MEM REQ", "MEM LIM", "MEM USO"}, podRows)
+	}
+
+	fmt.Fprintf(os.Stdout, "--- Imágenes en caché (%d) ---\n", len(d.Images))
+	imgRows := [][]string{}
+	for i, img := range d.Images {
+		if i == maxNodeDetailRows {
+			imgRows = append(imgRows, []string{fmt.Sprintf("... y %d más", len(d.Images)-maxNodeDetailRows), ""})
+			break
+		}
+		imgRows = append(imgRows, []string{img.Name, formatBytes(img.SizeBytes)})
+	}
+	PrintBasicTable([]string{"IMAGEN", "TAMAÑO"}, imgRows)
+
+	fmt.Fprintf(os.Stdout, "--- Eventos recientes (%d) ---\n", len(d.Events))
+	if len(d.Events) == 0 {
+		fmt.Fprintln(os.Stdout, "No hay eventos recientes para este nodo.")
+		return
+	}
+	evRows := [][]string{}
+	for i, e := range d.Events {
+		if i == maxNodeDetailRows {
+			break
+		}
+		evRows = append(evRows, []string{formatEventAge(e.LastSeen), fmt.Sprintf("%d", e.Count), e.Type, e.Reason, truncateString(e.Message, 100)})
+	}
+	PrintBasicTable([]string{"ÚLTIMA VEZ", "CUENTA", "TIPO", "RAZÓN", "MENSAJE"}, evRows)
+}
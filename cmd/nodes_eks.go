@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Etiquetas que EKS, eksctl y Karpenter añaden a los nodos.
+const (
+	labelEKSNodegroup       = "eks.amazonaws.com/nodegroup"
+	labelEKSCapacityType    = "eks.amazonaws.com/capacityType"
+	labelEKSNodegroupImage  = "eks.amazonaws.com/nodegroup-image"
+	labelEksctlNodegroup    = "alpha.eksctl.io/nodegroup-name"
+	labelKarpenterNodePool  = "karpenter.sh/nodepool"
+	labelKarpenterCapacity  = "karpenter.sh/capacity-type"
+	labelInstanceType       = "node.kubernetes.io/instance-type"
+	labelInstanceTypeLegacy = "beta.kubernetes.io/instance-type"
+	labelZone               = "topology.kubernetes.io/zone"
+	labelZoneLegacy         = "failure-domain.beta.kubernetes.io/zone"
+)
+
+// Criterios válidos para monitor nodes --group-by.
+const (
+	nodeGroupByNodegroup    = "nodegroup"
+	nodeGroupByZone         = "zone"
+	nodeGroupByInstanceType = "instance-type"
+)
+
+// eksNodeInfo contiene los metadatos de EKS/Karpenter de un nodo.
+type eksNodeInfo struct {
+	Nodegroup     string `json:"nodegroup,omitempty"`
+	NodePool      string `json:"nodePool,omitempty"`
+	InstanceType  string `json:"instanceType,omitempty"`
+	Zone          string `json:"zone,omitempty"`
+	CapacityType  string `json:"capacityType,omitempty"`
+	AMI           string `json:"ami,omitempty"`
+	OSImage       string `json:"osImage,omitempty"`
+	KernelVersion string `json:"kernelVersion,omitempty"`
+}
+
+// getEKSNodeInfo extrae los metadatos de EKS de las etiquetas y del NodeInfo del nodo.
+func getEKSNodeInfo(node corev1.Node) eksNodeInfo {
+	labels := node.Labels
+	info := eksNodeInfo{
+		Nodegroup:     firstLabel(labels, labelEKSNodegroup, labelEksctlNodegroup),
+		NodePool:      labels[labelKarpenterNodePool],
+		InstanceType:  firstLabel(labels, labelInstanceType, labelInstanceTypeLegacy),
+		Zone:          firstLabel(labels, labelZone, labelZoneLegacy),
+		AMI:           labels[labelEKSNodegroupImage],
+		OSImage:       node.Status.NodeInfo.OSImage,
+		KernelVersion: node.Status.NodeInfo.KernelVersion,
+	}
+	// EKS usa ON_DEMAND/SPOT y Karpenter on-demand/spot: se normaliza al formato de EKS.
+	capacity := firstLabel(labels, labelEKSCapacityType, labelKarpenterCapacity)
+	info.CapacityType = strings.ToUpper(strings.ReplaceAll(capacity, "-", "_"))
+	return info
+}
+
+// groupName devuelve el nombre del grupo de nodos: el nodegroup gestionado o,
+// para nodos de Karpenter, el nodepool con el prefijo "karpenter:".
+func (i eksNodeInfo) groupName() string {
+	if i.Nodegroup != "" {
+		return i.Nodegroup
+	}
+	if i.NodePool != "" {
+		return "karpenter:" + i.NodePool
+	}
+	return ""
+}
+
+func firstLabel(labels map[string]string, keys ...string) string {
+	for _, k := range keys {
+		if v := labels[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// valueOrNone devuelve "<none>" para cadenas vacías.
+func valueOrNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+// nodeGroupSummary agrega los nodos que comparten nodegroup, zona o tipo de instancia.
+type nodeGroupSummary struct {
+	Group             string         `json:"group"`
+	Nodes             int            `json:"nodes"`
+	Ready             int            `json:"ready"`
+	Pods              int            `json:"pods"`
+	PodsAllocatable   int64          `json:"podsAllocatable"`
+	CPUAllocatable    int64          `json:"cpuAllocatableMillis"`
+	CPURequested      int64          `json:"cpuRequestedMillis"`
+	MemoryAllocatable int64          `json:"memoryAllocatableBytes"`
+	MemoryRequested   int64          `json:"memoryRequestedBytes"`
+	CapacityTypes     map[string]int `json:"capacityTypes"`
+	KubeletVersions   map[string]int `json:"kubeletVersions"`
+}
+
+// groupNodeSummaries agrupa los nodos según groupBy y ordena los grupos por nombre.
+func groupNodeSummaries(nodes []nodeSummary, groupBy string) []*nodeGroupSummary {
+	groups := map[string]*nodeGroupSummary{}
+	for _, n := range nodes {
+		var key string
+		switch groupBy {
+		case nodeGroupByNodegroup:
+			key = n.EKS.groupName()
+		case nodeGroupByZone:
+			key = n.EKS.Zone
+		case nodeGroupByInstanceType:
+			key = n.EKS.InstanceType
+		}
+		key = valueOrNone(key)
+
+		g, ok := groups[key]
+		if !ok {
+			g = &nodeGroupSummary{Group: key, CapacityTypes: map[string]int{}, KubeletVersions: map[string]int{}}
+			groups[key] = g
+		}
+		g.Nodes++
+		if strings.HasPrefix(n.Status, "Ready") {
+			g.Ready++
+		}
+		g.Pods += n.Pods
+		g.PodsAllocatable += n.PodsAllocatable
+		g.CPUAllocatable += n.CPUAllocatable
+		g.CPURequested += n.CPURequested
+		g.MemoryAllocatable += n.MemoryAllocatable
+		g.MemoryRequested += n.MemoryRequested
+		g.CapacityTypes[valueOrNone(n.EKS.CapacityType)]++
+		g.KubeletVersions[n.KubeletVersion]++
+	}
+
+	result := make([]*nodeGroupSummary, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, g)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Group < result[j].Group })
+	return result
+}
+
+// printNodeGroupSummaries imprime el resumen agrupado de nodos.
+func printNodeGroupSummaries(groups []*nodeGroupSummary, groupBy string) {
+	headers := []string{strings.ToUpper(groupBy), "NODOS", "READY", "PODS", "CPU_REQ", "MEM_REQ", "CAPACIDAD", "VERSIONES"}
+	rows := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		rows = append(rows, []string{
+			g.Group,
+			fmt.Sprintf("%d", g.Nodes),
+			fmt.Sprintf("%d/%d", g.Ready, g.Nodes),
+			fmt.Sprintf("%d/%d", g.Pods, g.PodsAllocatable),
+			fmt.Sprintf("%s/%s (%s)", formatMilliCPU(g.CPURequested), formatMilliCPU(g.CPUAllocatable), formatPercent(g.CPURequested, g.CPUAllocatable)),
+			fmt.Sprintf("%s/%s (%s)", formatBytes(g.MemoryRequested), formatBytes(g.MemoryAllocatable), formatPercent(g.MemoryRequested, g.MemoryAllocatable)),
+			formatCountMap(g.CapacityTypes, 3),
+			formatCountMap(g.KubeletVersions, 3),
+		})
+	}
+	PrintBasicTable(headers, rows)
+}
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+func TestBuildNodeSummaries(t *testing.T) {
+	node := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "ip-10-0-1-10"},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+			Taints:        []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}},
+		},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+				corev1.ResourcePods:   resource.MustParse("29"),
+			},
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodeNetworkUnavailable, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	pods := []corev1.Pod{
+		{
+			Spec: corev1.PodSpec{
+				NodeName:       "ip-10-0-1-10",
+				Containers:     []corev1.Container{testContainer("app", "500m", "", "1Gi", "")},
+				InitContainers: []corev1.Container{testContainer("init", "1", "", "128Mi", "")},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		{
+			Spec:   corev1.PodSpec{NodeName: "ip-10-0-1-10", Containers: []corev1.Container{testContainer("app", "250m", "", "512Mi", "")}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		{
+			Spec:   corev1.PodSpec{NodeName: "ip-10-0-1-10", Containers: []corev1.Container{testContainer("done", "4", "", "", "")}},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+	}
+	usage := map[string]corev1.ResourceList{
+		"ip-10-0-1-10": {corev1.ResourceCPU: resource.MustParse("300m"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+
+	summaries := buildNodeSummaries([]corev1.Node{node}, pods, usage)
+	if len(summaries) != 1 {
+		t.Fatalf("expected 1 summary, got %d", len(summaries))
+	}
+	s := summaries[0]
+	if s.Status != "Ready,SchedulingDisabled" {
+		t.Errorf("unexpected status %q", s.Status)
+	}
+	if s.Pods != 2 || s.PodsAllocatable != 29 {
+		t.Errorf("unexpected pod counts %d/%d", s.Pods, s.PodsAllocatable)
+	}
+	// El init container (1 CPU) domina sobre el contenedor (500m) en el primer pod.
+	if s.CPURequested != 1250 || s.CPUAllocatable != 2000 {
+		t.Errorf("unexpected CPU requested/allocatable %d/%d", s.CPURequested, s.CPUAllocatable)
+	}
+	if s.MemoryRequested != 1536*1024*1024 {
+		t.Errorf("unexpected memory requested %d", s.MemoryRequested)
+	}
+	if s.CPUUsage == nil || *s.CPUUsage != 300 {
+		t.Errorf("unexpected CPU usage %v", s.CPUUsage)
+	}
+	if len(s.Pressure) != 1 || s.Pressure[0] != "MemoryPressure" {
+		t.Errorf("unexpected pressure %v", s.Pressure)
+	}
+	if len(s.Taints) != 1 || s.Taints[0] != "dedicated=gpu:NoSchedule" {
+		t.Errorf("unexpected taints %v", s.Taints)
+	}
+}
+
+func TestGetEKSNodeInfo(t *testing.T) {
+	managed := corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+		labelEKSNodegroup:      "ng-general",
+		labelEKSCapacityType:   "ON_DEMAND",
+		labelEKSNodegroupImage: "ami-0abc",
+		labelInstanceType:      "m5.large",
+		labelZone:              "us-east-1a",
+	}}}
+	info := getEKSNodeInfo(managed)
+	if info.groupName() != "ng-general" || info.CapacityType != "ON_DEMAND" || info.InstanceType != "m5.large" || info.Zone != "us-east-1a" || info.AMI != "ami-0abc" {
+		t.Errorf("unexpected managed node info: %+v", info)
+	}
+
+	karpenter := corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+		labelKarpenterNodePool: "default",
+		labelKarpenterCapacity: "spot",
+		labelZoneLegacy:        "us-east-1b",
+	}}}
+	info = getEKSNodeInfo(karpenter)
+	if info.groupName() != "karpenter:default" || info.CapacityType != "SPOT" || info.Zone != "us-east-1b" {
+		t.Errorf("unexpected karpenter node info: %+v", info)
+	}
+}
+
+func TestGroupNodeSummaries(t *testing.T) {
+	nodes := []nodeSummary{
+		{Name: "a", Status: "Ready", KubeletVersion: "v1.29.0", CPUAllocatable: 2000, CPURequested: 1000, EKS: eksNodeInfo{Zone: "us-east-1a", CapacityType: "SPOT"}},
+		{Name: "b", Status: "NotReady", KubeletVersion: "v1.29.0", CPUAllocatable: 2000, CPURequested: 500, EKS: eksNodeInfo{Zone: "us-east-1a", CapacityType: "ON_DEMAND"}},
+		{Name: "c", Status: "Ready,SchedulingDisabled", KubeletVersion: "v1.28.5", EKS: eksNodeInfo{}},
+	}
+	groups := groupNodeSummaries(nodes, nodeGroupByZone)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if groups[0].Group != "<none>" || groups[0].Ready != 1 {
+		t.Errorf("unexpected first group: %+v", groups[0])
+	}
+	g := groups[1]
+	if g.Group != "us-east-1a" || g.Nodes != 2 || g.Ready != 1 || g.CPURequested != 1500 || g.CapacityTypes["SPOT"] != 1 {
+		t.Errorf("unexpected zone group: %+v", g)
+	}
+}
+
+func TestBuildNodeDetail(t *testing.T) {
+	node := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "ip-10-0-1-10"},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+				corev1.ResourcePods:   resource.MustParse("29"),
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1930m"),
+				corev1.ResourceMemory: resource.MustParse("3Gi"),
+				corev1.ResourcePods:   resource.MustParse("29"),
+			},
+			Images: []corev1.ContainerImage{
+				{Names: []string{"repo/app@sha256:abc", "repo/app:1.0"}, SizeBytes: 100},
+				{Names: []string{"repo/big:2.0"}, SizeBytes: 500},
+			},
+		},
+	}
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "web"},
+			Spec: corev1.PodSpec{NodeName: node.Name, Containers: []corev1.Container{
+				testContainer("app", "250m", "500m", "256Mi", "512Mi"),
+				testContainer("sidecar", "50m", "", "64Mi", "128Mi"),
+			}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{RestartCount: 2}, {RestartCount: 1}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "job"},
+			Spec:       corev1.PodSpec{NodeName: node.Name, Containers: []corev1.Container{testContainer("run", "1", "1", "", "")}},
+			Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+	}
+	podMetrics := []metricsv1beta1.PodMetrics{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "web"},
+		Containers: []metricsv1beta1.ContainerMetrics{testContainerMetrics("app", "120m", "200Mi"), testContainerMetrics("sidecar", "5m", "20Mi")},
+	}}
+	events := []clusterEvent{
+		{Kind: "Node", Name: node.Name, Reason: "NodeNotReady", Count: 1},
+		{Kind: "Node", Name: "other", Reason: "NodeNotReady", Count: 1},
+		{Kind: "Pod", Name: node.Name, Namespace: "prod", Reason: "BackOff", Count: 1},
+	}
+
+	d := buildNodeDetail(node, pods, nil, podMetrics, events)
+	if d.CPUCapacity != 2000 || d.CPUAllocatable != 1930 || d.PodsCapacity != 29 {
+		t.Errorf("unexpected capacity/allocatable: %+v", d)
+	}
+	// Los pods terminados no cuentan para los límites del nodo.
+	if d.CPULimits != 500 || d.MemoryLimits != 640*1024*1024 {
+		t.Errorf("unexpected limits cpu=%d mem=%d", d.CPULimits, d.MemoryLimits)
+	}
+	if len(d.PodDetails) != 2 || d.PodDetails[0].Name != "job" || d.PodDetails[1].Name != "web" {
+		t.Fatalf("unexpected pod order: %+v", d.PodDetails)
+	}
+	web := d.PodDetails[1]
+	if web.Restarts != 3 || web.CPUUsage == nil || *web.CPUUsage != 125 || web.CPULimit != 0 {
+		t.Errorf("unexpected web pod detail: %+v", web)
+	}
+	if d.PodDetails[0].CPUUsage != nil {
+		t.Errorf("pod without metrics should have nil usage")
+	}
+	if len(d.Images) != 2 || d.Images[0].Name != "repo/big:2.0" || d.Images[1].Name != "repo/app:1.0" {
+		t.Errorf("unexpected images: %+v", d.Images)
+	}
+	if len(d.Events) != 1 || d.Events[0].Name != node.Name || d.Events[0].Kind != "Node" {
+		t.Errorf("unexpected events: %+v", d.Events)
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// outputAPIVersion identifica la versión del esquema de la salida JSON/YAML. Se debe
+// incrementar si se elimina o cambia el significado de algún campo existente.
+const outputAPIVersion = "eks-review/v1"
+
+// outputHeader encabeza todos los documentos JSON/YAML para que los consumidores
+// puedan identificar el tipo de documento y la versión del esquema.
+type outputHeader struct {
+	APIVersion  string    `json:"apiVersion"`
+	Kind        string    `json:"kind"`
+	GeneratedAt time.Time `json:"generatedAt"`
+}
+
+// outputList es el documento genérico para listas de elementos.
+type outputList struct {
+	outputHeader
+	Items interface{} `json:"items"`
+}
+
+func newOutputHeader(kind string) outputHeader {
+	return outputHeader{APIVersion: outputAPIVersion, Kind: kind, GeneratedAt: time.Now().UTC()}
+}
+
+// newOutputList envuelve los elementos en un documento del tipo indicado.
+func newOutputList(kind string, items interface{}) outputList {
+	return outputList{outputHeader: newOutputHeader(kind), Items: items}
+}
+
+// isStructuredOutput indica si el formato es json o yaml.
+func isStructuredOutput(format string) bool {
+	format = strings.ToLower(format)
+	return format == "json" || format == "yaml"
+}
+
+// writeStructuredOutput escribe doc en JSON indentado o en YAML.
+func writeStructuredOutput(w io.Writer, format string, doc interface{}) error {
+	var (
+		data []byte
+		err  error
+	)
+	switch strings.ToLower(format) {
+	case "json":
+		data, err = json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error convirtiendo a JSON: %w", err)
+		}
+	case "yaml":
+		data, err = yaml.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("error convirtiendo a YAML: %w", err)
+		}
+	default:
+		return fmt.Errorf("formato de salida no soportado '%s'", format)
+	}
+	_, err = fmt.Fprintln(w, strings.TrimRight(string(data), "\n"))
+	return err
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestWriteStructuredOutput_Envelope(t *testing.T) {
+	var buf bytes.Buffer
+	doc := newOutputList("EventList", []clusterEvent{{Reason: "BackOff", Count: 2}})
+	if err := writeStructuredOutput(&buf, "json", doc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if decoded["apiVersion"] != outputAPIVersion || decoded["kind"] != "EventList" || decoded["generatedAt"] == nil {
+		t.Errorf("missing envelope fields: %v", decoded)
+	}
+	items, ok := decoded["items"].([]interface{})
+	if !ok || len(items) != 1 {
+		t.Fatalf("unexpected items: %v", decoded["items"])
+	}
+
+	buf.Reset()
+	report := struct {
+		outputHeader
+		versionReport
+	}{newOutputHeader("VersionReport"), versionReport{APIServerVersion: "v1.30.0"}}
+	if err := writeStructuredOutput(&buf, "YAML", report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"apiVersion: eks-review/v1", "kind: VersionReport", "apiServerVersion: v1.30.0"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("YAML output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	if err := writeStructuredOutput(&buf, "xml", doc); err == nil {
+		t.Errorf("expected error for unsupported format")
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// resourceTotals acumula requests y limits de CPU (milicores) y memoria (bytes).
+// Un límite a 0 significa que al menos un contenedor no tiene límite definido.
+type resourceTotals struct {
+	CPURequest int64 `json:"cpuRequestMillis"`
+	CPULimit   int64 `json:"cpuLimitMillis"`
+	MemRequest int64 `json:"memoryRequestBytes"`
+	MemLimit   int64 `json:"memoryLimitBytes"`
+}
+
+// containerResources devuelve los requests y limits declarados por un contenedor.
+func containerResources(c corev1.Container) resourceTotals {
+	var t resourceTotals
+	if q, ok := c.Resources.Requests[corev1.ResourceCPU]; ok {
+		t.CPURequest = q.MilliValue()
+	}
+	if q, ok := c.Resources.Requests[corev1.ResourceMemory]; ok {
+		t.MemRequest = q.Value()
+	}
+	if q, ok := c.Resources.Limits[corev1.ResourceCPU]; ok {
+		t.CPULimit = q.MilliValue()
+	}
+	if q, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+		t.MemLimit = q.Value()
+	}
+	// Sin request explícito, Kubernetes usa el límite como request.
+	if t.CPURequest == 0 {
+		t.CPURequest = t.CPULimit
+	}
+	if t.MemRequest == 0 {
+		t.MemRequest = t.MemLimit
+	}
+	return t
+}
+
+// podResources suma los requests y limits de los contenedores de un pod. Si algún
+// contenedor no define límite, el límite del pod queda sin definir (0).
+func podResources(pod corev1.Pod) resourceTotals {
+	var total resourceTotals
+	cpuUnbounded, memUnbounded := false, false
+	for _, c := range pod.Spec.Containers {
+		r := containerResources(c)
+		total.CPURequest += r.CPURequest
+		total.MemRequest += r.MemRequest
+		total.CPULimit += r.CPULimit
+		total.MemLimit += r.MemLimit
+		cpuUnbounded = cpuUnbounded || r.CPULimit == 0
+		memUnbounded = memUnbounded || r.MemLimit == 0
+	}
+	if cpuUnbounded {
+		total.CPULimit = 0
+	}
+	if memUnbounded {
+		total.MemLimit = 0
+	}
+	return total
+}
+
+// formatMilliCPU formatea milicores como lo hace kubectl top (ej. "250m").
+func formatMilliCPU(milli int64) string {
+	if milli == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%dm", milli)
+}
+
+// formatBytes formatea bytes en Mi, o Gi a partir de 10Gi.
+func formatBytes(b int64) string {
+	if b == 0 {
+		return "-"
+	}
+	const mi = 1024 * 1024
+	const gi = 1024 * mi
+	if b >= 10*gi {
+		return fmt.Sprintf("%.1fGi", float64(b)/gi)
+	}
+	return fmt.Sprintf("%dMi", b/mi)
+}
+
+// formatPercent devuelve used/base como porcentaje, o "N/A" si base es 0.
+func formatPercent(used, base int64) string {
+	if base <= 0 {
+		return "N/A"
+	}
+	return fmt.Sprintf("%.0f%%", float64(used)*100.0/float64(base))
+}
+
+// podSchedulingRequests calcula los requests que el scheduler reserva para un pod:
+// el máximo entre la suma de los contenedores y el mayor init container, más el overhead.
+func podSchedulingRequests(pod corev1.Pod) (cpuMilli, memBytes int64) {
+	for _, c := range pod.Spec.Containers {
+		r := containerResources(c)
+		cpuMilli += r.CPURequest
+		memBytes += r.MemRequest
+	}
+	for _, c := range pod.Spec.InitContainers {
+		r := containerResources(c)
+		if r.CPURequest > cpuMilli {
+			cpuMilli = r.CPURequest
+		}
+		if r.MemRequest > memBytes {
+			memBytes = r.MemRequest
+		}
+	}
+	if q, ok := pod.Spec.Overhead[corev1.ResourceCPU]; ok {
+		cpuMilli += q.MilliValue()
+	}
+	if q, ok := pod.Spec.Overhead[corev1.ResourceMemory]; ok {
+		memBytes += q.Value()
+	}
+	return cpuMilli, memBytes
+}
@@ -8,19 +8,40 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1" // Asegúrate que esta importación esté
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes" // Necesario para el tipo del parámetro clientset
 )
 
 var allNamespaces bool
 var targetNamespace string
+var statusMinScore int
+var statusPendingThreshold time.Duration
+var statusWarningWindow time.Duration
+var statusWorkers int
+var statusOutputFormat string
 
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Proporciona un resumen rápido del estado de los recursos.",
-	Long: `El comando status recupera un resumen de Pods, Deployments, Services,
-e Ingresses en un namespace dado o en todos los namespaces.`,
+	Long: `El comando status recupera un resumen de Pods, Deployments, StatefulSets,
+DaemonSets, Jobs, PersistentVolumeClaims, Services e Ingresses en un namespace
+dado o en todos los namespaces. Los recursos se recuperan en paralelo; si alguno
+no se puede leer (por ejemplo, por falta de permisos RBAC) el resto se muestra
+igualmente y los errores se resumen al final.
+
+Antes de las tablas muestra un resumen de salud con una puntuación de 0 a 100
+calculada a partir de los nodos NotReady, los pods en CrashLoopBackOff,
+ImagePullBackOff o Pending prolongado, los deployments, statefulsets y
+daemonsets sin todas sus réplicas listas y los eventos Warning recientes.
+
+Con --min-score el comando termina con código de salida 2 si la puntuación es
+inferior al mínimo indicado, lo que permite usarlo como control en CI.
+
+Con -o json|yaml imprime un documento StatusReport (apiVersion eks-review/v1)
+con la puntuación, las comprobaciones, un resumen de cada recurso y los errores.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		clients, err := GetKubeClients()
 		if err != nil {
@@ -28,21 +49,81 @@ e Ingresses en un namespace dado o en todos los namespaces.`,
 			os.Exit(1)
 		}
 
-		fmt.Println("Recuperando estado de recursos de Kubernetes...")
+		outputLower := strings.ToLower(statusOutputFormat)
+		if outputLower != "" && !isStructuredOutput(outputLower) {
+			fmt.Fprintf(os.Stderr, "Error: formato de salida no soportado '%s' (usa json o yaml).\n", statusOutputFormat)
+			os.Exit(1)
+		}
+		// Con salida JSON/YAML los mensajes informativos van a stderr.
+		info := os.Stdout
+		if isStructuredOutput(outputLower) {
+			info = os.Stderr
+		}
+
+		fmt.Fprintln(info, "Recuperando estado de recursos de Kubernetes...")
 		namespaceToList := GetEffectiveNamespace(targetNamespace, allNamespaces, "default", false)
 
 		if !allNamespaces && targetNamespace == "" && namespaceToList == "default" {
-			fmt.Fprintf(os.Stdout, "No se especificó namespace. Usando namespace '%s'. Use -n <namespace> o -A / --all-namespaces.\n", namespaceToList)
+			fmt.Fprintf(info, "No se especificó namespace. Usando namespace '%s'. Use -n <namespace> o -A / --all-namespaces.\n", namespaceToList)
 		} else if allNamespaces {
-			fmt.Fprintln(os.Stdout, "Recuperando estado de recursos de todos los namespaces.")
+			fmt.Fprintln(info, "Recuperando estado de recursos de todos los namespaces.")
 		} else if namespaceToList != "" {
-			fmt.Fprintf(os.Stdout, "Recuperando estado de recursos del namespace '%s'.\n", namespaceToList)
+			fmt.Fprintf(info, "Recuperando estado de recursos del namespace '%s'.\n", namespaceToList)
+		}
+
+		data, errs := collectStatusData(context.TODO(), clients.Core, namespaceToList, statusWorkers)
+		failed := failedResources(errs)
+
+		health := computeClusterHealth(data, healthOptions{
+			PendingThreshold: statusPendingThreshold,
+			WarningWindow:    statusWarningWindow,
+		}, time.Now())
+
+		if isStructuredOutput(outputLower) {
+			report := buildStatusReport(namespaceToList, data, health, errs)
+			if err := writeStructuredOutput(os.Stdout, outputLower, report); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			if health.Score < statusMinScore {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", describeHealthFailure(health, statusMinScore))
+				os.Exit(2)
+			}
+			return
+		}
+
+		printClusterHealth(health)
+
+		if !failed["pods"] {
+			printPods(data.Pods)
+		}
+		if !failed["deployments"] {
+			printDeployments(data.Deployments)
+		}
+		if !failed["statefulsets"] {
+			printStatefulSets(data.StatefulSets)
 		}
+		if !failed["daemonsets"] {
+			printDaemonSets(data.DaemonSets)
+		}
+		if !failed["jobs"] {
+			printJobs(data.Jobs)
+		}
+		if !failed["persistentvolumeclaims"] {
+			printPVCs(data.PVCs)
+		}
+		if !failed["services"] {
+			printServices(data.Services)
+		}
+		if !failed["ingresses"] {
+			printIngresses(data.Ingresses)
+		}
+		printCollectErrors(errs)
 
-		listPods(clients.Core, namespaceToList)
-		listDeployments(clients.Core, namespaceToList)
-		listServices(clients.Core, namespaceToList)
-		listIngresses(clients.Core, namespaceToList)
+		if health.Score < statusMinScore {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", describeHealthFailure(health, statusMinScore))
+			os.Exit(2)
+		}
 	},
 }
 
@@ -50,58 +131,51 @@ func init() {
 	monitorCmd.AddCommand(statusCmd)
 	statusCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Si es true, lista el/los objeto(s) solicitado(s) en todos los namespaces.")
 	statusCmd.Flags().StringVarP(&targetNamespace, "namespace", "n", "", "Si está presente, el ámbito del namespace para esta solicitud CLI.")
+	statusCmd.Flags().IntVar(&statusMinScore, "min-score", 0, "Terminar con código 2 si la puntuación de salud es inferior a este valor (0 = desactivado)")
+	statusCmd.Flags().DurationVar(&statusPendingThreshold, "pending-threshold", 5*time.Minute, "Tiempo a partir del cual un pod Pending se considera un problema")
+	statusCmd.Flags().DurationVar(&statusWarningWindow, "warning-window", time.Hour, "Ventana de tiempo para contar eventos Warning recientes")
+	statusCmd.Flags().StringVarP(&statusOutputFormat, "output", "o", "", "Formato de salida. Soportado: json, yaml")
+	statusCmd.Flags().IntVar(&statusWorkers, "workers", defaultStatusWorkers, "Número máximo de peticiones simultáneas al API server")
 }
 
-func listPods(clientset kubernetes.Interface, namespace string) {
-	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error listando pods: %v\n", err)
-		return
-	}
-
+func printPods(pods []corev1.Pod) {
 	fmt.Fprintln(os.Stdout, "\n--- Pods ---")
-	if len(pods.Items) == 0 {
+	if len(pods) == 0 {
 		fmt.Fprintln(os.Stdout, "No se encontraron pods.")
 		return
 	}
 
 	headers := []string{"NOMBRE", "NAMESPACE", "ESTADO", "REINICIOS", "EDAD"}
-	rows := make([][]string, 0, len(pods.Items))
+	rows := make([][]string, 0, len(pods))
 	if Verbose {
-		fmt.Fprintf(os.Stdout, "DEBUG: Pods encontrados: %d\n", len(pods.Items))
+		fmt.Fprintf(os.Stdout, "DEBUG: Pods encontrados: %d\n", len(pods))
 	}
 
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		restarts := 0
 		for _, cs := range pod.Status.ContainerStatuses {
 			restarts += int(cs.RestartCount)
 		}
 		age := metav1.Now().Sub(pod.CreationTimestamp.Time).Truncate(time.Second).String()
-		rows = append(rows, []string{pod.Name, pod.Namespace, string(pod.Status.Phase), fmt.Sprintf("%d", restarts), age})
+		rows = append(rows, []string{pod.Name, pod.Namespace, podDisplayStatus(pod), fmt.Sprintf("%d", restarts), age})
 	}
 	PrintBasicTable(headers, rows)
 }
 
-func listDeployments(clientset kubernetes.Interface, namespace string) {
-	deployments, err := clientset.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error listando deployments: %v\n", err)
-		return
-	}
-
+func printDeployments(deployments []appsv1.Deployment) {
 	fmt.Fprintln(os.Stdout, "\n--- Deployments ---")
-	if len(deployments.Items) == 0 {
+	if len(deployments) == 0 {
 		fmt.Fprintln(os.Stdout, "No se encontraron deployments.")
 		return
 	}
 
 	headers := []string{"NOMBRE", "NAMESPACE", "LISTOS", "ACTUALIZADOS", "DISPONIBLES", "EDAD"}
-	rows := make([][]string, 0, len(deployments.Items))
+	rows := make([][]string, 0, len(deployments))
 	if Verbose {
-		fmt.Fprintf(os.Stdout, "DEBUG: Deployments encontrados: %d\n", len(deployments.Items))
+		fmt.Fprintf(os.Stdout, "DEBUG: Deployments encontrados: %d\n", len(deployments))
 	}
 
-	for _, deploy := range deployments.Items {
+	for _, deploy := range deployments {
 		readyReplicas := int32(0)
 		if deploy.Spec.Replicas != nil { // deploy.Spec.Replicas es un puntero
 			readyReplicas = *deploy.Spec.Replicas
@@ -115,101 +189,124 @@ func listDeployments(clientset kubernetes.Interface, namespace string) {
 	PrintBasicTable(headers, rows)
 }
 
-func listServices(clientset kubernetes.Interface, namespace string) {
-	services, err := clientset.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error listando services: %v\n", err)
+func printStatefulSets(statefulSets []appsv1.StatefulSet) {
+	fmt.Fprintln(os.Stdout, "\n--- StatefulSets ---")
+	if len(statefulSets) == 0 {
+		fmt.Fprintln(os.Stdout, "No se encontraron statefulsets.")
+		return
+	}
+
+	headers := []string{"NOMBRE", "NAMESPACE", "LISTOS", "ACTUALIZADOS", "EDAD"}
+	rows := make([][]string, 0, len(statefulSets))
+	for _, sts := range statefulSets {
+		ready := fmt.Sprintf("%d/%d", sts.Status.ReadyReplicas, replicasOrDefault(sts.Spec.Replicas))
+		age := metav1.Now().Sub(sts.CreationTimestamp.Time).Truncate(time.Second).String()
+		rows = append(rows, []string{sts.Name, sts.Namespace, ready, fmt.Sprintf("%d", sts.Status.UpdatedReplicas), age})
+	}
+	PrintBasicTable(headers, rows)
+}
+
+func printDaemonSets(daemonSets []appsv1.DaemonSet) {
+	fmt.Fprintln(os.Stdout, "\n--- DaemonSets ---")
+	if len(daemonSets) == 0 {
+		fmt.Fprintln(os.Stdout, "No se encontraron daemonsets.")
+		return
+	}
+
+	headers := []string{"NOMBRE", "NAMESPACE", "DESEADOS", "LISTOS", "ACTUALIZADOS", "DISPONIBLES", "EDAD"}
+	rows := make([][]string, 0, len(daemonSets))
+	for _, ds := range daemonSets {
+		age := metav1.Now().Sub(ds.CreationTimestamp.Time).Truncate(time.Second).String()
+		rows = append(rows, []string{
+			ds.Name, ds.Namespace,
+			fmt.Sprintf("%d", ds.Status.DesiredNumberScheduled),
+			fmt.Sprintf("%d", ds.Status.NumberReady),
+			fmt.Sprintf("%d", ds.Status.UpdatedNumberScheduled),
+			fmt.Sprintf("%d", ds.Status.NumberAvailable),
+			age,
+		})
+	}
+	PrintBasicTable(headers, rows)
+}
+
+func printJobs(jobs []batchv1.Job) {
+	fmt.Fprintln(os.Stdout, "\n--- Jobs ---")
+	if len(jobs) == 0 {
+		fmt.Fprintln(os.Stdout, "No se encontraron jobs.")
+		return
+	}
+
+	headers := []string{"NOMBRE", "NAMESPACE", "COMPLETADOS", "ESTADO", "EDAD"}
+	rows := make([][]string, 0, len(jobs))
+	for _, job := range jobs {
+		age := metav1.Now().Sub(job.CreationTimestamp.Time).Truncate(time.Second).String()
+		rows = append(rows, []string{job.Name, job.Namespace, fmt.Sprintf("%d/%d", job.Status.Succeeded, jobCompletions(job)), jobState(job), age})
+	}
+	PrintBasicTable(headers, rows)
+}
+
+func printPVCs(pvcs []corev1.PersistentVolumeClaim) {
+	fmt.Fprintln(os.Stdout, "\n--- PersistentVolumeClaims ---")
+	if len(pvcs) == 0 {
+		fmt.Fprintln(os.Stdout, "No se encontraron persistentvolumeclaims.")
 		return
 	}
 
+	headers := []string{"NOMBRE", "NAMESPACE", "ESTADO", "VOLUMEN", "CAPACIDAD", "MODOS", "STORAGECLASS", "EDAD"}
+	rows := make([][]string, 0, len(pvcs))
+	for _, pvc := range pvcs {
+		capacity := "<none>"
+		if q, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+			capacity = q.String()
+		}
+		var modes []string
+		for _, m := range pvc.Spec.AccessModes {
+			modes = append(modes, string(m))
+		}
+		storageClass := "<none>"
+		if pvc.Spec.StorageClassName != nil {
+			storageClass = *pvc.Spec.StorageClassName
+		}
+		age := metav1.Now().Sub(pvc.CreationTimestamp.Time).Truncate(time.Second).String()
+		rows = append(rows, []string{pvc.Name, pvc.Namespace, string(pvc.Status.Phase), valueOrNone(pvc.Spec.VolumeName), capacity, strings.Join(modes, ","), storageClass, age})
+	}
+	PrintBasicTable(headers, rows)
+}
+
+func printServices(services []corev1.Service) {
 	fmt.Fprintln(os.Stdout, "\n--- Services ---")
-	if len(services.Items) == 0 {
+	if len(services) == 0 {
 		fmt.Fprintln(os.Stdout, "No se encontraron services.")
 		return
 	}
 
 	headers := []string{"NOMBRE", "NAMESPACE", "TIPO", "CLUSTER-IP", "IP-EXTERNA", "PUERTO(S)", "EDAD"}
-	rows := make([][]string, 0, len(services.Items))
+	rows := make([][]string, 0, len(services))
 	if Verbose {
-		fmt.Fprintf(os.Stdout, "DEBUG: Services encontrados: %d\n", len(services.Items))
-	}
-
-	for _, svc := range services.Items {
-		externalIP := "<none>"
-		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer { // Usa corev1 aquí
-			if len(svc.Status.LoadBalancer.Ingress) > 0 {
-				if svc.Status.LoadBalancer.Ingress[0].IP != "" {
-					externalIP = svc.Status.LoadBalancer.Ingress[0].IP
-				} else if svc.Status.LoadBalancer.Ingress[0].Hostname != "" {
-					externalIP = svc.Status.LoadBalancer.Ingress[0].Hostname
-				} else {
-					externalIP = "<pending>"
-				}
-			} else {
-				externalIP = "<pending>"
-			}
-		} else if len(svc.Spec.ExternalIPs) > 0 {
-			externalIP = strings.Join(svc.Spec.ExternalIPs, ",")
-		}
+		fmt.Fprintf(os.Stdout, "DEBUG: Services encontrados: %d\n", len(services))
+	}
 
-		var portStrings []string
-		for _, port := range svc.Spec.Ports {
-			pStr := fmt.Sprintf("%d", port.Port)
-			if port.NodePort > 0 {
-				pStr += fmt.Sprintf(":%d", port.NodePort)
-			}
-			pStr += fmt.Sprintf("/%s", port.Protocol)
-			portStrings = append(portStrings, pStr)
-		}
+	for _, svc := range services {
 		age := metav1.Now().Sub(svc.CreationTimestamp.Time).Truncate(time.Second).String()
-		rows = append(rows, []string{svc.Name, svc.Namespace, string(svc.Spec.Type), svc.Spec.ClusterIP, externalIP, strings.Join(portStrings, ","), age})
+		rows = append(rows, []string{svc.Name, svc.Namespace, string(svc.Spec.Type), svc.Spec.ClusterIP, serviceExternalIP(svc), strings.Join(servicePorts(svc), ","), age})
 	}
 	PrintBasicTable(headers, rows)
 }
 
-func listIngresses(clientset kubernetes.Interface, namespace string) {
-	ingresses, err := clientset.NetworkingV1().Ingresses(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error listando ingresses: %v\n", err)
-		return
-	}
-
+func printIngresses(ingresses []networkingv1.Ingress) {
 	fmt.Fprintln(os.Stdout, "\n--- Ingresses ---")
-	if len(ingresses.Items) == 0 {
+	if len(ingresses) == 0 {
 		fmt.Fprintln(os.Stdout, "No se encontraron ingresses.")
 		return
 	}
 
 	headers := []string{"NOMBRE", "NAMESPACE", "CLASE", "HOSTS", "DIRECCIÓN", "PUERTOS", "EDAD"}
-	rows := make([][]string, 0, len(ingresses.Items))
+	rows := make([][]string, 0, len(ingresses))
 	if Verbose {
-		fmt.Fprintf(os.Stdout, "DEBUG: Ingresses encontrados: %d\n", len(ingresses.Items))
-	}
-
-	for _, ingress := range ingresses.Items {
-		address := "<none>"
-		if len(ingress.Status.LoadBalancer.Ingress) > 0 {
-			var addresses []string
-			for _, ingStatus := range ingress.Status.LoadBalancer.Ingress {
-				if ingStatus.IP != "" {
-					addresses = append(addresses, ingStatus.IP)
-				}
-				if ingStatus.Hostname != "" {
-					addresses = append(addresses, ingStatus.Hostname)
-				}
-			}
-			if len(addresses) > 0 {
-				address = strings.Join(addresses, ",")
-			} else {
-				address = "<pending>"
-			}
-		}
-
-		var hosts []string
-		for _, rule := range ingress.Spec.Rules {
-			hosts = append(hosts, rule.Host)
-		}
+		fmt.Fprintf(os.Stdout, "DEBUG: Ingresses encontrados: %d\n", len(ingresses))
+	}
 
+	for _, ingress := range ingresses {
 		className := ""
 		if ingress.Spec.IngressClassName != nil {
 			className = *ingress.Spec.IngressClassName
@@ -221,7 +318,7 @@ func listIngresses(clientset kubernetes.Interface, namespace string) {
 		// Es difícil extraer esto de forma genérica del objeto Ingress.
 		portStr := "80, 443" // Placeholder o puedes intentar lógica más compleja.
 
-		rows = append(rows, []string{ingress.Name, ingress.Namespace, className, strings.Join(hosts, ","), address, portStr, age})
+		rows = append(rows, []string{ingress.Name, ingress.Namespace, className, strings.Join(ingressHosts(ingress), ","), ingressAddress(ingress), portStr, age})
 	}
 	PrintBasicTable(headers, rows)
 }
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultStatusWorkers es el número de peticiones simultáneas al API server.
+const defaultStatusWorkers = 4
+
+// statusData contiene todos los recursos que muestra monitor status.
+type statusData struct {
+	Nodes        []corev1.Node
+	Pods         []corev1.Pod
+	Deployments  []appsv1.Deployment
+	StatefulSets []appsv1.StatefulSet
+	DaemonSets   []appsv1.DaemonSet
+	Jobs         []batchv1.Job
+	PVCs         []corev1.PersistentVolumeClaim
+	Services     []corev1.Service
+	Ingresses    []networkingv1.Ingress
+	Events       []clusterEvent
+}
+
+// collectError indica que no se pudo recuperar un tipo de recurso.
+type collectError struct {
+	Resource string
+	Err      error
+}
+
+// statusTask recupera un tipo de recurso y lo guarda en su campo de statusData.
+type statusTask struct {
+	resource string
+	run      func(ctx context.Context) error
+}
+
+// statusTasks devuelve las tareas de recogida de datos. Cada una escribe en un campo
+// distinto de data, por lo que pueden ejecutarse en paralelo sin sincronización.
+func statusTasks(clientset kubernetes.Interface, namespace string, data *statusData) []statusTask {
+	return []statusTask{
+		{"nodes", func(ctx context.Context) error {
+			l, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+			if err == nil {
+				data.Nodes = l.Items
+			}
+			return err
+		}},
+		{"pods", func(ctx context.Context) error {
+			l, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+			if err == nil {
+				data.Pods = l.Items
+			}
+			return err
+		}},
+		{"deployments", func(ctx context.Context) error {
+			l, err := clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+			if err == nil {
+				data.Deployments = l.Items
+			}
+			return err
+		}},
+		{"statefulsets", func(ctx context.Context) error {
+			l, err := clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+			if err == nil {
+				data.StatefulSets = l.Items
+			}
+			return err
+		}},
+		{"daemonsets", func(ctx context.Context) error {
+			l, err := clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+			if err == nil {
+				data.DaemonSets = l.Items
+			}
+			return err
+		}},
+		{"jobs", func(ctx context.Context) error {
+			l, err := clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+			if err == nil {
+				data.Jobs = l.Items
+			}
+			return err
+		}},
+		{"persistentvolumeclaims", func(ctx context.Context) error {
+			l, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+			if err == nil {
+				data.PVCs = l.Items
+			}
+			return err
+		}},
+		{"services", func(ctx context.Context) error {
+			l, err := clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+			if err == nil {
+				data.Services = l.Items
+			}
+			return err
+		}},
+		{"ingresses", func(ctx context.Context) error {
+			l, err := clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+			if err == nil {
+				data.Ingresses = l.Items
+			}
+			return err
+		}},
+		{"events", func(ctx context.Context) error {
+			events, _, _, err := listEventsWithFallback(ctx, clientset, namespace)
+			if err == nil {
+				data.Events = events
+			}
+			return err
+		}},
+	}
+}
+
+// collectStatusData recupera todos los recursos con un máximo de workers peticiones
+// simultáneas. Los fallos de un tipo de recurso no detienen al resto: se devuelven
+// ordenados por recurso para mostrarlos en un resumen.
+func collectStatusData(ctx context.Context, clientset kubernetes.Interface, namespace string, workers int) (statusData, []collectError) {
+	var data statusData
+	tasks := statusTasks(clientset, namespace, &data)
+	if workers < 1 {
+		workers = 1
+	}
+
+	queue := make(chan statusTask)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []collectError
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range queue {
+				if err := task.run(ctx); err != nil {
+					mu.Lock()
+					errs = append(errs, collectError{Resource: task.resource, Err: err})
+					mu.Unlock()
+				} else if Verbose {
+					fmt.Fprintf(os.Stderr, "DEBUG: %s recuperados\n", task.resource)
+				}
+			}
+		}()
+	}
+	for _, task := range tasks {
+		queue <- task
+	}
+	close(queue)
+	wg.Wait()
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Resource < errs[j].Resource })
+	return data, errs
+}
+
+// failedResources devuelve el conjunto de recursos que no se pudieron recuperar.
+func failedResources(errs []collectError) map[string]bool {
+	failed := make(map[string]bool, len(errs))
+	for _, e := range errs {
+		failed[e.Resource] = true
+	}
+	return failed
+}
+
+// describeCollectError resume la causa de un error de la API en una frase corta.
+func describeCollectError(err error) string {
+	switch {
+	case apierrors.IsForbidden(err):
+		return "sin permisos (RBAC forbidden)"
+	case apierrors.IsUnauthorized(err):
+		return "credenciales no válidas o caducadas"
+	case apierrors.IsNotFound(err):
+		return "API no disponible en este clúster"
+	case apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err):
+		return "tiempo de espera agotado"
+	}
+	return err.Error()
+}
+
+// printCollectErrors imprime en stderr el resumen de recursos que no se pudieron recuperar.
+func printCollectErrors(errs []collectError) {
+	if len(errs) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\nAdvertencia: no se pudieron recuperar %d %s; la puntuación de salud se calculó con datos incompletos:\n",
+		len(errs), pluralize(len(errs), "tipo de recurso", "tipos de recurso"))
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "  - %s: %s\n", e.Resource, describeCollectError(e.Err))
+		if Verbose {
+			fmt.Fprintf(os.Stderr, "    DEBUG: %v\n", e.Err)
+		}
+	}
+}
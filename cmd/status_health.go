@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Severidades de las comprobaciones de salud.
+const (
+	healthCritical = "critical"
+	healthWarning  = "warning"
+)
+
+// healthExamples es el número de objetos de ejemplo que se muestran por comprobación.
+const healthExamples = 3
+
+// healthOptions configura los umbrales de las comprobaciones.
+type healthOptions struct {
+	PendingThreshold time.Duration
+	WarningWindow    time.Duration
+}
+
+// healthCheck es el resultado de una comprobación: cuántos objetos la incumplen y
+// cuántos puntos resta a la puntuación.
+type healthCheck struct {
+	Name     string   `json:"name"`
+	Severity string   `json:"severity"`
+	Count    int      `json:"count"`
+	Penalty  int      `json:"penalty"`
+	Objects  []string `json:"objects,omitempty"`
+}
+
+// clusterHealth es la puntuación global (0-100) y el detalle de cada comprobación.
+type clusterHealth struct {
+	Score  int           `json:"score"`
+	Checks []healthCheck `json:"checks"`
+}
+
+// healthRule define cuánto resta cada objeto afectado y el máximo por comprobación,
+// para que un único tipo de problema no oculte el resto.
+type healthRule struct {
+	name      string
+	severity  string
+	weight    int
+	maxWeight int
+}
+
+var (
+	ruleNodesNotReady   = healthRule{"Nodos NotReady", healthCritical, 10, 40}
+	ruleCrashLoop       = healthRule{"Pods en CrashLoopBackOff", healthCritical, 5, 30}
+	ruleImagePull       = healthRule{"Pods en ImagePullBackOff", healthCritical, 5, 20}
+	rulePendingPods     = healthRule{"Pods Pending prolongado", healthWarning, 3, 15}
+	ruleMissingReplicas = healthRule{"Workloads sin réplicas listas", healthCritical, 5, 30}
+	ruleRecentWarnings  = healthRule{"Eventos Warning recientes", healthWarning, 1, 10}
+)
+
+// newHealthCheck aplica la regla a los objetos afectados.
+func newHealthCheck(r healthRule, objects []string) healthCheck {
+	sort.Strings(objects)
+	penalty := r.weight * len(objects)
+	if penalty > r.maxWeight {
+		penalty = r.maxWeight
+	}
+	return healthCheck{Name: r.name, Severity: r.severity, Count: len(objects), Penalty: penalty, Objects: objects}
+}
+
+// computeClusterHealth evalúa las comprobaciones y calcula la puntuación de salud.
+func computeClusterHealth(in statusData, opts healthOptions, now time.Time) clusterHealth {
+	var notReady []string
+	for _, n := range in.Nodes {
+		if getNodeStatus(n) != "Ready" {
+			notReady = append(notReady, n.Name)
+		}
+	}
+
+	var crashLoop, imagePull, pending []string
+	for _, p := range in.Pods {
+		name := p.Namespace + "/" + p.Name
+		switch reason := podWaitingReason(p); {
+		case reason == "CrashLoopBackOff":
+			crashLoop = append(crashLoop, name)
+		case reason == "ImagePullBackOff" || reason == "ErrImagePull":
+			imagePull = append(imagePull, name)
+		case p.Status.Phase == corev1.PodPending && now.Sub(p.CreationTimestamp.Time) > opts.PendingThreshold:
+			pending = append(pending, name)
+		}
+	}
+
+	var missing []string
+	addMissing := func(kind, namespace, name string, ready, desired int32) {
+		if ready < desired {
+			missing = append(missing, fmt.Sprintf("%s %s/%s (%d/%d)", kind, namespace, name, ready, desired))
+		}
+	}
+	for _, d := range in.Deployments {
+		addMissing("Deployment", d.Namespace, d.Name, d.Status.ReadyReplicas, replicasOrDefault(d.Spec.Replicas))
+	}
+	for _, s := range in.StatefulSets {
+		addMissing("StatefulSet", s.Namespace, s.Name, s.Status.ReadyReplicas, replicasOrDefault(s.Spec.Replicas))
+	}
+	for _, d := range in.DaemonSets {
+		addMissing("DaemonSet", d.Namespace, d.Name, d.Status.NumberReady, d.Status.DesiredNumberScheduled)
+	}
+
+	var warnings []string
+	recent := dedupeEvents(filterEvents(in.Events, eventFilter{Type: "Warning", Since: opts.WarningWindow}, now))
+	for _, e := range recent {
+		warnings = append(warnings, fmt.Sprintf("%s/%s: %s", e.Kind, e.Name, e.Reason))
+	}
+
+	h := clusterHealth{Checks: []healthCheck{
+		newHealthCheck(ruleNodesNotReady, notReady),
+		newHealthCheck(ruleCrashLoop, crashLoop),
+		newHealthCheck(ruleImagePull, imagePull),
+		newHealthCheck(rulePendingPods, pending),
+		newHealthCheck(ruleMissingReplicas, missing),
+		newHealthCheck(ruleRecentWarnings, warnings),
+	}}
+	h.Score = 100
+	for _, c := range h.Checks {
+		h.Score -= c.Penalty
+	}
+	if h.Score < 0 {
+		h.Score = 0
+	}
+	return h
+}
+
+// replicasOrDefault devuelve las réplicas deseadas; Kubernetes usa 1 si no se indican.
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// podWaitingReason devuelve el motivo de espera del primer contenedor (incluidos los
+// init containers) que no ha podido arrancar, o "" si no hay ninguno.
+func podWaitingReason(pod corev1.Pod) string {
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, cs := range statuses {
+			if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && cs.State.Waiting.Reason != "PodInitializing" && cs.State.Waiting.Reason != "ContainerCreating" {
+				return cs.State.Waiting.Reason
+			}
+		}
+	}
+	return ""
+}
+
+// podDisplayStatus devuelve el estado del pod como lo muestra kubectl: el motivo de
+// espera o de terminación de un contenedor si existe, o la fase del pod.
+func podDisplayStatus(pod corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	if reason := podWaitingReason(pod); reason != "" {
+		return reason
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" && pod.Status.Phase != corev1.PodSucceeded {
+			return cs.State.Terminated.Reason
+		}
+	}
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+	return string(pod.Status.Phase)
+}
+
+// scoreColor devuelve el color de la puntuación: verde, amarillo o rojo.
+func scoreColor(score int) string {
+	switch {
+	case score >= 90:
+		return colorGreen
+	case score >= 70:
+		return colorYellow
+	}
+	return colorRed
+}
+
+// printClusterHealth imprime la sección de resumen con la puntuación y las comprobaciones.
+func printClusterHealth(h clusterHealth) {
+	fmt.Fprintln(os.Stdout, "\n--- Resumen de salud ---")
+	fmt.Fprintln(os.Stdout, colorize(fmt.Sprintf("Puntuación de salud: %d/100", h.Score), scoreColor(h.Score)))
+	for _, c := range h.Checks {
+		mark, color := "OK", colorGreen
+		if c.Count > 0 {
+			mark, color = "!!", colorYellow
+			if c.Severity == healthCritical {
+				color = colorRed
+			}
+		}
+		line := fmt.Sprintf("  [%s] %-33s %4d", mark, c.Name+":", c.Count)
+		if c.Count > 0 {
+			line += fmt.Sprintf("  (-%d)  %s", c.Penalty, formatLimitedList(c.Objects, healthExamples))
+		}
+		fmt.Fprintln(os.Stdout, colorize(line, color))
+	}
+}
+
+// describeHealthFailure explica por qué la puntuación no alcanza el mínimo exigido.
+func describeHealthFailure(h clusterHealth, minScore int) string {
+	var failing []string
+	for _, c := range h.Checks {
+		if c.Count > 0 {
+			failing = append(failing, fmt.Sprintf("%s (%d)", c.Name, c.Count))
+		}
+	}
+	return fmt.Sprintf("la puntuación de salud %d es inferior al mínimo %d: %s", h.Score, minScore, strings.Join(failing, ", "))
+}
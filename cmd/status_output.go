@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// statusReport es el documento de monitor status -o json|yaml. Una lista es null
+// cuando ese tipo de recurso no se pudo recuperar; el motivo aparece en errors.
+type statusReport struct {
+	outputHeader
+	Namespace              string                  `json:"namespace"`
+	Health                 clusterHealth           `json:"health"`
+	Pods                   []podStatusSummary      `json:"pods"`
+	Deployments            []workloadStatusSummary `json:"deployments"`
+	StatefulSets           []workloadStatusSummary `json:"statefulSets"`
+	DaemonSets             []workloadStatusSummary `json:"daemonSets"`
+	Jobs                   []jobStatusSummary      `json:"jobs"`
+	PersistentVolumeClaims []pvcStatusSummary      `json:"persistentVolumeClaims"`
+	Services               []serviceStatusSummary  `json:"services"`
+	Ingresses              []ingressStatusSummary  `json:"ingresses"`
+	Errors                 []statusError           `json:"errors"`
+}
+
+type podStatusSummary struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Phase     string    `json:"phase"`
+	Restarts  int32     `json:"restarts"`
+	Node      string    `json:"node,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// workloadStatusSummary sirve para Deployments, StatefulSets y DaemonSets.
+type workloadStatusSummary struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Desired   int32     `json:"desired"`
+	Ready     int32     `json:"ready"`
+	Updated   int32     `json:"updated"`
+	Available int32     `json:"available"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type jobStatusSummary struct {
+	Namespace   string    `json:"namespace"`
+	Name        string    `json:"name"`
+	Completions int32     `json:"completions"`
+	Succeeded   int32     `json:"succeeded"`
+	Failed      int32     `json:"failed"`
+	State       string    `json:"state"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type pvcStatusSummary struct {
+	Namespace    string    `json:"namespace"`
+	Name         string    `json:"name"`
+	Phase        string    `json:"phase"`
+	Volume       string    `json:"volume,omitempty"`
+	Capacity     string    `json:"capacity,omitempty"`
+	AccessModes  []string  `json:"accessModes"`
+	StorageClass string    `json:"storageClass,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type serviceStatusSummary struct {
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	ClusterIP  string    `json:"clusterIP"`
+	ExternalIP string    `json:"externalIP"`
+	Ports      []string  `json:"ports"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type ingressStatusSummary struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Class     string    `json:"class,omitempty"`
+	Hosts     []string  `json:"hosts"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type statusError struct {
+	Resource string `json:"resource"`
+	Message  string `json:"message"`
+}
+
+// buildStatusReport convierte los recursos recuperados en el documento de salida.
+func buildStatusReport(namespace string, data statusData, health clusterHealth, errs []collectError) statusReport {
+	failed := failedResources(errs)
+	r := statusReport{
+		outputHeader: newOutputHeader("StatusReport"),
+		Namespace:    namespace,
+		Health:       health,
+		Errors:       []statusError{},
+	}
+	for _, e := range errs {
+		r.Errors = append(r.Errors, statusError{Resource: e.Resource, Message: describeCollectError(e.Err)})
+	}
+
+	if !failed["pods"] {
+		r.Pods = make([]podStatusSummary, 0, len(data.Pods))
+		for _, p := range data.Pods {
+			var restarts int32
+			for _, cs := range p.Status.ContainerStatuses {
+				restarts += cs.RestartCount
+			}
+			r.Pods = append(r.Pods, podStatusSummary{
+				Namespace: p.Namespace, Name: p.Name, Status: podDisplayStatus(p), Phase: string(p.Status.Phase),
+				Restarts: restarts, Node: p.Spec.NodeName, CreatedAt: p.CreationTimestamp.UTC(),
+			})
+		}
+	}
+	if !failed["deployments"] {
+		r.Deployments = make([]workloadStatusSummary, 0, len(data.Deployments))
+		for _, d := range data.Deployments {
+			r.Deployments = append(r.Deployments, workloadStatusSummary{
+				Namespace: d.Namespace, Name: d.Name, Desired: replicasOrDefault(d.Spec.Replicas),
+				Ready: d.Status.ReadyReplicas, Updated: d.Status.UpdatedReplicas, Available: d.Status.AvailableReplicas,
+				CreatedAt: d.CreationTimestamp.UTC(),
+			})
+		}
+	}
+	if !failed["statefulsets"] {
+		r.StatefulSets = make([]workloadStatusSummary, 0, len(data.StatefulSets))
+		for _, s := range data.StatefulSets {
+			r.StatefulSets = append(r.StatefulSets, workloadStatusSummary{
+				Namespace: s.Namespace, Name: s.Name, Desired: replicasOrDefault(s.Spec.Replicas),
+				Ready: s.Status.ReadyReplicas, Updated: s.Status.UpdatedReplicas, Available: s.Status.AvailableReplicas,
+				CreatedAt: s.CreationTimestamp.UTC(),
+			})
+		}
+	}
+	if !failed["daemonsets"] {
+		r.DaemonSets = make([]workloadStatusSummary, 0, len(data.DaemonSets))
+		for _, d := range data.DaemonSets {
+			r.DaemonSets = append(r.DaemonSets, daemonSetSummary(d))
+		}
+	}
+	if !failed["jobs"] {
+		r.Jobs = make([]jobStatusSummary, 0, len(data.Jobs))
+		for _, j := range data.Jobs {
+			r.Jobs = append(r.Jobs, jobStatusSummary{
+				Namespace: j.Namespace, Name: j.Name, Completions: jobCompletions(j),
+				Succeeded: j.Status.Succeeded, Failed: j.Status.Failed, State: jobState(j),
+				CreatedAt: j.CreationTimestamp.UTC(),
+			})
+		}
+	}
+	if !failed["persistentvolumeclaims"] {
+		r.PersistentVolumeClaims = make([]pvcStatusSummary, 0, len(data.PVCs))
+		for _, pvc := range data.PVCs {
+			s := pvcStatusSummary{
+				Namespace: pvc.Namespace, Name: pvc.Name, Phase: string(pvc.Status.Phase), Volume: pvc.Spec.VolumeName,
+				AccessModes: []string{}, CreatedAt: pvc.CreationTimestamp.UTC(),
+			}
+			if q, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+				s.Capacity = q.String()
+			}
+			for _, m := range pvc.Spec.AccessModes {
+				s.AccessModes = append(s.AccessModes, string(m))
+			}
+			if pvc.Spec.StorageClassName != nil {
+				s.StorageClass = *pvc.Spec.StorageClassName
+			}
+			r.PersistentVolumeClaims = append(r.PersistentVolumeClaims, s)
+		}
+	}
+	if !failed["services"] {
+		r.Services = make([]serviceStatusSummary, 0, len(data.Services))
+		for _, svc := range data.Services {
+			r.Services = append(r.Services, serviceStatusSummary{
+				Namespace: svc.Namespace, Name: svc.Name, Type: string(svc.Spec.Type), ClusterIP: svc.Spec.ClusterIP,
+				ExternalIP: serviceExternalIP(svc), Ports: servicePorts(svc), CreatedAt: svc.CreationTimestamp.UTC(),
+			})
+		}
+	}
+	if !failed["ingresses"] {
+		r.Ingresses = make([]ingressStatusSummary, 0, len(data.Ingresses))
+		for _, ing := range data.Ingresses {
+			s := ingressStatusSummary{
+				Namespace: ing.Namespace, Name: ing.Name, Hosts: ingressHosts(ing), Address: ingressAddress(ing),
+				CreatedAt: ing.CreationTimestamp.UTC(),
+			}
+			if ing.Spec.IngressClassName != nil {
+				s.Class = *ing.Spec.IngressClassName
+			}
+			r.Ingresses = append(r.Ingresses, s)
+		}
+	}
+	return r
+}
+
+func daemonSetSummary(d appsv1.DaemonSet) workloadStatusSummary {
+	return workloadStatusSummary{
+		Namespace: d.Namespace, Name: d.Name, Desired: d.Status.DesiredNumberScheduled,
+		Ready: d.Status.NumberReady, Updated: d.Status.UpdatedNumberScheduled, Available: d.Status.NumberAvailable,
+		CreatedAt: d.CreationTimestamp.UTC(),
+	}
+}
+
+// jobCompletions devuelve las finalizaciones requeridas; Kubernetes usa 1 si no se indican.
+func jobCompletions(job batchv1.Job) int32 {
+	return replicasOrDefault(job.Spec.Completions)
+}
+
+// jobState resume el estado de un Job: Complete, Failed, Suspended, Running o Pending.
+func jobState(job batchv1.Job) string {
+	state := "Running"
+	if job.Status.Active == 0 {
+		state = "Pending"
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status == corev1.ConditionTrue && (cond.Type == batchv1.JobComplete || cond.Type == batchv1.JobFailed || cond.Type == batchv1.JobSuspended) {
+			state = string(cond.Type)
+		}
+	}
+	return state
+}
+
+// serviceExternalIP devuelve la IP o el hostname externo del Service, "<pending>"
+// si el LoadBalancer aún no tiene dirección o "<none>" si no aplica.
+func serviceExternalIP(svc corev1.Service) string {
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		if len(svc.Status.LoadBalancer.Ingress) > 0 {
+			if svc.Status.LoadBalancer.Ingress[0].IP != "" {
+				return svc.Status.LoadBalancer.Ingress[0].IP
+			} else if svc.Status.LoadBalancer.Ingress[0].Hostname != "" {
+				return svc.Status.LoadBalancer.Ingress[0].Hostname
+			}
+		}
+		return "<pending>"
+	}
+	if len(svc.Spec.ExternalIPs) > 0 {
+		return strings.Join(svc.Spec.ExternalIPs, ",")
+	}
+	return "<none>"
+}
+
+// servicePorts devuelve los puertos en formato puerto[:nodePort]/protocolo.
+func servicePorts(svc corev1.Service) []string {
+	ports := []string{}
+	for _, port := range svc.Spec.Ports {
+		pStr := fmt.Sprintf("%d", port.Port)
+		if port.NodePort > 0 {
+			pStr += fmt.Sprintf(":%d", port.NodePort)
+		}
+		pStr += fmt.Sprintf("/%s", port.Protocol)
+		ports = append(ports, pStr)
+	}
+	return ports
+}
+
+// ingressAddress devuelve las direcciones del balanceador del Ingress.
+func ingressAddress(ingress networkingv1.Ingress) string {
+	if len(ingress.Status.LoadBalancer.Ingress) == 0 {
+		return "<none>"
+	}
+	var addresses []string
+	for _, ingStatus := range ingress.Status.LoadBalancer.Ingress {
+		if ingStatus.IP != "" {
+			addresses = append(addresses, ingStatus.IP)
+		}
+		if ingStatus.Hostname != "" {
+			addresses = append(addresses, ingStatus.Hostname)
+		}
+	}
+	if len(addresses) == 0 {
+		return "<pending>"
+	}
+	return strings.Join(addresses, ",")
+}
+
+func ingressHosts(ingress networkingv1.Ingress) []string {
+	hosts := []string{}
+	for _, rule := range ingress.Spec.Rules {
+		hosts = append(hosts, rule.Host)
+	}
+	return hosts
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func testWaitingPod(name, reason string, phase corev1.PodPhase, created time.Time) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+	if reason != "" {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name:  "app",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}},
+		}}
+	}
+	return pod
+}
+
+func TestComputeClusterHealth(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	replicas := int32(3)
+	in := statusData{
+		Nodes: []corev1.Node{
+			{ObjectMeta: metav1.ObjectMeta{Name: "ok"}, Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "bad"}, Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionUnknown}}}},
+		},
+		Pods: []corev1.Pod{
+			testWaitingPod("crash", "CrashLoopBackOff", corev1.PodRunning, now.Add(-time.Hour)),
+			testWaitingPod("pull", "ErrImagePull", corev1.PodPending, now.Add(-time.Hour)),
+			testWaitingPod("stuck", "", corev1.PodPending, now.Add(-10*time.Minute)),
+			testWaitingPod("fresh", "", corev1.PodPending, now.Add(-time.Minute)),
+			testWaitingPod("creating", "ContainerCreating", corev1.PodPending, now.Add(-time.Minute)),
+			testWaitingPod("fine", "", corev1.PodRunning, now.Add(-time.Hour)),
+		},
+		Deployments: []appsv1.Deployment{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "api"}, Spec: appsv1.DeploymentSpec{Replicas: &replicas}, Status: appsv1.DeploymentStatus{ReadyReplicas: 1}},
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "web"}, Status: appsv1.DeploymentStatus{ReadyReplicas: 1}},
+		},
+		DaemonSets: []appsv1.DaemonSet{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "aws-node"}, Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 2}},
+		},
+		Events: []clusterEvent{
+			{Type: "Warning", Kind: "Pod", Name: "crash", Reason: "BackOff", Count: 5, LastSeen: now.Add(-5 * time.Minute)},
+			{Type: "Warning", Kind: "Pod", Name: "old", Reason: "BackOff", Count: 1, LastSeen: now.Add(-3 * time.Hour)},
+			{Type: "Normal", Kind: "Pod", Name: "fine", Reason: "Pulled", Count: 1, LastSeen: now},
+		},
+	}
+
+	h := computeClusterHealth(in, healthOptions{PendingThreshold: 5 * time.Minute, WarningWindow: time.Hour}, now)
+	want := map[string]int{
+		ruleNodesNotReady.name:   1,
+		ruleCrashLoop.name:       1,
+		ruleImagePull.name:       1,
+		rulePendingPods.name:     1,
+		ruleMissingReplicas.name: 2,
+		ruleRecentWarnings.name:  1,
+	}
+	for _, c := range h.Checks {
+		if c.Count != want[c.Name] {
+			t.Errorf("check %q: expected count %d, got %d (%v)", c.Name, want[c.Name], c.Count, c.Objects)
+		}
+	}
+	// 100 - 10 (nodo) - 5 (crash) - 5 (pull) - 3 (pending) - 10 (workloads) - 1 (warning)
+	if h.Score != 66 {
+		t.Errorf("expected score 66, got %d", h.Score)
+	}
+}
+
+func TestComputeClusterHealth_PenaltyIsCapped(t *testing.T) {
+	now := time.Now()
+	var pods []corev1.Pod
+	for i := 0; i < 50; i++ {
+		pods = append(pods, testWaitingPod(fmt.Sprintf("pod-%d", i), "CrashLoopBackOff", corev1.PodRunning, now))
+	}
+	h := computeClusterHealth(statusData{Pods: pods}, healthOptions{PendingThreshold: time.Minute, WarningWindow: time.Hour}, now)
+	if h.Score != 100-ruleCrashLoop.maxWeight {
+		t.Errorf("expected penalty capped at %d, got score %d", ruleCrashLoop.maxWeight, h.Score)
+	}
+}
+
+func TestPodDisplayStatus(t *testing.T) {
+	now := time.Now()
+	if got := podDisplayStatus(testWaitingPod("a", "CrashLoopBackOff", corev1.PodRunning, now)); got != "CrashLoopBackOff" {
+		t.Errorf("expected CrashLoopBackOff, got %q", got)
+	}
+	if got := podDisplayStatus(testWaitingPod("b", "", corev1.PodRunning, now)); got != "Running" {
+		t.Errorf("expected Running, got %q", got)
+	}
+	evicted := corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"}}
+	if got := podDisplayStatus(evicted); got != "Evicted" {
+		t.Errorf("expected Evicted, got %q", got)
+	}
+	oom := corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{{
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+	}}}}
+	if got := podDisplayStatus(oom); got != "OOMKilled" {
+		t.Errorf("expected OOMKilled, got %q", got)
+	}
+}
+
+func TestCollectStatusData_AggregatesPartialErrors(t *testing.T) {
+	clientset := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "api"}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "db"}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "data"}},
+	)
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Group: "networking.k8s.io", Resource: "ingresses"}, "", errors.New("rbac"))
+	clientset.PrependReactor("list", "ingresses", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, forbidden
+	})
+
+	data, errs := collectStatusData(context.Background(), clientset, "prod", 3)
+	if len(data.Pods) != 1 || len(data.StatefulSets) != 1 || len(data.PVCs) != 1 {
+		t.Errorf("unexpected data: pods=%d statefulsets=%d pvcs=%d", len(data.Pods), len(data.StatefulSets), len(data.PVCs))
+	}
+	if len(errs) != 1 || errs[0].Resource != "ingresses" {
+		t.Fatalf("expected a single ingresses error, got %+v", errs)
+	}
+	if got := describeCollectError(errs[0].Err); got != "sin permisos (RBAC forbidden)" {
+		t.Errorf("unexpected error description %q", got)
+	}
+	if failed := failedResources(errs); !failed["ingresses"] || failed["pods"] {
+		t.Errorf("unexpected failed resources: %v", failed)
+	}
+}
+
+func TestBuildStatusReport_NullForFailedResources(t *testing.T) {
+	data := statusData{
+		Pods: []corev1.Pod{testWaitingPod("crash", "CrashLoopBackOff", corev1.PodRunning, time.Now())},
+		Services: []corev1.Service{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "web"},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeLoadBalancer,
+				Ports: []corev1.ServicePort{{Port: 80, NodePort: 30080, Protocol: corev1.ProtocolTCP}},
+			},
+		}},
+	}
+	errs := []collectError{{Resource: "ingresses", Err: errors.New("boom")}}
+
+	r := buildStatusReport("prod", data, clusterHealth{Score: 95}, errs)
+	if r.Kind != "StatusReport" || r.Namespace != "prod" || r.Health.Score != 95 {
+		t.Errorf("unexpected header: %+v", r.outputHeader)
+	}
+	if len(r.Pods) != 1 || r.Pods[0].Status != "CrashLoopBackOff" || r.Pods[0].Phase != "Running" {
+		t.Errorf("unexpected pods: %+v", r.Pods)
+	}
+	if r.Deployments == nil || len(r.Deployments) != 0 {
+		t.Errorf("expected an empty, non-null deployments list")
+	}
+	if r.Ingresses != nil {
+		t.Errorf("expected null ingresses when listing failed")
+	}
+	if len(r.Errors) != 1 || r.Errors[0].Resource != "ingresses" || r.Errors[0].Message != "boom" {
+		t.Errorf("unexpected errors: %+v", r.Errors)
+	}
+	svc := r.Services[0]
+	if svc.ExternalIP != "<pending>" || len(svc.Ports) != 1 || svc.Ports[0] != "80:30080/TCP" {
+		t.Errorf("unexpected service summary: %+v", svc)
+	}
+}
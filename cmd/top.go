@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// topCmd representa el comando 'monitor top'
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Muestra el consumo de recursos (CPU/memoria)",
+	Long: `Muestra el consumo actual de CPU y memoria obtenido del servidor de métricas,
+comparado con los requests y limits declarados.
+
+Requiere metrics-server instalado en el clúster.`,
+}
+
+func init() {
+	monitorCmd.AddCommand(topCmd)
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+// Variables para las flags de 'top pods'
+var (
+	topPodsNamespace     string
+	topPodsAllNamespaces bool
+	topPodsSelector      string
+	topPodsContainers    bool
+	topPodsSortBy        string
+	topPodsMemThreshold  int
+)
+
+// podUsage representa el consumo de un pod o de uno de sus contenedores.
+type podUsage struct {
+	Namespace string
+	Pod       string
+	Container string // vacío en la vista agregada por pod
+	CPUUsage  int64  // milicores
+	MemUsage  int64  // bytes
+	resourceTotals
+	// containers guarda el uso de cada contenedor en la vista agregada por pod: los
+	// límites se aplican por contenedor y la suma del pod no los representa.
+	containers []podUsage
+}
+
+// topPodsCmd representa el comando 'monitor top pods'
+var topPodsCmd = &cobra.Command{
+	Use:     "pods",
+	Aliases: []string{"po"},
+	Short:   "Muestra el consumo de CPU/memoria de pods y contenedores",
+	Long: `Muestra el consumo de CPU y memoria de cada pod (o de cada contenedor con
+--containers) junto a sus requests y limits, y el porcentaje que representa el uso
+sobre cada uno. Los contenedores cerca de su límite de memoria se marcan en la
+columna ALERTA.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sortBy := strings.ToLower(topPodsSortBy)
+		if sortBy != "" && sortBy != "cpu" && sortBy != "memory" {
+			return fmt.Errorf("--sort-by debe ser 'cpu' o 'memory'")
+		}
+
+		clients, err := GetKubeClients()
+		if err != nil {
+			return fmt.Errorf("creando clientes de Kubernetes: %w", err)
+		}
+		if clients.Metrics == nil {
+			return fmt.Errorf("el cliente de métricas no está disponible; ¿está instalado metrics-server?")
+		}
+
+		effectiveNamespace := GetEffectiveNamespace(topPodsNamespace, topPodsAllNamespaces, "default", false)
+		if topPodsAllNamespaces {
+			effectiveNamespace = ""
+		}
+		listOptions := metav1.ListOptions{LabelSelector: topPodsSelector}
+
+		if Verbose {
+			fmt.Printf("DEBUG: Obteniendo métricas de pods en namespace '%s' con selector '%s'\n", effectiveNamespace, topPodsSelector)
+		}
+		podMetrics, err := clients.Metrics.MetricsV1beta1().PodMetricses(effectiveNamespace).List(context.TODO(), listOptions)
+		if err != nil {
+			return fmt.Errorf("error obteniendo métricas de pods: %w", err)
+		}
+		pods, err := clients.Core.CoreV1().Pods(effectiveNamespace).List(context.TODO(), listOptions)
+		if err != nil {
+			return fmt.Errorf("error listando pods: %w", err)
+		}
+
+		usages := buildPodUsages(pods.Items, podMetrics.Items, topPodsContainers)
+		if len(usages) == 0 {
+			fmt.Fprintln(os.Stdout, "No se encontraron métricas de pods.")
+			return nil
+		}
+		sortPodUsages(usages, sortBy)
+
+		headers := []string{"NAMESPACE", "NAME"}
+		if topPodsContainers {
+			headers = append(headers, "CONTAINER")
+		}
+		headers = append(headers,
+			"CPU", "CPU REQ", "%CPU REQ", "CPU LIM", "%CPU LIM",
+			"MEM", "MEM REQ", "%MEM REQ", "MEM LIM", "%MEM LIM", "ALERTA")
+
+		rows := make([][]string, 0, len(usages))
+		nearLimit := 0
+		for _, u := range usages {
+			row := []string{u.Namespace, u.Pod}
+			if topPodsContainers {
+				row = append(row, u.Container)
+			}
+			alert := ""
+			if isNearMemoryLimit(u, topPodsMemThreshold) {
+				alert = fmt.Sprintf("MEM >= %d%% LÍMITE", topPodsMemThreshold)
+				nearLimit++
+			}
+			row = append(row,
+				formatMilliCPU(u.CPUUsage), formatMilliCPU(u.CPURequest), formatPercent(u.CPUUsage, u.CPURequest),
+				formatMilliCPU(u.CPULimit), formatPercent(u.CPUUsage, u.CPULimit),
+				formatBytes(u.MemUsage), formatBytes(u.MemRequest), formatPercent(u.MemUsage, u.MemRequest),
+				formatBytes(u.MemLimit), formatPercent(u.MemUsage, u.MemLimit),
+				alert)
+			rows = append(rows, row)
+		}
+		PrintBasicTable(headers, rows)
+		if nearLimit > 0 {
+			fmt.Fprintf(os.Stdout, "%d %s cerca de su límite de memoria (>= %d%%).\n", nearLimit, pluralize(nearLimit, "elemento", "elementos"), topPodsMemThreshold)
+		}
+		return nil
+	},
+}
+
+func init() {
+	topCmd.AddCommand(topPodsCmd)
+
+	topPodsCmd.Flags().StringVarP(&topPodsNamespace, "namespace", "n", "", "Namespace (opcional)")
+	topPodsCmd.Flags().BoolVarP(&topPodsAllNamespaces, "all-namespaces", "A", false, "Mostrar pods de todos los namespaces")
+	topPodsCmd.Flags().StringVarP(&topPodsSelector, "selector", "l", "", "Selector (label query) para filtrar pods. Ej: app=mi-app")
+	topPodsCmd.Flags().BoolVar(&topPodsContainers, "containers", false, "Mostrar una fila por contenedor")
+	topPodsCmd.Flags().StringVar(&topPodsSortBy, "sort-by", "", "Ordenar por consumo: cpu o memory")
+	topPodsCmd.Flags().IntVar(&topPodsMemThreshold, "memory-threshold", 90, "Porcentaje del límite de memoria a partir del cual se marca una alerta")
+}
+
+// buildPodUsages combina las métricas con los requests/limits de los pods. Con
+// perContainer devuelve una entrada por contenedor; si no, una por pod.
+func buildPodUsages(pods []corev1.Pod, metrics []metricsv1beta1.PodMetrics, perContainer bool) []podUsage {
+	podsByKey := make(map[string]corev1.Pod, len(pods))
+	for _, p := range pods {
+		podsByKey[p.Namespace+"/"+p.Name] = p
+	}
+
+	var result []podUsage
+	for _, m := range metrics {
+		pod, hasPod := podsByKey[m.Namespace+"/"+m.Name]
+		specs := make(map[string]corev1.Container)
+		if hasPod {
+			for _, c := range pod.Spec.Containers {
+				specs[c.Name] = c
+			}
+		}
+
+		podTotal := podUsage{Namespace: m.Namespace, Pod: m.Name}
+		if hasPod {
+			podTotal.resourceTotals = podResources(pod)
+		}
+		for _, cm := range m.Containers {
+			cpu := cm.Usage[corev1.ResourceCPU]
+			mem := cm.Usage[corev1.ResourceMemory]
+			podTotal.CPUUsage += cpu.MilliValue()
+			podTotal.MemUsage += mem.Value()
+			u := podUsage{
+				Namespace: m.Namespace, Pod: m.Name, Container: cm.Name,
+				CPUUsage: cpu.MilliValue(), MemUsage: mem.Value(),
+			}
+			if spec, ok := specs[cm.Name]; ok {
+				u.resourceTotals = containerResources(spec)
+			}
+			if perContainer {
+				result = append(result, u)
+			} else {
+				podTotal.containers = append(podTotal.containers, u)
+			}
+		}
+		if !perContainer {
+			result = append(result, podTotal)
+		}
+	}
+	return result
+}
+
+// sortPodUsages ordena por consumo descendente de cpu o memory; sin criterio,
+// por namespace y nombre.
+func sortPodUsages(usages []podUsage, sortBy string) {
+	sort.SliceStable(usages, func(i, j int) bool {
+		switch sortBy {
+		case "cpu":
+			return usages[i].CPUUsage > usages[j].CPUUsage
+		case "memory":
+			return usages[i].MemUsage > usages[j].MemUsage
+		}
+		if usages[i].Namespace != usages[j].Namespace {
+			return usages[i].Namespace < usages[j].Namespace
+		}
+		if usages[i].Pod != usages[j].Pod {
+			return usages[i].Pod < usages[j].Pod
+		}
+		return usages[i].Container < usages[j].Container
+	})
+}
+
+// isNearMemoryLimit indica si el uso de memoria alcanza el porcentaje indicado del límite.
+// En la vista por pod basta con que lo alcance uno de sus contenedores.
+func isNearMemoryLimit(u podUsage, thresholdPercent int) bool {
+	if len(u.containers) > 0 {
+		for _, c := range u.containers {
+			if isNearMemoryLimit(c, thresholdPercent) {
+				return true
+			}
+		}
+		return false
+	}
+	if u.MemLimit <= 0 {
+		return false
+	}
+	return u.MemUsage*100 >= u.MemLimit*int64(thresholdPercent)
+}
+
+// pluralize devuelve singular o plural según n.
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+func testContainer(name, cpuReq, cpuLim, memReq, memLim string) corev1.Container {
+	c := corev1.Container{Name: name, Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{}, Limits: corev1.ResourceList{},
+	}}
+	if cpuReq != "" {
+		c.Resources.Requests[corev1.ResourceCPU] = resource.MustParse(cpuReq)
+	}
+	if cpuLim != "" {
+		c.Resources.Limits[corev1.ResourceCPU] = resource.MustParse(cpuLim)
+	}
+	if memReq != "" {
+		c.Resources.Requests[corev1.ResourceMemory] = resource.MustParse(memReq)
+	}
+	if memLim != "" {
+		c.Resources.Limits[corev1.ResourceMemory] = resource.MustParse(memLim)
+	}
+	return c
+}
+
+func testContainerMetrics(name, cpu, mem string) metricsv1beta1.ContainerMetrics {
+	return metricsv1beta1.ContainerMetrics{Name: name, Usage: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(mem),
+	}}
+}
+
+func TestBuildPodUsages(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "payments"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			testContainer("app", "250m", "500m", "256Mi", "512Mi"),
+			testContainer("sidecar", "50m", "", "64Mi", "128Mi"),
+		}},
+	}
+	metrics := metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "payments"},
+		Containers: []metricsv1beta1.ContainerMetrics{
+			testContainerMetrics("app", "100m", "480Mi"),
+			testContainerMetrics("sidecar", "10m", "20Mi"),
+		},
+	}
+
+	byPod := buildPodUsages([]corev1.Pod{pod}, []metricsv1beta1.PodMetrics{metrics}, false)
+	if len(byPod) != 1 {
+		t.Fatalf("expected 1 pod row, got %d", len(byPod))
+	}
+	u := byPod[0]
+	if u.CPUUsage != 110 || u.CPURequest != 300 || u.CPULimit != 0 {
+		t.Errorf("unexpected pod CPU totals: %+v", u)
+	}
+	if u.MemLimit != 640*1024*1024 {
+		t.Errorf("expected pod memory limit 640Mi, got %d", u.MemLimit)
+	}
+
+	byContainer := buildPodUsages([]corev1.Pod{pod}, []metricsv1beta1.PodMetrics{metrics}, true)
+	if len(byContainer) != 2 {
+		t.Fatalf("expected 2 container rows, got %d", len(byContainer))
+	}
+	sortPodUsages(byContainer, "memory")
+	app := byContainer[0]
+	if app.Container != "app" || !isNearMemoryLimit(app, 90) {
+		t.Errorf("expected app container near memory limit, got %+v", app)
+	}
+	if isNearMemoryLimit(byContainer[1], 90) {
+		t.Error("sidecar should not be near its memory limit")
+	}
+}
+
+func TestPodRowNearMemoryLimitPerContainer(t *testing.T) {
+	tests := []struct {
+		name       string
+		containers []corev1.Container
+		want       bool
+	}{
+		// Sin límite en el sidecar el límite del pod es 0, pero app está al 94%.
+		{"sidecar sin límite", []corev1.Container{testContainer("app", "", "", "", "512Mi"), testContainer("sidecar", "", "", "", "")}, true},
+		// El pod suma 600Mi de 1152Mi (52%), pero el sidecar está al 94% del suyo.
+		{"sidecar al límite", []corev1.Container{testContainer("app", "", "", "", "1Gi"), testContainer("sidecar", "", "", "", "128Mi")}, true},
+		{"ninguno al límite", []corev1.Container{testContainer("app", "", "", "", "1Gi"), testContainer("sidecar", "", "", "", "256Mi")}, false},
+	}
+	metrics := metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "payments"},
+		Containers: []metricsv1beta1.ContainerMetrics{
+			testContainerMetrics("app", "100m", "480Mi"),
+			testContainerMetrics("sidecar", "10m", "120Mi"),
+		},
+	}
+	for _, tt := range tests {
+		pod := corev1.Pod{ObjectMeta: metrics.ObjectMeta, Spec: corev1.PodSpec{Containers: tt.containers}}
+		byPod := buildPodUsages([]corev1.Pod{pod}, []metricsv1beta1.PodMetrics{metrics}, false)
+		if got := isNearMemoryLimit(byPod[0], 90); got != tt.want {
+			t.Errorf("%s: isNearMemoryLimit = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatHelpers(t *testing.T) {
+	if got := formatPercent(50, 200); got != "25%" {
+		t.Errorf("formatPercent = %q", got)
+	}
+	if got := formatPercent(50, 0); got != "N/A" {
+		t.Errorf("formatPercent with zero base = %q", got)
+	}
+	if got := formatBytes(512 * 1024 * 1024); got != "512Mi" {
+		t.Errorf("formatBytes = %q", got)
+	}
+	if got := formatMilliCPU(1500); got != "1500m" {
+		t.Errorf("formatMilliCPU = %q", got)
+	}
+}
@@ -123,3 +123,30 @@ func PrintBasicTable(headers []string, rows [][]string) {
 	}
 	fmt.Println()
 }
+
+// Códigos ANSI usados para resaltar problemas en la salida de texto.
+const (
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorGreen  = "\033[32m"
+	colorReset  = "\033[0m"
+)
+
+// colorEnabled indica si se debe colorear la salida: solo cuando stdout es una
+// terminal y no se ha definido NO_COLOR (https://no-color.org).
+func colorEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize envuelve s con el color indicado si la salida admite colores.
+// No se debe usar dentro de PrintBasicTable, que calcula anchos con len().
+func colorize(s, color string) string {
+	if !colorEnabled() {
+		return s
+	}
+	return color + s + colorReset
+}
@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+var versionsOutputFormat string
+
+// Estados de desfase de versión de un componente respecto al API server.
+const (
+	versionStatusOK          = "OK"
+	versionStatusBehind      = "ATRASADO"
+	versionStatusUnsupported = "NO SOPORTADO"
+	versionStatusUnknown     = "DESCONOCIDO"
+)
+
+// nodeVersionInfo contiene las versiones de los componentes de un nodo.
+type nodeVersionInfo struct {
+	Name             string   `json:"name"`
+	Nodegroup        string   `json:"nodegroup"`
+	KubeletVersion   string   `json:"kubeletVersion"`
+	KubeletSkew      int      `json:"kubeletSkew"`
+	KubeProxyVersion string   `json:"kubeProxyVersion,omitempty"`
+	KubeProxySkew    int      `json:"kubeProxySkew"`
+	ContainerRuntime string   `json:"containerRuntime"`
+	Status           string   `json:"status"`
+	Issues           []string `json:"issues,omitempty"`
+}
+
+// versionGroup agrupa los nodos de un nodegroup que comparten versión de kubelet.
+type versionGroup struct {
+	Nodegroup         string         `json:"nodegroup"`
+	KubeletVersion    string         `json:"kubeletVersion"`
+	Skew              int            `json:"skew"`
+	Status            string         `json:"status"`
+	Nodes             int            `json:"nodes"`
+	KubeProxyVersions map[string]int `json:"kubeProxyVersions"`
+	Runtimes          map[string]int `json:"containerRuntimes"`
+}
+
+// versionReport es el informe completo de desfase de versiones del clúster.
+type versionReport struct {
+	APIServerVersion string            `json:"apiServerVersion"`
+	MaxSupportedSkew int               `json:"maxSupportedSkew"`
+	UpgradedNodes    int               `json:"upgradedNodes"`
+	TotalNodes       int               `json:"totalNodes"`
+	Unsupported      int               `json:"unsupportedNodes"`
+	Nodes            []nodeVersionInfo `json:"nodes"`
+	Groups           []versionGroup    `json:"groups"`
+}
+
+var versionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "Compara la versión del control plane con la de los nodos.",
+	Long: `El comando versions compara la versión del API server (obtenida por discovery)
+con la versión del kubelet, kube-proxy y el container runtime de cada nodo.
+
+Señala los componentes que superan la ventana de desfase soportada por
+Kubernetes (kubelet y kube-proxy pueden ir hasta 3 versiones menores por detrás
+del API server desde la 1.28, 2 en versiones anteriores, y nunca por delante)
+y agrupa los nodos por nodegroup y versión para seguir el progreso de una
+actualización de EKS.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		outputLower := strings.ToLower(versionsOutputFormat)
+		switch outputLower {
+		case "", "wide", "json", "yaml":
+		default:
+			fmt.Fprintf(os.Stderr, "Error: formato de salida no soportado '%s' (usa wide, json o yaml).\n", versionsOutputFormat)
+			os.Exit(1)
+		}
+
+		clients, err := GetKubeClients()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creando clientes de Kubernetes: %v\n", err)
+			os.Exit(1)
+		}
+
+		info := os.Stdout
+		if isStructuredOutput(outputLower) {
+			info = os.Stderr
+		}
+		fmt.Fprintln(info, "Recuperando versiones del control plane y de los nodos...")
+
+		serverVersion, err := clients.Core.Discovery().ServerVersion()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error obteniendo la versión del API server: %v\n", err)
+			os.Exit(1)
+		}
+
+		nodes, err := clients.Core.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listando nodos: %v\n", err)
+			os.Exit(1)
+		}
+
+		// En clústeres recientes NodeInfo.KubeProxyVersion está vacío: se usa la imagen
+		// del pod de kube-proxy que corre en cada nodo.
+		var proxyPods []corev1.Pod
+		pods, err := clients.Core.CoreV1().Pods("kube-system").List(context.TODO(), metav1.ListOptions{LabelSelector: "k8s-app=kube-proxy"})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Advertencia: no se pudieron listar los pods de kube-proxy: %v\n", err)
+		} else {
+			proxyPods = pods.Items
+		}
+
+		report, err := buildVersionReport(serverVersion.GitVersion, nodes.Items, proxyPods)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if isStructuredOutput(outputLower) {
+			doc := struct {
+				outputHeader
+				versionReport
+			}{newOutputHeader("VersionReport"), report}
+			if err := writeStructuredOutput(os.Stdout, outputLower, doc); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		printVersionReport(report, outputLower == "wide")
+	},
+}
+
+func init() {
+	monitorCmd.AddCommand(versionsCmd)
+	versionsCmd.Flags().StringVarP(&versionsOutputFormat, "output", "o", "", "Formato de salida. Soportado: wide, json, yaml")
+}
+
+// maxSupportedSkew devuelve cuántas versiones menores puede ir el kubelet por detrás
+// del API server según la política de desfase de Kubernetes.
+func maxSupportedSkew(apiServer *version.Version) int {
+	if apiServer.Major() == 1 && apiServer.Minor() < 28 {
+		return 2
+	}
+	return 3
+}
+
+// evaluateSkew compara la versión de un componente con la del API server y devuelve
+// cuántas versiones menores va por detrás (negativo si va por delante) y su estado.
+func evaluateSkew(apiServer *version.Version, component string, maxSkew int) (int, string) {
+	v, err := version.ParseGeneric(component)
+	if err != nil {
+		return 0, versionStatusUnknown
+	}
+	skew := int(apiServer.Minor()) - int(v.Minor())
+	switch {
+	case v.Major() != apiServer.Major() || skew < 0 || skew > maxSkew:
+		return skew, versionStatusUnsupported
+	case skew > 0:
+		return skew, versionStatusBehind
+	}
+	return skew, versionStatusOK
+}
+
+// worseVersionStatus devuelve el estado más grave de los dos.
+func worseVersionStatus(a, b string) string {
+	rank := map[string]int{versionStatusOK: 0, versionStatusBehind: 1, versionStatusUnknown: 2, versionStatusUnsupported: 3}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// imageTag devuelve la etiqueta de una referencia de imagen (ej. "v1.29.0-eksbuild.1").
+func imageTag(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// buildVersionReport calcula el desfase de cada nodo frente al API server y agrupa
+// los nodos por nodegroup y versión de kubelet.
+func buildVersionReport(apiServerVersion string, nodes []corev1.Node, proxyPods []corev1.Pod) (versionReport, error) {
+	apiServer, err := version.ParseGeneric(apiServerVersion)
+	if err != nil {
+		return versionReport{}, fmt.Errorf("versión del API server no válida '%s': %w", apiServerVersion, err)
+	}
+	maxSkew := maxSupportedSkew(apiServer)
+	report := versionReport{
+		APIServerVersion: apiServerVersion,
+		MaxSupportedSkew: maxSkew,
+		TotalNodes:       len(nodes),
+		Nodes:            []nodeVersionInfo{},
+		Groups:           []versionGroup{},
+	}
+
+	proxyByNode := map[string]string{}
+	for _, p := range proxyPods {
+		for _, c := range p.Spec.Containers {
+			if c.Name == "kube-proxy" {
+				proxyByNode[p.Spec.NodeName] = imageTag(c.Image)
+			}
+		}
+	}
+
+	groups := map[string]*versionGroup{}
+	for _, node := range nodes {
+		ni := node.Status.NodeInfo
+		n := nodeVersionInfo{
+			Name:             node.Name,
+			Nodegroup:        valueOrNone(getEKSNodeInfo(node).groupName()),
+			KubeletVersion:   ni.KubeletVersion,
+			KubeProxyVersion: proxyByNode[node.Name],
+			ContainerRuntime: ni.ContainerRuntimeVersion,
+		}
+		if n.KubeProxyVersion == "" {
+			// Campo obsoleto que solo informan los kubelet antiguos.
+			n.KubeProxyVersion = ni.KubeProxyVersion
+		}
+
+		var kubeletStatus string
+		n.KubeletSkew, kubeletStatus = evaluateSkew(apiServer, n.KubeletVersion, maxSkew)
+		n.Status = kubeletStatus
+		if kubeletStatus == versionStatusUnsupported {
+			n.Issues = append(n.Issues, describeSkew("kubelet", n.KubeletSkew, maxSkew))
+		}
+		if n.KubeProxyVersion != "" {
+			var proxyStatus string
+			n.KubeProxySkew, proxyStatus = evaluateSkew(apiServer, n.KubeProxyVersion, maxSkew)
+			n.Status = worseVersionStatus(n.Status, proxyStatus)
+			if proxyStatus == versionStatusUnsupported {
+				n.Issues = append(n.Issues, describeSkew("kube-proxy", n.KubeProxySkew, maxSkew))
+			}
+		}
+		if n.Status == versionStatusOK {
+			report.UpgradedNodes++
+		}
+		if n.Status == versionStatusUnsupported {
+			report.Unsupported++
+		}
+		report.Nodes = append(report.Nodes, n)
+
+		key := n.Nodegroup + "|" + n.KubeletVersion
+		g, ok := groups[key]
+		if !ok {
+			g = &versionGroup{
+				Nodegroup:         n.Nodegroup,
+				KubeletVersion:    n.KubeletVersion,
+				Skew:              n.KubeletSkew,
+				Status:            kubeletStatus,
+				KubeProxyVersions: map[string]int{},
+				Runtimes:          map[string]int{},
+			}
+			groups[key] = g
+		}
+		g.Nodes++
+		g.KubeProxyVersions[valueOrNone(n.KubeProxyVersion)]++
+		g.Runtimes[valueOrNone(n.ContainerRuntime)]++
+	}
+
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		if report.Nodes[i].KubeletSkew != report.Nodes[j].KubeletSkew {
+			return report.Nodes[i].KubeletSkew > report.Nodes[j].KubeletSkew
+		}
+		return report.Nodes[i].Name < report.Nodes[j].Name
+	})
+	for _, g := range groups {
+		report.Groups = append(report.Groups, *g)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].Nodegroup != report.Groups[j].Nodegroup {
+			return report.Groups[i].Nodegroup < report.Groups[j].Nodegroup
+		}
+		return report.Groups[i].KubeletVersion < report.Groups[j].KubeletVersion
+	})
+	return report, nil
+}
+
+// describeSkew explica por qué la versión de un componente no está soportada.
+func describeSkew(component string, skew, maxSkew int) string {
+	if skew < 0 {
+		return fmt.Sprintf("%s es %d versión(es) menor(es) más nuevo que el API server", component, -skew)
+	}
+	return fmt.Sprintf("%s va %d versiones menores por detrás (máximo soportado: %d)", component, skew, maxSkew)
+}
+
+// printVersionReport imprime el resumen por nodegroup y, con wide, el detalle por nodo.
+func printVersionReport(r versionReport, wide bool) {
+	fmt.Fprintf(os.Stdout, "\nAPI server: %s (desfase máximo soportado: %d versiones menores)\n", r.APIServerVersion, r.MaxSupportedSkew)
+	fmt.Fprintf(os.Stdout, "Nodos en la versión del control plane: %d/%d\n", r.UpgradedNodes, r.TotalNodes)
+
+	fmt.Fprintln(os.Stdout, "\n--- Versiones por nodegroup ---")
+	rows := make([][]string, 0, len(r.Groups))
+	for _, g := range r.Groups {
+		rows = append(rows, []string{
+			g.Nodegroup, g.KubeletVersion, fmt.Sprintf("%d", g.Nodes), fmt.Sprintf("%d", g.Skew), g.Status,
+			formatCountMap(g.KubeProxyVersions, 3), formatCountMap(g.Runtimes, 3),
+		})
+	}
+	PrintBasicTable([]string{"NODEGROUP", "KUBELET", "NODOS", "DESFASE", "ESTADO", "KUBE-PROXY", "RUNTIME"}, rows)
+
+	if wide {
+		fmt.Fprintln(os.Stdout, "--- Versiones por nodo ---")
+		nodeRows := make([][]string, 0, len(r.Nodes))
+		for _, n := range r.Nodes {
+			nodeRows = append(nodeRows, []string{
+				n.Name, n.Nodegroup, n.KubeletVersion, valueOrNone(n.KubeProxyVersion), n.ContainerRuntime, n.Status,
+			})
+		}
+		PrintBasicTable([]string{"NOMBRE", "NODEGROUP", "KUBELET", "KUBE-PROXY", "RUNTIME", "ESTADO"}, nodeRows)
+	}
+
+	if r.Unsupported == 0 {
+		fmt.Fprintln(os.Stdout, "Todos los nodos están dentro de la ventana de desfase soportada.")
+		return
+	}
+	fmt.Fprintf(os.Stdout, "%d %s fuera de la ventana de desfase soportada:\n", r.Unsupported, pluralize(r.Unsupported, "nodo", "nodos"))
+	for _, n := range r.Nodes {
+		for _, issue := range n.Issues {
+			fmt.Fprintf(os.Stdout, "  - %s: %s\n", n.Name, issue)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+func testVersionNode(name, nodegroup, kubelet string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{labelEKSNodegroup: nodegroup}},
+		Status: corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{
+			KubeletVersion:          kubelet,
+			ContainerRuntimeVersion: "containerd://1.7.11",
+		}},
+	}
+}
+
+func TestEvaluateSkew(t *testing.T) {
+	api := version.MustParseGeneric("v1.30.2-eks-db838b0")
+	tests := []struct {
+		component string
+		skew      int
+		status    string
+	}{
+		{"v1.30.0-eks-036c24b", 0, versionStatusOK},
+		{"v1.28.5-eks-5e0fdde", 2, versionStatusBehind},
+		{"v1.27.9", 3, versionStatusBehind},
+		{"v1.26.1", 4, versionStatusUnsupported},
+		{"v1.31.0", -1, versionStatusUnsupported},
+		{"unknown", 0, versionStatusUnknown},
+	}
+	for _, tt := range tests {
+		skew, status := evaluateSkew(api, tt.component, maxSupportedSkew(api))
+		if skew != tt.skew || status != tt.status {
+			t.Errorf("evaluateSkew(%q) = %d, %q; want %d, %q", tt.component, skew, status, tt.skew, tt.status)
+		}
+	}
+	if got := maxSupportedSkew(version.MustParseGeneric("v1.27.0")); got != 2 {
+		t.Errorf("expected skew window 2 before 1.28, got %d", got)
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	tests := map[string]string{
+		"602401143452.dkr.ecr.us-east-1.amazonaws.com/eks/kube-proxy:v1.29.0-minimal-eksbuild.1": "v1.29.0-minimal-eksbuild.1",
+		"registry:5000/kube-proxy":                  "",
+		"registry/kube-proxy:v1.30.0@sha256:abcdef": "v1.30.0",
+		"kube-proxy": "",
+	}
+	for image, want := range tests {
+		if got := imageTag(image); got != want {
+			t.Errorf("imageTag(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestBuildVersionReport(t *testing.T) {
+	nodes := []corev1.Node{
+		testVersionNode("a", "ng-new", "v1.30.1-eks-1"),
+		testVersionNode("b", "ng-new", "v1.30.1-eks-1"),
+		testVersionNode("c", "ng-old", "v1.26.4-eks-1"),
+	}
+	proxyPods := []corev1.Pod{{
+		Spec: corev1.PodSpec{NodeName: "a", Containers: []corev1.Container{{Name: "kube-proxy", Image: "eks/kube-proxy:v1.29.0-eksbuild.1"}}},
+	}}
+
+	r, err := buildVersionReport("v1.30.2-eks-db838b0", nodes, proxyPods)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.TotalNodes != 3 || r.UpgradedNodes != 1 || r.Unsupported != 1 {
+		t.Errorf("unexpected totals: upgraded=%d unsupported=%d total=%d", r.UpgradedNodes, r.Unsupported, r.TotalNodes)
+	}
+	// El nodo más atrasado aparece primero.
+	if r.Nodes[0].Name != "c" || r.Nodes[0].Status != versionStatusUnsupported || len(r.Nodes[0].Issues) != 1 {
+		t.Errorf("unexpected first node: %+v", r.Nodes[0])
+	}
+	// El kube-proxy atrasado degrada el estado del nodo aunque el kubelet esté al día.
+	for _, n := range r.Nodes {
+		if n.Name == "a" && (n.KubeProxyVersion != "v1.29.0-eksbuild.1" || n.Status != versionStatusBehind) {
+			t.Errorf("unexpected node a: %+v", n)
+		}
+	}
+	if len(r.Groups) != 2 || r.Groups[0].Nodegroup != "ng-new" || r.Groups[0].Nodes != 2 || r.Groups[0].Status != versionStatusOK {
+		t.Errorf("unexpected groups: %+v", r.Groups)
+	}
+
+	if _, err := buildVersionReport("garbage", nodes, nil); err == nil {
+		t.Errorf("expected error for an invalid API server version")
+	}
+}